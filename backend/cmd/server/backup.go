@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/conquista-ai/conquista-ai/internal/config"
+	"github.com/conquista-ai/conquista-ai/internal/database"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/conquista-ai/conquista-ai/internal/planning"
+	"github.com/conquista-ai/conquista-ai/internal/repositories"
+	"github.com/conquista-ai/conquista-ai/internal/services"
+)
+
+// runExport grava o backup completo em um arquivo ou na saída padrão
+func runExport(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	output := fs.String("o", "", "arquivo de saída (padrão: saída padrão)")
+	if err := fs.Parse(args); err != nil || fs.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	service, closeDB, err := backupService()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer closeDB()
+
+	backup, err := service.Export(context.Background())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "erro ao criar %s: %v\n", *output, err)
+			return 1
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if err := writeJSON(w, backup); err != nil {
+		fmt.Fprintf(os.Stderr, "erro ao gravar backup: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// runImport importa um arquivo gerado por export ("-" lê da entrada padrão) e mostra o
+// resumo da importação
+func runImport(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	strategy := fs.String("strategy", models.ImportSkip, "OKRs já existentes: skip, overwrite ou duplicate")
+	dryRun := fs.Bool("dry-run", false, "apenas simula a importação, sem gravar")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	var r io.Reader = os.Stdin
+	if path := fs.Arg(0); path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "erro ao abrir %s: %v\n", path, err)
+			return 1
+		}
+		defer file.Close()
+		r = file
+	}
+
+	var backup models.Backup
+	if err := json.NewDecoder(r).Decode(&backup); err != nil {
+		fmt.Fprintf(os.Stderr, "arquivo de backup inválido: %v\n", err)
+		return 1
+	}
+
+	service, closeDB, err := backupService()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer closeDB()
+
+	result, err := service.Import(context.Background(), &backup, *strategy, *dryRun)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if err := writeJSON(os.Stdout, result); err != nil {
+		return 1
+	}
+	return 0
+}
+
+// backupService conecta ao banco configurado e monta o serviço de backup
+func backupService() (*services.BackupService, func(), error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao carregar configurações: %w", err)
+	}
+
+	db, err := database.Connect(cfg.DatabaseURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao conectar ao banco: %w", err)
+	}
+
+	service := services.NewBackupService(repositories.NewBackupRepository(db), planning.SystemClock{})
+	return service, func() { db.Close() }, nil
+}
+
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -1,12 +1,27 @@
 package main
 
 import (
+	"fmt"
 	"log"
+	"os"
 
 	"github.com/conquista-ai/conquista-ai/internal/app"
+	"github.com/conquista-ai/conquista-ai/internal/config"
 )
 
+const usage = `Uso:
+  server                 inicia o servidor HTTP
+  server config print    mostra a configuração efetiva (segredos mascarados)
+  server export [-o arquivo]
+                         exporta todos os dados em JSON (padrão: saída padrão)
+  server import [-strategy skip|overwrite|duplicate] [-dry-run] arquivo
+                         importa um arquivo gerado por export ("-" lê da entrada padrão)`
+
 func main() {
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1:]))
+	}
+
 	application, err := app.NewApp()
 	if err != nil {
 		log.Fatalf("Erro ao inicializar aplicação: %v", err)
@@ -17,3 +32,20 @@ func main() {
 	}
 }
 
+// runCommand executa os subcomandos da linha de comando e retorna o código de saída
+func runCommand(args []string) int {
+	switch {
+	case len(args) == 2 && args[0] == "config" && args[1] == "print":
+		if err := config.Print(os.Stdout); err != nil {
+			return 1
+		}
+		return 0
+	case args[0] == "export":
+		return runExport(args[1:])
+	case args[0] == "import":
+		return runImport(args[1:])
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+}
@@ -33,6 +33,18 @@ Feature: Gerenciar OKRs
     Then a resposta deve ter status 200
     And a resposta deve conter apenas OKRs da categoria "Profissional"
 
+  Scenario: Paginar e ordenar OKRs
+    Given que o sistema está configurado
+    And existem OKRs cadastrados
+    When eu faço uma requisição GET para /api/v1/okrs?limit=1&sort=-completion_date
+    Then a resposta deve ter status 200
+    And a resposta deve conter uma lista de OKRs
+
+  Scenario: Rejeitar filtro inválido na listagem de OKRs
+    Given que o sistema está configurado
+    When eu faço uma requisição GET para /api/v1/okrs?category_id=abc
+    Then a resposta deve ter status 400
+
   Scenario: Atualizar OKR
     Given que o sistema está configurado
     And existe um OKR com objective "Aprender Golang"
@@ -54,3 +66,9 @@ Feature: Gerenciar OKRs
     Then a resposta deve ter status 200
     And Key Results devem ser gerados para o OKR
 
+
+  Scenario: Prévia da distribuição de prazos dos Key Results
+    Given que o sistema está configurado
+    And existe um OKR com objective "Aprender Golang"
+    When eu faço uma requisição POST para /api/v1/okrs/{id}/schedule
+    Then a resposta deve ter status 200
@@ -0,0 +1,21 @@
+Feature: Buscar conteúdo
+  Como um usuário do sistema
+  Eu quero buscar por texto em OKRs, Key Results, roadmaps e trilhas
+  Para que eu encontre rapidamente o que estou estudando
+
+  Scenario: Buscar por termo
+    Given que o sistema está configurado
+    And existe um OKR com objective "Aprender Kubernetes"
+    When eu faço uma requisição GET para /api/v1/search?q=kubernetes
+    Then a resposta deve ter status 200
+    And a resposta deve conter os resultados da busca
+
+  Scenario: Buscar apenas um tipo de conteúdo
+    Given que o sistema está configurado
+    When eu faço uma requisição GET para /api/v1/search?q=golang&types=okr,key_result
+    Then a resposta deve ter status 200
+
+  Scenario: Buscar sem termo
+    Given que o sistema está configurado
+    When eu faço uma requisição GET para /api/v1/search
+    Then a resposta deve ter status 400
@@ -76,8 +76,16 @@ func aRespostaDeveTerStatus(status int) error {
 
 func aRespostaDeveConterUmaListaDe(entity string) error {
 	var list []interface{}
-	if err := json.Unmarshal(ctx.body, &list); err != nil {
-		return fmt.Errorf("resposta não é uma lista: %v", err)
+	if err := json.Unmarshal(ctx.body, &list); err == nil {
+		return nil
+	}
+
+	// Listagens paginadas retornam um envelope com os itens em "items"
+	var page struct {
+		Items []interface{} `json:"items"`
+	}
+	if err := json.Unmarshal(ctx.body, &page); err != nil || page.Items == nil {
+		return fmt.Errorf("resposta não é uma lista nem uma página de %s", entity)
 	}
 	return nil
 }
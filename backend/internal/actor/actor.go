@@ -0,0 +1,32 @@
+// Package actor guarda no context.Context quem está realizando a operação, para que as
+// mudanças registradas no log de auditoria identifiquem o autor.
+package actor
+
+import "context"
+
+// Header é o cabeçalho HTTP opcional com o nome de quem faz a requisição
+const Header = "X-Actor"
+
+// Valores usados quando o autor não é informado: requisições HTTP sem o cabeçalho Header
+// e operações fora de requisições (jobs e linha de comando)
+const (
+	Anonymous = "anonymous"
+	System    = "system"
+)
+
+// MaxLength é o tamanho máximo aceito para o nome do autor
+const MaxLength = 100
+
+type contextKey struct{}
+
+func WithActor(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, contextKey{}, name)
+}
+
+// FromContext retorna o autor da operação ou System se não houver
+func FromContext(ctx context.Context) string {
+	if name, ok := ctx.Value(contextKey{}).(string); ok && name != "" {
+		return name
+	}
+	return System
+}
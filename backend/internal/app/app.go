@@ -1,26 +1,46 @@
 package app
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/conquista-ai/conquista-ai/internal/config"
 	"github.com/conquista-ai/conquista-ai/internal/database"
 	"github.com/conquista-ai/conquista-ai/internal/handlers"
+	"github.com/conquista-ai/conquista-ai/internal/health"
+	"github.com/conquista-ai/conquista-ai/internal/jobs"
+	"github.com/conquista-ai/conquista-ai/internal/logging"
+	"github.com/conquista-ai/conquista-ai/internal/metrics"
+	"github.com/conquista-ai/conquista-ai/internal/notifications"
+	"github.com/conquista-ai/conquista-ai/internal/planning"
 	"github.com/conquista-ai/conquista-ai/internal/repositories"
+	"github.com/conquista-ai/conquista-ai/internal/risk"
 	"github.com/conquista-ai/conquista-ai/internal/routes"
 	"github.com/conquista-ai/conquista-ai/internal/services"
+	"github.com/conquista-ai/conquista-ai/internal/telemetry"
+	"github.com/conquista-ai/conquista-ai/internal/webhooks"
 	spellbookClient "github.com/conquista-ai/conquista-ai/internal/services/spellbook"
 )
 
+// healthCacheTTL é o tempo durante o qual o resultado do readiness é reaproveitado
+const healthCacheTTL = 5 * time.Second
+
 type App struct {
 	Config  *config.Config
 	DB      *sql.DB
 	Router  *gin.Engine
+	Jobs    *jobs.Runner
+	Health  *health.Checker
+
+	shutdownTracing func(context.Context) error
 }
 
 func NewApp() (*App, error) {
@@ -29,6 +49,15 @@ func NewApp() (*App, error) {
 		return nil, fmt.Errorf("erro ao carregar configurações: %w", err)
 	}
 
+	if _, err := logging.Setup(cfg.Log.Level, cfg.Log.Format); err != nil {
+		return nil, fmt.Errorf("erro ao configurar logs: %w", err)
+	}
+
+	shutdownTracing, err := telemetry.Setup(context.Background(), cfg.Telemetry.OTLPEndpoint, cfg.Telemetry.OTLPInsecure)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao configurar tracing: %w", err)
+	}
+
 	db, err := database.Connect(cfg.DatabaseURL)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao conectar ao banco: %w", err)
@@ -40,49 +69,200 @@ func NewApp() (*App, error) {
 	keyResultRepo := repositories.NewKeyResultRepository(db)
 	roadmapRepo := repositories.NewRoadmapRepository(db)
 	educationalRoadmapRepo := repositories.NewEducationalRoadmapRepository(db)
-	educationalTrailRepo := repositories.NewEducationalTrailRepository(db)
+	educationalTrailRepo := repositories.NewEducationalTrailRepository(db, planning.SystemClock{})
+	searchRepo := repositories.NewSearchRepository(db)
+	studySettingsRepo := repositories.NewStudySettingsRepository(db)
+	calendarRepo := repositories.NewCalendarRepository(db, planning.SystemClock{})
+	riskRepo := repositories.NewRiskRepository(db)
+	notificationRepo := repositories.NewNotificationRepository(db)
+	webhookRepo := repositories.NewWebhookRepository(db)
+	studyRepo := repositories.NewStudyRepository(db)
+	dashboardRepo := repositories.NewDashboardRepository(db)
+	auditRepo := repositories.NewAuditRepository(db)
+	trashRepo := repositories.NewTrashRepository(db)
+	backupRepo := repositories.NewBackupRepository(db)
+	shareRepo := repositories.NewShareRepository(db)
+
+	// Métricas que dependem do banco (pool de conexões e gauges de negócio)
+	if err := metrics.Register(db, okrRepo); err != nil {
+		return nil, fmt.Errorf("erro ao registrar métricas: %w", err)
+	}
 
 	// Cliente Spellbook
-	spellbookClient := spellbookClient.NewClient(cfg.SpellbookAPIURL)
+	spellbookClient := spellbookClient.NewClient(cfg.SpellbookAPIURL, cfg.Spellbook.Timeout)
+
+	// Planejamento de prazos
+	scheduler, err := planning.NewScheduler(cfg.Planning.Strategy)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao configurar planejamento: %w", err)
+	}
+	planner := planning.NewPlanner(scheduler, planning.SystemClock{}, cfg.Planning)
+
+	// Avaliação de risco (tempo decorrido x progresso)
+	riskEngine := risk.NewEngine(cfg.Risk, planning.SystemClock{})
 
 	// Serviços
-	okrService := services.NewOKRService(okrRepo, keyResultRepo, categoryRepo, spellbookClient)
-	roadmapService := services.NewRoadmapService(roadmapRepo, educationalRoadmapRepo, educationalTrailRepo, keyResultRepo, okrRepo, spellbookClient)
+	riskService := services.NewRiskService(riskRepo, okrRepo, riskEngine)
+	okrService := services.NewOKRService(okrRepo, keyResultRepo, categoryRepo, spellbookClient, planner, riskService)
+	calendarService := services.NewCalendarService(calendarRepo, studySettingsRepo)
+	studyService := services.NewStudyService(studyRepo, studySettingsRepo, planning.SystemClock{})
+	dashboardService := services.NewDashboardService(dashboardRepo, planning.SystemClock{}, cfg.Dashboard.CacheTTL)
+	auditRetentionService := services.NewAuditRetentionService(auditRepo, planning.SystemClock{}, cfg.Audit.RetentionDays)
+	trashService := services.NewTrashService(trashRepo, planning.SystemClock{}, cfg.Trash.RetentionDays)
+	backupService := services.NewBackupService(backupRepo, planning.SystemClock{})
+	shareService := services.NewShareService(shareRepo, roadmapRepo, educationalTrailRepo, keyResultRepo, planning.SystemClock{})
+	roadmapService := services.NewRoadmapService(roadmapRepo, educationalRoadmapRepo, educationalTrailRepo, studySettingsRepo, keyResultRepo, okrRepo, spellbookClient, planner)
+
+	// Lembretes: caixa de entrada sempre disponível; e-mail apenas com SMTP configurado
+	notifiers := []notifications.Notifier{
+		notifications.NewInboxNotifier(notificationRepo),
+		notifications.NewWebhookNotifier(cfg.Notifications.WebhookTimeout),
+	}
+	if cfg.Notifications.SMTP.Host != "" {
+		notifiers = append(notifiers, notifications.NewSMTPNotifier(cfg.Notifications.SMTP))
+	}
+	reminderGenerator := notifications.NewGenerator(notificationRepo, okrRepo, planning.SystemClock{}, cfg.Notifications.DueSoonDays)
+	notificationService := notifications.NewService(notificationRepo, reminderGenerator, planning.SystemClock{},
+		cfg.Notifications.MaxAttempts, notifiers...)
+	webhookDispatcher := webhooks.NewDispatcher(webhookRepo, planning.SystemClock{}, cfg.Webhooks.Timeout, cfg.Webhooks.MaxAttempts)
 
 	// Handlers
 	categoryHandler := handlers.NewCategoryHandler(categoryRepo)
 	okrHandler := handlers.NewOKRHandler(okrService)
-	keyResultHandler := handlers.NewKeyResultHandler(keyResultRepo, okrRepo)
+	keyResultHandler := handlers.NewKeyResultHandler(keyResultRepo, okrRepo, planner)
 	roadmapHandler := handlers.NewRoadmapHandler(roadmapService)
+	searchHandler := handlers.NewSearchHandler(searchRepo)
+	studySettingsHandler := handlers.NewStudySettingsHandler(studySettingsRepo)
+	calendarHandler := handlers.NewCalendarHandler(calendarService)
+	reportHandler := handlers.NewReportHandler(riskService)
+	notificationHandler := handlers.NewNotificationHandler(notificationRepo, notificationService)
+	webhookHandler := handlers.NewWebhookHandler(webhookRepo, planning.SystemClock{})
+	studyHandler := handlers.NewStudyHandler(studyRepo, studyService)
+	dashboardHandler := handlers.NewDashboardHandler(dashboardService)
+	auditHandler := handlers.NewAuditHandler(auditRepo)
+	trashHandler := handlers.NewTrashHandler(trashService, okrRepo, keyResultRepo, roadmapRepo)
+	backupHandler := handlers.NewBackupHandler(backupService)
+	shareHandler := handlers.NewShareHandler(shareService)
+
+	// Probes de readiness: banco e Spellbook, com resultado em cache
+	checker := health.NewChecker(healthCacheTTL,
+		health.Check{Name: "database", Fn: db.PingContext},
+		health.Check{Name: "spellbook", Fn: spellbookClient.Ping},
+	)
+	healthHandler := handlers.NewHealthHandler(checker)
 
 	// Router
 	gin.SetMode(gin.ReleaseMode)
-	router := gin.Default()
+	router := gin.New()
+	router.Use(gin.Recovery())
 
-	routes.SetupRoutes(router, categoryHandler, okrHandler, keyResultHandler, roadmapHandler)
+	routes.SetupRoutes(router, categoryHandler, okrHandler, keyResultHandler, roadmapHandler, searchHandler, studySettingsHandler, calendarHandler, reportHandler, notificationHandler, webhookHandler, studyHandler, dashboardHandler, auditHandler, trashHandler, backupHandler, shareHandler, healthHandler, cfg.CORS.AllowedOrigins)
+
+	// Jobs em segundo plano
+	runner := jobs.NewRunner()
+	if cfg.Notifications.Enabled {
+		runner.Every("notifications", cfg.Notifications.Interval, notificationService.Run)
+	}
+	if cfg.Webhooks.Enabled {
+		runner.Every("webhooks", cfg.Webhooks.Interval, webhookDispatcher.Run)
+	}
+	if cfg.Audit.RetentionDays > 0 {
+		runner.Every("audit-retention", cfg.Audit.PurgeInterval, auditRetentionService.Run)
+	}
+	if cfg.Trash.RetentionDays > 0 {
+		runner.Every("trash-purge", cfg.Trash.PurgeInterval, trashService.Run)
+	}
 
 	return &App{
 		Config: cfg,
 		DB:     db,
 		Router: router,
+		Jobs:   runner,
+		Health: checker,
+
+		shutdownTracing: shutdownTracing,
 	}, nil
 }
 
+// Run inicia o servidor HTTP e bloqueia até receber SIGINT/SIGTERM. No desligamento,
+// o readiness passa a falhar, novas conexões são recusadas e as requisições e jobs em
+// andamento têm até ShutdownTimeout para terminar
 func (a *App) Run() error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
 	addr := fmt.Sprintf(":%s", a.Config.Port)
-	log.Printf("Servidor Conquista AI iniciado na porta %s", a.Config.Port)
-	log.Printf("Health check: http://localhost%s/health", addr)
-	log.Printf("API disponível em: http://localhost%s/api/v1", addr)
 
-	// Criar servidor HTTP com timeout aumentado para requisições longas (trilhas educacionais)
+	// Timeouts longos por padrão para as gerações via Spellbook (trilhas educacionais)
 	srv := &http.Server{
 		Addr:         addr,
 		Handler:      a.Router,
-		ReadTimeout:  180 * time.Second, // 3 minutos
-		WriteTimeout: 180 * time.Second, // 3 minutos
-		IdleTimeout:  120 * time.Second,
+		ReadTimeout:  a.Config.Server.ReadTimeout,
+		WriteTimeout: a.Config.Server.WriteTimeout,
+		IdleTimeout:  a.Config.Server.IdleTimeout,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("Servidor Conquista AI iniciado",
+			"port", a.Config.Port,
+			"health", fmt.Sprintf("http://localhost%s/readyz", addr),
+			"api", fmt.Sprintf("http://localhost%s/api/v1", addr),
+		)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+		close(serverErr)
+	}()
+
+	select {
+	case err, ok := <-serverErr:
+		if ok {
+			a.close()
+			return err
+		}
+	case <-ctx.Done():
+		stop()
 	}
 
-	return srv.ListenAndServe()
+	// /readyz passa a responder 503 enquanto o servidor ainda aceita conexões, dando
+	// tempo ao balanceador de parar de enviar tráfego antes do Shutdown
+	a.Health.SetDraining()
+	if delay := a.Config.Server.DrainDelay; delay > 0 {
+		slog.Info("Sinal de desligamento recebido, drenando tráfego", "drain_delay", delay.String())
+		time.Sleep(delay)
+	}
+
+	slog.Info("Encerrando servidor, aguardando requisições em andamento",
+		"timeout", a.Config.Server.ShutdownTimeout.String())
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), a.Config.Server.ShutdownTimeout)
+	defer cancel()
+
+	var shutdownErr error
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		shutdownErr = fmt.Errorf("erro ao encerrar servidor HTTP: %w", err)
+	}
+	if err := a.Jobs.Shutdown(shutdownCtx); err != nil {
+		shutdownErr = errors.Join(shutdownErr, fmt.Errorf("erro ao aguardar jobs: %w", err))
+	}
+	a.close()
+
+	if shutdownErr == nil {
+		slog.Info("Servidor encerrado")
+	}
+	return shutdownErr
 }
 
+// close libera os recursos da aplicação: descarrega os spans pendentes e fecha o banco
+func (a *App) close() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := a.shutdownTracing(ctx); err != nil {
+		slog.Error("erro ao encerrar tracing", "error", err)
+	}
+	if err := a.DB.Close(); err != nil {
+		slog.Error("erro ao fechar conexão com banco", "error", err)
+	}
+}
@@ -0,0 +1,94 @@
+// Package apperrors define os erros de domínio usados pelos serviços e handlers.
+// Cada erro carrega um tipo (Kind) que o middleware de erros converte no status HTTP
+// e no corpo application/problem+json correspondentes.
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Tipos de erro. Use errors.Is(err, apperrors.ErrNotFound) para testar o tipo
+var (
+	ErrNotFound     = errors.New("recurso não encontrado")
+	ErrValidation   = errors.New("dados inválidos")
+	ErrConflict     = errors.New("conflito com o estado atual do recurso")
+	ErrUnauthorized = errors.New("credenciais ausentes ou inválidas")
+	ErrForbidden    = errors.New("operação não permitida")
+	ErrUpstream     = errors.New("falha em serviço externo")
+	ErrInternal     = errors.New("erro interno")
+)
+
+// FieldError descreve um problema de validação em um campo específico
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error é um erro de domínio com mensagem para o cliente, detalhes por campo
+// e a causa original (que nunca é exposta na resposta)
+type Error struct {
+	Kind   error
+	Detail string
+	Fields []FieldError
+	Err    error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Detail, e.Err)
+	}
+	return e.Detail
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is permite comparar o erro com os sentinelas de tipo (ErrNotFound, ErrValidation, ...)
+func (e *Error) Is(target error) bool {
+	return e.Kind == target
+}
+
+func NotFound(format string, args ...interface{}) *Error {
+	return &Error{Kind: ErrNotFound, Detail: fmt.Sprintf(format, args...)}
+}
+
+func Validation(detail string, fields ...FieldError) *Error {
+	return &Error{Kind: ErrValidation, Detail: detail, Fields: fields}
+}
+
+// InvalidField cria um erro de validação para um único campo
+func InvalidField(field, message string) *Error {
+	return Validation(message, FieldError{Field: field, Message: message})
+}
+
+func Conflict(format string, args ...interface{}) *Error {
+	return &Error{Kind: ErrConflict, Detail: fmt.Sprintf(format, args...)}
+}
+
+func Unauthorized(format string, args ...interface{}) *Error {
+	return &Error{Kind: ErrUnauthorized, Detail: fmt.Sprintf(format, args...)}
+}
+
+func Forbidden(format string, args ...interface{}) *Error {
+	return &Error{Kind: ErrForbidden, Detail: fmt.Sprintf(format, args...)}
+}
+
+func Upstream(detail string, err error) *Error {
+	return &Error{Kind: ErrUpstream, Detail: detail, Err: err}
+}
+
+func Internal(detail string, err error) *Error {
+	return &Error{Kind: ErrInternal, Detail: detail, Err: err}
+}
+
+// Wrap preserva o tipo de um erro de domínio já existente e, para os demais erros,
+// retorna um erro interno com a mensagem informada
+func Wrap(detail string, err error) error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return err
+	}
+	return Internal(detail, err)
+}
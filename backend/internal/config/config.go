@@ -1,36 +1,244 @@
+// Package config carrega a configuração da aplicação a partir de valores padrão, de um
+// arquivo YAML/TOML opcional (CONFIG_FILE) e de variáveis de ambiente, nessa ordem de
+// precedência (o ambiente sobrescreve o arquivo, que sobrescreve os padrões).
+//
+// Cada campo declara a chave usada no arquivo (tag config) e a variável de ambiente
+// correspondente (tag env). Campos com a tag secret são mascarados pelo Print.
 package config
 
 import (
-	"fmt"
-	"os"
+	"time"
 )
 
 type Config struct {
-	Port            string
-	DatabaseURL     string
-	SpellbookAPIURL string
+	Port            string `config:"port" env:"PORT"`
+	DatabaseURL     string `config:"database_url" env:"DATABASE_URL" secret:"true"`
+	SpellbookAPIURL string `config:"spellbook_api_url" env:"SPELLBOOK_API_URL"`
+
+	Server    ServerConfig    `config:"server"`
+	Spellbook SpellbookConfig `config:"spellbook"`
+	CORS      CORSConfig      `config:"cors"`
+	Log       LogConfig       `config:"log"`
+	Telemetry TelemetryConfig `config:"telemetry"`
+	Planning  PlanningConfig  `config:"planning"`
+	Risk      RiskConfig      `config:"risk"`
+	Dashboard DashboardConfig `config:"dashboard"`
+	Audit     AuditConfig     `config:"audit"`
+	Trash     TrashConfig     `config:"trash"`
+
+	Notifications NotificationsConfig `config:"notifications"`
+	Webhooks      WebhooksConfig      `config:"webhooks"`
 }
 
-func Load() (*Config, error) {
-	cfg := &Config{
-		Port:            getEnv("PORT"),
-		DatabaseURL:     getEnv("DATABASE_URL"),
-		SpellbookAPIURL: getEnv("SPELLBOOK_API_URL"),
-	}
+// ServerConfig controla os timeouts do servidor HTTP
+type ServerConfig struct {
+	ReadTimeout  time.Duration `config:"read_timeout" env:"SERVER_READ_TIMEOUT"`
+	WriteTimeout time.Duration `config:"write_timeout" env:"SERVER_WRITE_TIMEOUT"`
+	IdleTimeout  time.Duration `config:"idle_timeout" env:"SERVER_IDLE_TIMEOUT"`
 
-	if cfg.Port == "" {
-		return nil, fmt.Errorf("PORT é obrigatória")
-	}
-	if cfg.DatabaseURL == "" {
-		return nil, fmt.Errorf("DATABASE_URL é obrigatória")
-	}
-	if cfg.SpellbookAPIURL == "" {
-		return nil, fmt.Errorf("SPELLBOOK_API_URL é obrigatória")
-	}
+	// Tempo máximo para concluir as requisições e jobs em andamento no desligamento
+	ShutdownTimeout time.Duration `config:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+	// Tempo em que /readyz responde 503 antes de o servidor parar de aceitar conexões,
+	// para o balanceador tirar a instância de rotação. Zero desativa a espera
+	DrainDelay time.Duration `config:"drain_delay" env:"SHUTDOWN_DRAIN_DELAY"`
+}
 
-	return cfg, nil
+// SpellbookConfig controla o cliente da API Spellbook
+type SpellbookConfig struct {
+	Timeout time.Duration `config:"timeout" env:"SPELLBOOK_TIMEOUT"`
+}
+
+// CORSConfig lista as origens aceitas. "*" libera qualquer origem (sem credentials)
+type CORSConfig struct {
+	AllowedOrigins []string `config:"allowed_origins" env:"CORS_ALLOWED_ORIGINS"`
+}
+
+type LogConfig struct {
+	Level  string `config:"level" env:"LOG_LEVEL"`
+	Format string `config:"format" env:"LOG_FORMAT"`
+}
+
+// TelemetryConfig configura a exportação OTLP. Endpoint vazio desativa o tracing
+type TelemetryConfig struct {
+	OTLPEndpoint string `config:"otlp_endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	OTLPInsecure bool   `config:"otlp_insecure" env:"OTEL_EXPORTER_OTLP_INSECURE"`
+}
+
+// PlanningConfig reúne os parâmetros usados para distribuir prazos de OKRs,
+// roadmaps e trilhas educacionais
+type PlanningConfig struct {
+	// Estratégia de distribuição dos dias: even, weighted, front_loaded ou back_loaded
+	Strategy string `config:"strategy" env:"PLANNING_STRATEGY"`
+	// Duração padrão de um OKR criado sem data de conclusão, em meses
+	DefaultOKRDurationMonths int `config:"default_okr_duration_months" env:"PLANNING_DEFAULT_OKR_DURATION_MONTHS"`
+	// Dias considerados para o roadmap quando não há prazo no Key Result nem no OKR
+	DefaultRoadmapDays int `config:"default_roadmap_days" env:"PLANNING_DEFAULT_ROADMAP_DAYS"`
+	// Limites de dias de uma trilha educacional
+	TrailMinDays int `config:"trail_min_days" env:"PLANNING_TRAIL_MIN_DAYS"`
+	TrailMaxDays int `config:"trail_max_days" env:"PLANNING_TRAIL_MAX_DAYS"`
+	// Limites de itens de um roadmap
+	RoadmapMinItems int `config:"roadmap_min_items" env:"PLANNING_ROADMAP_MIN_ITEMS"`
+	RoadmapMaxItems int `config:"roadmap_max_items" env:"PLANNING_ROADMAP_MAX_ITEMS"`
+}
+
+// PlanningStrategies lista os valores aceitos em planning.strategy. Como o pacote
+// planning depende de config, a lista não pode vir de planning.Strategies; um teste
+// daquele pacote garante que as duas sejam iguais
+var PlanningStrategies = []string{"even", "weighted", "front_loaded", "back_loaded"}
+
+// RiskConfig define quando um item é considerado em risco: a diferença, em pontos
+// percentuais, entre o tempo decorrido do prazo e o progresso concluído
+type RiskConfig struct {
+	AtRiskGapPercent   int `config:"at_risk_gap_percent" env:"RISK_AT_RISK_GAP_PERCENT"`
+	OffTrackGapPercent int `config:"off_track_gap_percent" env:"RISK_OFF_TRACK_GAP_PERCENT"`
 }
 
-func getEnv(key string) string {
-	return os.Getenv(key)
+// NotificationsConfig controla o job de lembretes e os canais de entrega
+type NotificationsConfig struct {
+	// Desativa o job de lembretes (a caixa de entrada e as preferências continuam disponíveis)
+	Enabled bool `config:"enabled" env:"NOTIFICATIONS_ENABLED"`
+	// Intervalo entre as execuções do job (geração e entrega)
+	Interval time.Duration `config:"interval" env:"NOTIFICATIONS_INTERVAL"`
+	// Antecedência, em dias, do lembrete de Key Results próximos do prazo
+	DueSoonDays int `config:"due_soon_days" env:"NOTIFICATIONS_DUE_SOON_DAYS"`
+	// Tentativas de entrega por canal antes de desistir
+	MaxAttempts    int           `config:"max_attempts" env:"NOTIFICATIONS_MAX_ATTEMPTS"`
+	WebhookTimeout time.Duration `config:"webhook_timeout" env:"NOTIFICATIONS_WEBHOOK_TIMEOUT"`
+
+	SMTP SMTPConfig `config:"smtp"`
+}
+
+// DashboardConfig controla o cache do dashboard (GET /dashboard)
+type DashboardConfig struct {
+	// Tempo durante o qual o dashboard calculado é reaproveitado; 0 desativa o cache
+	CacheTTL time.Duration `config:"cache_ttl" env:"DASHBOARD_CACHE_TTL"`
+}
+
+// AuditConfig define por quanto tempo o log de auditoria é mantido
+type AuditConfig struct {
+	// Dias de retenção dos eventos; 0 mantém os eventos indefinidamente
+	RetentionDays int `config:"retention_days" env:"AUDIT_RETENTION_DAYS"`
+	// Intervalo entre as execuções do job que remove os eventos antigos
+	PurgeInterval time.Duration `config:"purge_interval" env:"AUDIT_PURGE_INTERVAL"`
+}
+
+// TrashConfig define por quanto tempo OKRs, Key Results e roadmaps excluídos ficam na
+// lixeira antes de serem removidos definitivamente
+type TrashConfig struct {
+	// Dias na lixeira; 0 mantém os itens até serem restaurados
+	RetentionDays int `config:"retention_days" env:"TRASH_RETENTION_DAYS"`
+	// Intervalo entre as execuções do job que esvazia a lixeira
+	PurgeInterval time.Duration `config:"purge_interval" env:"TRASH_PURGE_INTERVAL"`
+}
+
+// WebhooksConfig controla o job que distribui e entrega os eventos de domínio aos
+// webhooks de integração
+type WebhooksConfig struct {
+	// Desativa o job de entrega (os eventos continuam acumulando na outbox)
+	Enabled bool `config:"enabled" env:"WEBHOOKS_ENABLED"`
+	// Intervalo entre as execuções do job
+	Interval time.Duration `config:"interval" env:"WEBHOOKS_INTERVAL"`
+	// Tentativas por entrega antes de desistir; a espera entre elas dobra a cada falha
+	MaxAttempts int           `config:"max_attempts" env:"WEBHOOKS_MAX_ATTEMPTS"`
+	Timeout     time.Duration `config:"timeout" env:"WEBHOOKS_TIMEOUT"`
+}
+
+// SMTPConfig configura o envio de e-mails. Host vazio desativa o canal de e-mail
+type SMTPConfig struct {
+	Host     string `config:"host" env:"SMTP_HOST"`
+	Port     int    `config:"port" env:"SMTP_PORT"`
+	Username string `config:"username" env:"SMTP_USERNAME"`
+	Password string `config:"password" env:"SMTP_PASSWORD" secret:"true"`
+	From     string `config:"from" env:"SMTP_FROM"`
+}
+
+// Default retorna a configuração com os valores padrão
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			ReadTimeout:     180 * time.Second, // 3 minutos para gerações longas (trilhas educacionais)
+			WriteTimeout:    180 * time.Second,
+			IdleTimeout:     120 * time.Second,
+			ShutdownTimeout: 190 * time.Second,
+			DrainDelay:      5 * time.Second,
+		},
+		Spellbook: SpellbookConfig{
+			Timeout: 180 * time.Second, // 3 minutos para trilhas educacionais complexas
+		},
+		CORS: CORSConfig{
+			// Hosts de desenvolvimento + produção
+			AllowedOrigins: []string{
+				"http://localhost:3000",
+				"http://localhost:3001",
+				"http://localhost:3003",
+				"http://127.0.0.1:3000",
+				"http://127.0.0.1:3001",
+				"http://127.0.0.1:3003",
+				"http://hiagoserver.local:3000",
+				"http://hiagoserver.local:3001",
+				"http://hiagoserver.local:3003",
+				"http://hiagoserver.local",
+				"https://conquista-ai-api.klapowsko.com",
+				"https://conquista-ai.klapowsko.com",
+			},
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "json",
+		},
+		Telemetry: TelemetryConfig{
+			OTLPInsecure: true,
+		},
+		Planning: PlanningConfig{
+			Strategy:                 "even",
+			DefaultOKRDurationMonths: 3,
+			DefaultRoadmapDays:       30,
+			TrailMinDays:             3,
+			TrailMaxDays:             30,
+			RoadmapMinItems:          3,
+			RoadmapMaxItems:          20,
+		},
+		Risk: RiskConfig{
+			AtRiskGapPercent:   15,
+			OffTrackGapPercent: 35,
+		},
+		Notifications: NotificationsConfig{
+			Enabled:        true,
+			Interval:       15 * time.Minute,
+			DueSoonDays:    3,
+			MaxAttempts:    5,
+			WebhookTimeout: 10 * time.Second,
+			SMTP: SMTPConfig{
+				Port: 587,
+				From: "Conquista AI <noreply@conquista-ai.local>",
+			},
+		},
+		Dashboard: DashboardConfig{
+			CacheTTL: 30 * time.Second,
+		},
+		Audit: AuditConfig{
+			RetentionDays: 365,
+			PurgeInterval: time.Hour,
+		},
+		Trash: TrashConfig{
+			RetentionDays: 30,
+			PurgeInterval: time.Hour,
+		},
+		Webhooks: WebhooksConfig{
+			Enabled:     true,
+			Interval:    10 * time.Second,
+			MaxAttempts: 8,
+			Timeout:     10 * time.Second,
+		},
+	}
+}
+
+// Load monta a configuração efetiva (padrões, arquivo CONFIG_FILE e ambiente) e a valida.
+// Em caso de erro de validação, retorna *ValidationError com todos os problemas encontrados
+func Load() (*Config, error) {
+	cfg, _, err := load()
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
 }
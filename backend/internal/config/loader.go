@@ -0,0 +1,369 @@
+package config
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/pelletier/go-toml/v2"
+)
+
+// Origens possíveis de um valor de configuração
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+)
+
+// ValidationError lista todos os problemas encontrados na configuração
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "configuração inválida:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// field é um valor folha da configuração, identificado pela chave do arquivo
+type field struct {
+	key    string
+	env    string
+	secret bool
+	value  reflect.Value
+}
+
+func (f field) label() string {
+	if f.env == "" {
+		return f.key
+	}
+	return fmt.Sprintf("%s (%s)", f.key, f.env)
+}
+
+// fields percorre a struct de configuração e retorna os campos folha na ordem de declaração
+func fields(cfg *Config) []field {
+	var out []field
+	var walk func(v reflect.Value, prefix string)
+	walk = func(v reflect.Value, prefix string) {
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			key := sf.Tag.Get("config")
+			if key == "" {
+				continue
+			}
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+
+			fv := v.Field(i)
+			if sf.Type.Kind() == reflect.Struct && sf.Type != reflect.TypeOf(time.Duration(0)) {
+				walk(fv, key)
+				continue
+			}
+
+			out = append(out, field{
+				key:    key,
+				env:    sf.Tag.Get("env"),
+				secret: sf.Tag.Get("secret") == "true",
+				value:  fv,
+			})
+		}
+	}
+	walk(reflect.ValueOf(cfg).Elem(), "")
+	return out
+}
+
+// load monta a configuração efetiva e retorna também a origem de cada chave.
+// A configuração é retornada mesmo quando há problemas de validação
+func load() (*Config, map[string]string, error) {
+	cfg := Default()
+	all := fields(cfg)
+	sources := make(map[string]string, len(all))
+	for _, f := range all {
+		sources[f.key] = SourceDefault
+	}
+
+	var problems []string
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		values, err := readFile(path)
+		if err != nil {
+			return cfg, sources, &ValidationError{Problems: []string{err.Error()}}
+		}
+
+		for _, f := range all {
+			raw, ok := values[f.key]
+			if !ok {
+				continue
+			}
+			delete(values, f.key)
+			if err := setField(f.value, raw); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", f.label(), err))
+				continue
+			}
+			sources[f.key] = SourceFile
+		}
+
+		unknown := make([]string, 0, len(values))
+		for key := range values {
+			unknown = append(unknown, key)
+		}
+		slices.Sort(unknown)
+		for _, key := range unknown {
+			problems = append(problems, fmt.Sprintf("%s: chave desconhecida em %s", key, path))
+		}
+	}
+
+	for _, f := range all {
+		if f.env == "" {
+			continue
+		}
+		raw, ok := os.LookupEnv(f.env)
+		if !ok || strings.TrimSpace(raw) == "" {
+			continue
+		}
+		if err := setField(f.value, raw); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", f.label(), err))
+			continue
+		}
+		sources[f.key] = SourceEnv
+	}
+
+	problems = append(problems, validate(cfg)...)
+	if len(problems) > 0 {
+		return cfg, sources, &ValidationError{Problems: problems}
+	}
+
+	return cfg, sources, nil
+}
+
+// readFile lê um arquivo YAML (.yaml/.yml) ou TOML (.toml) e o achata em chaves
+// separadas por ponto (ex.: server.read_timeout)
+func readFile(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler arquivo de configuração: %w", err)
+	}
+
+	var tree map[string]any
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &tree)
+	case ".toml":
+		err = toml.Unmarshal(data, &tree)
+	default:
+		return nil, fmt.Errorf("formato de arquivo de configuração não suportado: %s (use .yaml, .yml ou .toml)", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao interpretar %s: %w", path, err)
+	}
+
+	values := make(map[string]any)
+	flatten("", tree, values)
+	return values, nil
+}
+
+func flatten(prefix string, tree map[string]any, out map[string]any) {
+	for key, value := range tree {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]any); ok {
+			flatten(key, nested, out)
+			continue
+		}
+		out[key] = value
+	}
+}
+
+// setField converte raw (texto do ambiente ou valor do arquivo) para o tipo do campo
+func setField(v reflect.Value, raw any) error {
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(fmt.Sprint(raw))
+		if err != nil {
+			return fmt.Errorf("duração inválida %q (use por exemplo 30s, 3m)", fmt.Sprint(raw))
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(fmt.Sprint(raw))
+	case reflect.Int:
+		n, err := strconv.Atoi(strings.TrimSpace(fmt.Sprint(raw)))
+		if err != nil {
+			return fmt.Errorf("número inteiro inválido %q", fmt.Sprint(raw))
+		}
+		v.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(fmt.Sprint(raw)))
+		if err != nil {
+			return fmt.Errorf("booleano inválido %q (use true ou false)", fmt.Sprint(raw))
+		}
+		v.SetBool(b)
+	case reflect.Slice:
+		var items []string
+		switch list := raw.(type) {
+		case []any:
+			for _, item := range list {
+				items = append(items, strings.TrimSpace(fmt.Sprint(item)))
+			}
+		default:
+			for _, item := range strings.Split(fmt.Sprint(raw), ",") {
+				if trimmed := strings.TrimSpace(item); trimmed != "" {
+					items = append(items, trimmed)
+				}
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("tipo de configuração não suportado: %s", v.Type())
+	}
+
+	return nil
+}
+
+// validate retorna todos os problemas encontrados na configuração
+func validate(cfg *Config) []string {
+	var problems []string
+	add := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if cfg.Port == "" {
+		add("port (PORT): é obrigatória")
+	} else if port, err := strconv.Atoi(cfg.Port); err != nil || port < 1 || port > 65535 {
+		add("port (PORT): deve ser um número entre 1 e 65535")
+	}
+	if cfg.DatabaseURL == "" {
+		add("database_url (DATABASE_URL): é obrigatória")
+	}
+	if cfg.SpellbookAPIURL == "" {
+		add("spellbook_api_url (SPELLBOOK_API_URL): é obrigatória")
+	} else if !isHTTPURL(cfg.SpellbookAPIURL) {
+		add("spellbook_api_url (SPELLBOOK_API_URL): deve ser uma URL http(s)")
+	}
+
+	durations := []struct {
+		label string
+		value time.Duration
+	}{
+		{"server.read_timeout (SERVER_READ_TIMEOUT)", cfg.Server.ReadTimeout},
+		{"server.write_timeout (SERVER_WRITE_TIMEOUT)", cfg.Server.WriteTimeout},
+		{"server.idle_timeout (SERVER_IDLE_TIMEOUT)", cfg.Server.IdleTimeout},
+		{"server.shutdown_timeout (SHUTDOWN_TIMEOUT)", cfg.Server.ShutdownTimeout},
+		{"spellbook.timeout (SPELLBOOK_TIMEOUT)", cfg.Spellbook.Timeout},
+		{"notifications.interval (NOTIFICATIONS_INTERVAL)", cfg.Notifications.Interval},
+		{"notifications.webhook_timeout (NOTIFICATIONS_WEBHOOK_TIMEOUT)", cfg.Notifications.WebhookTimeout},
+		{"audit.purge_interval (AUDIT_PURGE_INTERVAL)", cfg.Audit.PurgeInterval},
+		{"trash.purge_interval (TRASH_PURGE_INTERVAL)", cfg.Trash.PurgeInterval},
+		{"webhooks.interval (WEBHOOKS_INTERVAL)", cfg.Webhooks.Interval},
+		{"webhooks.timeout (WEBHOOKS_TIMEOUT)", cfg.Webhooks.Timeout},
+	}
+	for _, d := range durations {
+		if d.value <= 0 {
+			add("%s: deve ser maior que zero", d.label)
+		}
+	}
+	if cfg.Server.DrainDelay < 0 {
+		add("server.drain_delay (SHUTDOWN_DRAIN_DELAY): não pode ser negativo")
+	}
+
+	if len(cfg.CORS.AllowedOrigins) == 0 {
+		add("cors.allowed_origins (CORS_ALLOWED_ORIGINS): informe ao menos uma origem ou *")
+	}
+	for _, origin := range cfg.CORS.AllowedOrigins {
+		if origin == "*" {
+			if len(cfg.CORS.AllowedOrigins) > 1 {
+				add("cors.allowed_origins (CORS_ALLOWED_ORIGINS): * não pode ser combinado com outras origens")
+			}
+			continue
+		}
+		if !isHTTPURL(origin) {
+			add("cors.allowed_origins (CORS_ALLOWED_ORIGINS): origem inválida %q", origin)
+		}
+	}
+
+	if !slices.Contains([]string{"debug", "info", "warn", "error"}, strings.ToLower(cfg.Log.Level)) {
+		add("log.level (LOG_LEVEL): deve ser debug, info, warn ou error")
+	}
+	if !slices.Contains([]string{"json", "text"}, strings.ToLower(cfg.Log.Format)) {
+		add("log.format (LOG_FORMAT): deve ser json ou text")
+	}
+
+	p := cfg.Planning
+	if !slices.Contains(PlanningStrategies, p.Strategy) {
+		add("planning.strategy (PLANNING_STRATEGY): deve ser %s", strings.Join(PlanningStrategies, ", "))
+	}
+	if p.DefaultOKRDurationMonths < 1 {
+		add("planning.default_okr_duration_months (PLANNING_DEFAULT_OKR_DURATION_MONTHS): deve ser ao menos 1")
+	}
+	if p.DefaultRoadmapDays < 1 {
+		add("planning.default_roadmap_days (PLANNING_DEFAULT_ROADMAP_DAYS): deve ser ao menos 1")
+	}
+	if p.TrailMinDays < 1 {
+		add("planning.trail_min_days (PLANNING_TRAIL_MIN_DAYS): deve ser ao menos 1")
+	}
+	if p.TrailMaxDays < p.TrailMinDays {
+		add("planning.trail_max_days (PLANNING_TRAIL_MAX_DAYS): deve ser maior ou igual a planning.trail_min_days")
+	}
+	if p.RoadmapMinItems < 1 {
+		add("planning.roadmap_min_items (PLANNING_ROADMAP_MIN_ITEMS): deve ser ao menos 1")
+	}
+	if p.RoadmapMaxItems < p.RoadmapMinItems {
+		add("planning.roadmap_max_items (PLANNING_ROADMAP_MAX_ITEMS): deve ser maior ou igual a planning.roadmap_min_items")
+	}
+
+	r := cfg.Risk
+	if r.AtRiskGapPercent < 1 || r.AtRiskGapPercent > 100 {
+		add("risk.at_risk_gap_percent (RISK_AT_RISK_GAP_PERCENT): deve ser um número entre 1 e 100")
+	}
+	if r.OffTrackGapPercent <= r.AtRiskGapPercent || r.OffTrackGapPercent > 100 {
+		add("risk.off_track_gap_percent (RISK_OFF_TRACK_GAP_PERCENT): deve ser maior que risk.at_risk_gap_percent e no máximo 100")
+	}
+
+	n := cfg.Notifications
+	if n.DueSoonDays < 1 {
+		add("notifications.due_soon_days (NOTIFICATIONS_DUE_SOON_DAYS): deve ser ao menos 1")
+	}
+	if n.MaxAttempts < 1 {
+		add("notifications.max_attempts (NOTIFICATIONS_MAX_ATTEMPTS): deve ser ao menos 1")
+	}
+	if n.SMTP.Host != "" {
+		if n.SMTP.Port < 1 || n.SMTP.Port > 65535 {
+			add("notifications.smtp.port (SMTP_PORT): deve ser um número entre 1 e 65535")
+		}
+		if _, err := mail.ParseAddress(n.SMTP.From); err != nil {
+			add("notifications.smtp.from (SMTP_FROM): endereço de e-mail inválido")
+		}
+	}
+
+	if cfg.Dashboard.CacheTTL < 0 {
+		add("dashboard.cache_ttl (DASHBOARD_CACHE_TTL): não pode ser negativo")
+	}
+	if cfg.Audit.RetentionDays < 0 {
+		add("audit.retention_days (AUDIT_RETENTION_DAYS): não pode ser negativo")
+	}
+	if cfg.Trash.RetentionDays < 0 {
+		add("trash.retention_days (TRASH_RETENTION_DAYS): não pode ser negativo")
+	}
+	if cfg.Webhooks.MaxAttempts < 1 {
+		add("webhooks.max_attempts (WEBHOOKS_MAX_ATTEMPTS): deve ser ao menos 1")
+	}
+
+	return problems
+}
+
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
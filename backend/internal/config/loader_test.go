@@ -0,0 +1,280 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clearEnv neutraliza as variáveis de ambiente da configuração (vazias são ignoradas
+// pelo load) para que o ambiente de quem roda os testes não interfira
+func clearEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv("CONFIG_FILE", "")
+	for _, f := range fields(Default()) {
+		if f.env != "" {
+			t.Setenv(f.env, "")
+		}
+	}
+}
+
+// writeConfig grava um arquivo de configuração temporário e o aponta em CONFIG_FILE
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_FILE", path)
+	return path
+}
+
+func problems(err error) []string {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr.Problems
+	}
+	return nil
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{
+			name: "yaml",
+			file: "config.yaml",
+			content: `port: "9000"
+database_url: postgres://app@db:5432/app
+spellbook_api_url: http://spellbook:8000
+server:
+  read_timeout: 10s
+log:
+  level: debug
+cors:
+  allowed_origins: [http://localhost:3000, https://app.example.com]
+`,
+		},
+		{
+			name: "toml",
+			file: "config.toml",
+			content: `port = "9000"
+database_url = "postgres://app@db:5432/app"
+spellbook_api_url = "http://spellbook:8000"
+
+[server]
+read_timeout = "10s"
+
+[log]
+level = "debug"
+
+[cors]
+allowed_origins = ["http://localhost:3000", "https://app.example.com"]
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			writeConfig(t, tt.file, tt.content)
+			t.Setenv("PORT", "9100")
+			t.Setenv("LOG_FORMAT", "text")
+
+			cfg, sources, err := load()
+			if err != nil {
+				t.Fatalf("load: %v", err)
+			}
+
+			checks := []struct {
+				key    string
+				got    any
+				want   any
+				source string
+			}{
+				{"port", cfg.Port, "9100", SourceEnv},
+				{"database_url", cfg.DatabaseURL, "postgres://app@db:5432/app", SourceFile},
+				{"server.read_timeout", cfg.Server.ReadTimeout, 10 * time.Second, SourceFile},
+				{"server.write_timeout", cfg.Server.WriteTimeout, 180 * time.Second, SourceDefault},
+				{"log.level", cfg.Log.Level, "debug", SourceFile},
+				{"log.format", cfg.Log.Format, "text", SourceEnv},
+				{"planning.strategy", cfg.Planning.Strategy, "even", SourceDefault},
+			}
+			for _, c := range checks {
+				if c.got != c.want || sources[c.key] != c.source {
+					t.Errorf("%s = %v (%s), want %v (%s)", c.key, c.got, sources[c.key], c.want, c.source)
+				}
+			}
+			if want := []string{"http://localhost:3000", "https://app.example.com"}; !slices.Equal(cfg.CORS.AllowedOrigins, want) {
+				t.Errorf("cors.allowed_origins = %v, want %v", cfg.CORS.AllowedOrigins, want)
+			}
+		})
+	}
+}
+
+func TestLoadEnvList(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATABASE_URL", "postgres://db/app")
+	t.Setenv("SPELLBOOK_API_URL", "http://spellbook:8000")
+	t.Setenv("CORS_ALLOWED_ORIGINS", " http://a.example.com, ,https://b.example.com ")
+
+	cfg, _, err := load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if want := []string{"http://a.example.com", "https://b.example.com"}; !slices.Equal(cfg.CORS.AllowedOrigins, want) {
+		t.Errorf("cors.allowed_origins = %v, want %v", cfg.CORS.AllowedOrigins, want)
+	}
+}
+
+func TestLoadUnknownFileKeys(t *testing.T) {
+	clearEnv(t)
+	path := writeConfig(t, "config.yml", `port: 8080
+database_url: postgres://db/app
+spellbook_api_url: http://spellbook:8000
+server:
+  read_timout: 10s
+extra: 1
+`)
+
+	_, _, err := load()
+	want := []string{
+		"extra: chave desconhecida em " + path,
+		"server.read_timout: chave desconhecida em " + path,
+	}
+	if got := problems(err); !slices.Equal(got, want) {
+		t.Errorf("problemas =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestLoadCollectsAllProblems(t *testing.T) {
+	clearEnv(t)
+	writeConfig(t, "config.toml", `[risk]
+at_risk_gap_percent = 40
+off_track_gap_percent = 30
+`)
+	t.Setenv("PORT", "70000")
+	t.Setenv("SERVER_READ_TIMEOUT", "três minutos")
+	t.Setenv("NOTIFICATIONS_ENABLED", "talvez")
+	t.Setenv("PLANNING_STRATEGY", "random")
+	t.Setenv("LOG_LEVEL", "verbose")
+
+	cfg, _, err := load()
+	if cfg == nil {
+		t.Fatal("a configuração deve ser retornada mesmo quando inválida")
+	}
+	got := problems(err)
+	for _, want := range []string{
+		`server.read_timeout (SERVER_READ_TIMEOUT): duração inválida "três minutos" (use por exemplo 30s, 3m)`,
+		`notifications.enabled (NOTIFICATIONS_ENABLED): booleano inválido "talvez" (use true ou false)`,
+		"port (PORT): deve ser um número entre 1 e 65535",
+		"database_url (DATABASE_URL): é obrigatória",
+		"spellbook_api_url (SPELLBOOK_API_URL): é obrigatória",
+		"log.level (LOG_LEVEL): deve ser debug, info, warn ou error",
+		"planning.strategy (PLANNING_STRATEGY): deve ser even, weighted, front_loaded, back_loaded",
+		"risk.off_track_gap_percent (RISK_OFF_TRACK_GAP_PERCENT): deve ser maior que risk.at_risk_gap_percent e no máximo 100",
+	} {
+		if !slices.Contains(got, want) {
+			t.Errorf("problema ausente: %q", want)
+		}
+	}
+	if len(got) != 8 {
+		t.Errorf("problemas = %d, want 8:\n%s", len(got), strings.Join(got, "\n"))
+	}
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	tests := []struct {
+		name, file, content, want string
+	}{
+		{"extensão não suportada", "config.json", `{}`, "formato de arquivo de configuração não suportado"},
+		{"YAML inválido", "config.yaml", "port: [", "erro ao interpretar"},
+		{"TOML inválido", "config.toml", "port = ", "erro ao interpretar"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			writeConfig(t, tt.file, tt.content)
+			_, _, err := load()
+			if got := problems(err); len(got) != 1 || !strings.Contains(got[0], tt.want) {
+				t.Errorf("problemas = %q, want um problema com %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("arquivo inexistente", func(t *testing.T) {
+		clearEnv(t)
+		t.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "ausente.yaml"))
+		if _, _, err := load(); err == nil {
+			t.Error("want erro para arquivo inexistente")
+		}
+	})
+}
+
+func TestPrintRedactsSecrets(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATABASE_URL", "postgres://app:s3nh4-secreta@db:5432/app")
+	t.Setenv("SPELLBOOK_API_URL", "http://spellbook:8000")
+	t.Setenv("SMTP_PASSWORD", "hunter2")
+
+	var buf bytes.Buffer
+	if err := Print(&buf); err != nil {
+		t.Fatalf("Print: %v", err)
+	}
+	out := buf.String()
+
+	for _, secret := range []string{"s3nh4-secreta", "hunter2"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("segredo %q exibido:\n%s", secret, out)
+		}
+	}
+	for _, want := range []string{
+		"database_url = postgres://app:********@db:5432/app  # env, DATABASE_URL\n",
+		"notifications.smtp.password = ********  # env, SMTP_PASSWORD\n",
+		"notifications.smtp.username = \"\"  # default, SMTP_USERNAME\n",
+		"server.read_timeout = 3m0s  # default, SERVER_READ_TIMEOUT\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("linha ausente: %q", want)
+		}
+	}
+}
+
+func TestPrintListsProblems(t *testing.T) {
+	clearEnv(t)
+
+	var buf bytes.Buffer
+	err := Print(&buf)
+	if problems(err) == nil {
+		t.Fatalf("err = %v, want *ValidationError", err)
+	}
+	if !strings.Contains(buf.String(), "Problemas encontrados:\n  - port (PORT): é obrigatória\n  - database_url (DATABASE_URL): é obrigatória\n") {
+		t.Errorf("saída sem a lista de problemas:\n%s", buf.String())
+	}
+}
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		value, want string
+	}{
+		{"", `""`},
+		{"hunter2", redacted},
+		{"postgres://app:senha@db:5432/app?sslmode=disable", "postgres://app:" + redacted + "@db:5432/app?sslmode=disable"},
+		{"postgres://app@db:5432/app", "postgres://app@db:5432/app"},
+		{"https://example.com/token", redacted},
+	}
+	for _, tt := range tests {
+		if got := redact(tt.value); got != tt.want {
+			t.Errorf("redact(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
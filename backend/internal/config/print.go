@@ -0,0 +1,79 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const redacted = "********"
+
+// Print escreve a configuração efetiva no formato "chave = valor  # origem, VARIÁVEL",
+// com os segredos mascarados. Os valores são exibidos mesmo quando a configuração é
+// inválida; nesse caso os problemas são listados ao final e retornados como *ValidationError
+func Print(w io.Writer) error {
+	cfg, sources, loadErr := load()
+
+	for _, f := range fields(cfg) {
+		origin := sources[f.key]
+		if f.env != "" {
+			origin += ", " + f.env
+		}
+		if _, err := fmt.Fprintf(w, "%s = %s  # %s\n", f.key, formatValue(f), origin); err != nil {
+			return err
+		}
+	}
+
+	var validationErr *ValidationError
+	if errors.As(loadErr, &validationErr) {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Problemas encontrados:")
+		for _, problem := range validationErr.Problems {
+			fmt.Fprintf(w, "  - %s\n", problem)
+		}
+	}
+
+	return loadErr
+}
+
+func formatValue(f field) string {
+	value := f.value.Interface()
+	var text string
+	switch v := value.(type) {
+	case time.Duration:
+		text = v.String()
+	case []string:
+		text = strings.Join(v, ",")
+	default:
+		text = fmt.Sprint(v)
+	}
+
+	if f.secret {
+		return redact(text)
+	}
+	if text == "" {
+		return `""`
+	}
+	return text
+}
+
+// redact mascara um segredo. Em URLs com credenciais apenas a senha é ocultada,
+// mantendo host e banco visíveis para diagnóstico
+func redact(value string) string {
+	if value == "" {
+		return `""`
+	}
+
+	if u, err := url.Parse(value); err == nil && u.Scheme != "" && u.User != nil {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			u.User = url.UserPassword(u.User.Username(), "xxxxx")
+			return strings.Replace(u.String(), "xxxxx", redacted, 1)
+		}
+		return u.String()
+	}
+
+	return redacted
+}
@@ -3,11 +3,15 @@ package database
 import (
 	"database/sql"
 	"fmt"
+
+	"github.com/XSAM/otelsql"
 	_ "github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
 func Connect(databaseURL string) (*sql.DB, error) {
-	db, err := sql.Open("postgres", databaseURL)
+	// otelsql gera spans para cada query executada com contexto
+	db, err := otelsql.Open("postgres", databaseURL, otelsql.WithAttributes(semconv.DBSystemPostgreSQL))
 	if err != nil {
 		return nil, fmt.Errorf("erro ao abrir conexão com banco: %w", err)
 	}
@@ -0,0 +1,84 @@
+// Package document descreve documentos de estrutura simples (títulos, parágrafos e
+// listas com caixas de seleção e links) e os grava em Markdown ou PDF. É usado na
+// exportação de roadmaps e trilhas para impressão e compartilhamento.
+package document
+
+import (
+	"net/url"
+	"time"
+)
+
+// Kind é o tipo de um bloco do documento
+type Kind int
+
+const (
+	KindHeading Kind = iota
+	KindParagraph
+	// KindItem é um item de lista, opcionalmente com caixa de seleção e link
+	KindItem
+)
+
+// Block é um bloco do documento
+type Block struct {
+	Kind Kind
+	// Nível do título (1 a 3) ou recuo do item de lista (0 para o primeiro nível)
+	Level int
+	// Label é um rótulo exibido em negrito antes do texto do item
+	Label string
+	Text  string
+	// URL transforma o texto do item em link (apenas http e https são mantidos)
+	URL string
+	// Checkbox exibe a caixa de seleção do item, marcada quando Checked
+	Checkbox bool
+	Checked  bool
+}
+
+// Document é uma sequência de blocos com um título
+type Document struct {
+	Title   string
+	Created time.Time
+	Blocks  []Block
+}
+
+// Heading acrescenta um título de nível 1 a 3
+func (d *Document) Heading(level int, text string) {
+	d.Blocks = append(d.Blocks, Block{Kind: KindHeading, Level: min(max(level, 1), 3), Text: text})
+}
+
+// Paragraph acrescenta um parágrafo; textos vazios são ignorados
+func (d *Document) Paragraph(text string) {
+	if text == "" {
+		return
+	}
+	d.Blocks = append(d.Blocks, Block{Kind: KindParagraph, Text: text})
+}
+
+// Item acrescenta um item de lista no recuo indicado
+func (d *Document) Item(level int, label, text string) {
+	d.Blocks = append(d.Blocks, Block{Kind: KindItem, Level: level, Label: label, Text: text})
+}
+
+// Task acrescenta um item de lista com caixa de seleção
+func (d *Document) Task(level int, checked bool, label, text string) {
+	d.Blocks = append(d.Blocks, Block{Kind: KindItem, Level: level, Label: label, Text: text, Checkbox: true, Checked: checked})
+}
+
+// Link acrescenta um item de lista com link. URLs que não sejam http(s) são exibidas
+// como texto, sem link
+func (d *Document) Link(level int, label, text, rawURL string) {
+	block := Block{Kind: KindItem, Level: level, Label: label, Text: text}
+	if safeURL(rawURL) {
+		block.URL = rawURL
+	} else if text == "" {
+		block.Text = rawURL
+	}
+	if block.Text == "" {
+		block.Text = block.URL
+	}
+	d.Blocks = append(d.Blocks, block)
+}
+
+func safeURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
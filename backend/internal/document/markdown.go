@@ -0,0 +1,75 @@
+package document
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// markdownEscaper escapa os caracteres com significado em Markdown inline
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`,
+	`<`, `\<`, `>`, `\>`, `#`, `\#`, `|`, `\|`, `~`, `\~`,
+)
+
+// WriteMarkdown grava o documento em Markdown (CommonMark com listas de tarefas no
+// estilo do GitHub)
+func (d *Document) WriteMarkdown(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	previous := Kind(-1)
+
+	for _, b := range d.Blocks {
+		// Itens consecutivos formam uma única lista; os demais blocos são separados por
+		// uma linha em branco
+		if previous >= 0 && !(b.Kind == KindItem && previous == KindItem) {
+			bw.WriteString("\n")
+		}
+		previous = b.Kind
+
+		switch b.Kind {
+		case KindHeading:
+			bw.WriteString(strings.Repeat("#", b.Level) + " " + inline(b.Text) + "\n")
+		case KindParagraph:
+			for i, line := range strings.Split(strings.TrimSpace(b.Text), "\n") {
+				if i > 0 {
+					// Quebra de linha dentro do parágrafo
+					bw.WriteString("\\\n")
+				}
+				bw.WriteString(inline(line))
+			}
+			bw.WriteString("\n")
+		case KindItem:
+			bw.WriteString(strings.Repeat("  ", b.Level) + "- ")
+			if b.Checkbox {
+				if b.Checked {
+					bw.WriteString("[x] ")
+				} else {
+					bw.WriteString("[ ] ")
+				}
+			}
+			if b.Label != "" {
+				bw.WriteString("**" + inline(b.Label) + "**")
+				if b.Text != "" {
+					bw.WriteString(" ")
+				}
+			}
+			if b.URL != "" {
+				bw.WriteString("[" + inline(b.Text) + "](<" + markdownURL(b.URL) + ">)")
+			} else {
+				bw.WriteString(inline(b.Text))
+			}
+			bw.WriteString("\n")
+		}
+	}
+	return bw.Flush()
+}
+
+// inline escapa o texto e junta as linhas, já que títulos e itens ocupam uma única linha
+func inline(s string) string {
+	return markdownEscaper.Replace(strings.Join(strings.Fields(s), " "))
+}
+
+// markdownURL codifica os caracteres que encerrariam um destino entre < e >
+func markdownURL(u string) string {
+	return strings.NewReplacer("<", "%3C", ">", "%3E", " ", "%20", "\n", "", "\r", "").Replace(u)
+}
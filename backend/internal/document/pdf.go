@@ -0,0 +1,215 @@
+package document
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/conquista-ai/conquista-ai/internal/pdf"
+)
+
+// Layout das páginas, em pontos
+const (
+	pdfMargin     = 56
+	pdfFooter     = 24
+	pdfBodySize   = 10.5
+	pdfFooterSize = 8
+	pdfLeading    = 1.4
+	pdfIndent     = 16
+	// Espaço entre o marcador (caixa ou ponto) e o texto do item
+	pdfMarker   = 14
+	pdfCheckbox = 8
+)
+
+var pdfHeadingSizes = map[int]float64{1: 18, 2: 14, 3: 12}
+
+// word é uma palavra com a fonte em que deve ser escrita
+type word struct {
+	text string
+	font pdf.Font
+}
+
+// pdfLayout posiciona os blocos de cima para baixo, abrindo páginas conforme necessário
+type pdfLayout struct {
+	doc  *pdf.Document
+	page *pdf.Page
+	// Linha de base da próxima linha de texto (coordenadas do PDF, de baixo para cima)
+	y float64
+}
+
+// WritePDF grava o documento como PDF A4, com o título e a numeração no rodapé
+func (d *Document) WritePDF(w io.Writer) error {
+	l := &pdfLayout{doc: &pdf.Document{Title: d.Title, Created: d.Created}}
+	l.newPage()
+
+	for i, b := range d.Blocks {
+		switch b.Kind {
+		case KindHeading:
+			size := pdfHeadingSizes[b.Level]
+			if i > 0 {
+				l.y -= size * 0.8
+			}
+			// Mantém o título junto das primeiras linhas do bloco seguinte
+			l.ensure(size*pdfLeading + 2*pdfBodySize*pdfLeading)
+			l.paragraph(pdfMargin, pdf.PageWidth-2*pdfMargin, size, words("", b.Text, pdf.HelveticaBold), "")
+			l.y -= size * 0.2
+		case KindParagraph:
+			for _, line := range strings.Split(strings.TrimSpace(b.Text), "\n") {
+				l.paragraph(pdfMargin, pdf.PageWidth-2*pdfMargin, pdfBodySize, words("", line, pdf.Helvetica), "")
+			}
+			l.y -= pdfBodySize * 0.5
+		case KindItem:
+			x := pdfMargin + float64(b.Level)*pdfIndent
+			l.ensure(pdfBodySize * pdfLeading)
+			l.marker(x, b)
+			l.paragraph(x+pdfMarker, pdf.PageWidth-pdfMargin-x-pdfMarker, pdfBodySize, words(b.Label, b.Text, pdf.Helvetica), b.URL)
+			l.y -= pdfBodySize * 0.2
+		}
+	}
+
+	pages := l.doc.Pages()
+	for i, page := range pages {
+		footer := fmt.Sprintf("Página %d de %d", i+1, len(pages))
+		page.SetColor(0.45, 0.45, 0.45)
+		page.Text(pdfMargin, pdfMargin-pdfFooter/2, pdf.Helvetica, pdfFooterSize, truncate(d.Title, pdf.PageWidth-2*pdfMargin-80))
+		page.Text(pdf.PageWidth-pdfMargin-pdf.Width(pdf.Helvetica, pdfFooterSize, footer), pdfMargin-pdfFooter/2, pdf.Helvetica, pdfFooterSize, footer)
+	}
+
+	return l.doc.Write(w)
+}
+
+func (l *pdfLayout) newPage() {
+	l.page = l.doc.AddPage()
+	l.y = pdf.PageHeight - pdfMargin
+}
+
+// ensure abre uma nova página quando não há espaço para height pontos
+func (l *pdfLayout) ensure(height float64) {
+	if l.y-height < pdfMargin+pdfFooter {
+		l.newPage()
+	}
+}
+
+// marker desenha a caixa de seleção (marcada ou não) ou o ponto do item de lista
+func (l *pdfLayout) marker(x float64, b Block) {
+	baseline := l.y - pdfBodySize
+	if !b.Checkbox {
+		l.page.Text(x+2, baseline, pdf.Helvetica, pdfBodySize, "•")
+		return
+	}
+	l.page.Rect(x, baseline-1, pdfCheckbox, pdfCheckbox, 0.8)
+	if b.Checked {
+		l.page.Line(x+1.5, baseline+3, x+3.5, baseline+0.8, 1.2)
+		l.page.Line(x+3.5, baseline+0.8, x+6.8, baseline+6, 1.2)
+	}
+}
+
+// paragraph quebra as palavras em linhas de até width pontos a partir de x. Com link,
+// o texto sai em azul e cada linha vira uma área clicável
+func (l *pdfLayout) paragraph(x, width, size float64, ws []word, link string) {
+	lineHeight := size * pdfLeading
+	for _, line := range wrap(ws, size, width) {
+		l.ensure(lineHeight)
+		baseline := l.y - size
+
+		if link != "" {
+			l.page.SetColor(0.05, 0.3, 0.7)
+		}
+		cursor := x
+		for _, run := range runs(line) {
+			l.page.Text(cursor, baseline, run.font, size, run.text)
+			cursor += pdf.Width(run.font, size, run.text+" ")
+		}
+		if link != "" {
+			l.page.SetColor(0, 0, 0)
+			l.page.Link(x, baseline-size*0.25, cursor-x, size*1.1, link)
+		}
+		l.y -= lineHeight
+	}
+}
+
+// words separa o rótulo (em negrito) e o texto em palavras
+func words(label, text string, font pdf.Font) []word {
+	var ws []word
+	for _, f := range strings.Fields(label) {
+		ws = append(ws, word{text: f, font: pdf.HelveticaBold})
+	}
+	for _, f := range strings.Fields(text) {
+		ws = append(ws, word{text: f, font: font})
+	}
+	return ws
+}
+
+// wrap distribui as palavras em linhas que cabem em width; palavras mais largas que a
+// linha inteira são partidas
+func wrap(ws []word, size, width float64) [][]word {
+	var lines [][]word
+	var line []word
+	used := 0.0
+	for _, w := range ws {
+		for _, part := range split(w, size, width) {
+			wWidth := pdf.Width(part.font, size, part.text)
+			space := 0.0
+			if len(line) > 0 {
+				space = pdf.Width(line[len(line)-1].font, size, " ")
+			}
+			if len(line) > 0 && used+space+wWidth > width {
+				lines = append(lines, line)
+				line, used, space = nil, 0, 0
+			}
+			line = append(line, part)
+			used += space + wWidth
+		}
+	}
+	if len(line) > 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// split parte uma palavra mais larga que width em pedaços que caibam na linha
+func split(w word, size, width float64) []word {
+	if pdf.Width(w.font, size, w.text) <= width {
+		return []word{w}
+	}
+	var parts []word
+	start := 0
+	for i, r := range w.text {
+		end := i + utf8.RuneLen(r)
+		if i > start && pdf.Width(w.font, size, w.text[start:end]) > width {
+			parts = append(parts, word{text: w.text[start:i], font: w.font})
+			start = i
+		}
+	}
+	return append(parts, word{text: w.text[start:], font: w.font})
+}
+
+// runs junta as palavras consecutivas de mesma fonte em um único trecho de texto
+func runs(line []word) []word {
+	var out []word
+	for _, w := range line {
+		if n := len(out); n > 0 && out[n-1].font == w.font {
+			out[n-1].text += " " + w.text
+			continue
+		}
+		out = append(out, w)
+	}
+	return out
+}
+
+// truncate corta s com reticências para caber em width pontos no rodapé
+func truncate(s string, width float64) string {
+	if pdf.Width(pdf.Helvetica, pdfFooterSize, s) <= width {
+		return s
+	}
+	for i := len(s); i > 0; i-- {
+		if !utf8.RuneStart(s[i-1]) {
+			continue
+		}
+		if cut := s[:i-1] + "…"; pdf.Width(pdf.Helvetica, pdfFooterSize, cut) <= width {
+			return cut
+		}
+	}
+	return ""
+}
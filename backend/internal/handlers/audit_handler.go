@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/conquista-ai/conquista-ai/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	repo *repositories.AuditRepository
+}
+
+func NewAuditHandler(repo *repositories.AuditRepository) *AuditHandler {
+	return &AuditHandler{repo: repo}
+}
+
+// List retorna o log de auditoria, do mais recente para o mais antigo
+// (?entity=okr&id=&action=&limit=&cursor=). id exige entity
+func (h *AuditHandler) List(c *gin.Context) {
+	filter := models.AuditEventFilter{
+		Entity: c.Query("entity"),
+		Action: c.Query("action"),
+		Cursor: c.Query("cursor"),
+	}
+	if filter.Entity != "" && !slices.Contains(models.AuditEntities, filter.Entity) {
+		c.Error(apperrors.InvalidField("entity", "entidade inválida: "+filter.Entity))
+		return
+	}
+	if filter.Action != "" && !slices.Contains([]string{models.AuditActionCreate, models.AuditActionUpdate, models.AuditActionDelete, models.AuditActionRestore}, filter.Action) {
+		c.Error(apperrors.InvalidField("action", "ação inválida: "+filter.Action))
+		return
+	}
+	if raw := c.Query("id"); raw != "" {
+		if filter.Entity == "" {
+			c.Error(apperrors.InvalidField("entity", "informe entity para filtrar por id"))
+			return
+		}
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id <= 0 {
+			c.Error(apperrors.InvalidField("id", "id inválido: "+raw))
+			return
+		}
+		filter.EntityID = &id
+	}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			c.Error(apperrors.InvalidField("limit", "limit inválido: "+raw))
+			return
+		}
+		filter.Limit = limit
+	}
+
+	page, err := h.repo.List(c.Request.Context(), filter)
+	if err != nil {
+		c.Error(apperrors.Wrap("erro ao listar atividade", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/conquista-ai/conquista-ai/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type BackupHandler struct {
+	service *services.BackupService
+}
+
+func NewBackupHandler(service *services.BackupService) *BackupHandler {
+	return &BackupHandler{service: service}
+}
+
+// Export retorna todos os dados como um arquivo JSON para download
+func (h *BackupHandler) Export(c *gin.Context) {
+	backup, err := h.service.Export(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	filename := fmt.Sprintf("conquista-ai-backup-%s.json", backup.ExportedAt.Format("2006-01-02"))
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.JSON(http.StatusOK, backup)
+}
+
+// Import grava um arquivo gerado por Export (?strategy=skip|overwrite|duplicate&dry_run=true)
+func (h *BackupHandler) Import(c *gin.Context) {
+	dryRun := false
+	if raw := c.Query("dry_run"); raw != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(raw); err != nil {
+			c.Error(apperrors.InvalidField("dry_run", "dry_run inválido: "+raw))
+			return
+		}
+	}
+
+	var backup models.Backup
+	if err := bindJSON(c, &backup); err != nil {
+		c.Error(err)
+		return
+	}
+
+	result, err := h.service.Import(c.Request.Context(), &backup, c.Query("strategy"), dryRun)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
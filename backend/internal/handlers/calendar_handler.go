@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/conquista-ai/conquista-ai/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+const calendarFeedPath = "/api/v1/calendar.ics"
+
+type CalendarHandler struct {
+	service *services.CalendarService
+}
+
+func NewCalendarHandler(service *services.CalendarService) *CalendarHandler {
+	return &CalendarHandler{service: service}
+}
+
+// Feed retorna o calendário iCalendar. Como aplicativos de calendário não enviam
+// cabeçalhos de autenticação, o token secreto vai no parâmetro token
+func (h *CalendarHandler) Feed(c *gin.Context) {
+	calendar, err := h.service.Feed(c.Request.Context(), c.Query("token"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Content-Type", "text/calendar; charset=utf-8")
+	c.Header("Content-Disposition", `inline; filename="conquista-ai.ics"`)
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Status(http.StatusOK)
+	if err := calendar.Write(c.Writer); err != nil {
+		c.Error(err)
+	}
+}
+
+// RotateToken gera um novo token para o feed e revoga o anterior. O token só é
+// exibido nesta resposta
+func (h *CalendarHandler) RotateToken(c *gin.Context) {
+	token, createdAt, err := h.service.RotateFeedToken(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.CalendarFeedToken{
+		Token:     token,
+		FeedURL:   feedURL(c, token),
+		CreatedAt: createdAt,
+	})
+}
+
+// feedURL monta a URL de assinatura do feed com o token
+func feedURL(c *gin.Context, token string) string {
+	return absoluteURL(c, calendarFeedPath, url.Values{"token": {token}}.Encode())
+}
+
+// absoluteURL monta uma URL a partir do host da requisição, respeitando o esquema
+// informado pelo proxy reverso
+func absoluteURL(c *gin.Context, path, rawQuery string) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	u := url.URL{
+		Scheme:   scheme,
+		Host:     c.Request.Host,
+		Path:     path,
+		RawQuery: rawQuery,
+	}
+	return u.String()
+}
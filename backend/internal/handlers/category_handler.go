@@ -2,9 +2,9 @@ package handlers
 
 import (
 	"net/http"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
 	"github.com/conquista-ai/conquista-ai/internal/models"
 	"github.com/conquista-ai/conquista-ai/internal/repositories"
 )
@@ -19,13 +19,13 @@ func NewCategoryHandler(repo *repositories.CategoryRepository) *CategoryHandler
 
 func (h *CategoryHandler) Create(c *gin.Context) {
 	// Categorias são fixas e não podem ser criadas
-	c.JSON(http.StatusForbidden, gin.H{"error": "categorias são fixas e não podem ser criadas. Use apenas: Pessoal, Profissional ou Social"})
+	c.Error(apperrors.Forbidden("categorias são fixas e não podem ser criadas. Use apenas: Pessoal, Profissional ou Social"))
 }
 
 func (h *CategoryHandler) GetAll(c *gin.Context) {
-	categories, err := h.repo.GetAll()
+	categories, err := h.repo.GetAll(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "erro ao buscar categorias"})
+		c.Error(apperrors.Internal("erro ao buscar categorias", err))
 		return
 	}
 
@@ -33,20 +33,20 @@ func (h *CategoryHandler) GetAll(c *gin.Context) {
 }
 
 func (h *CategoryHandler) GetByID(c *gin.Context) {
-	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	id, err := parseIDParam(c, "id")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		c.Error(err)
 		return
 	}
 
-	category, err := h.repo.GetByID(id)
+	category, err := h.repo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "erro ao buscar categoria"})
+		c.Error(apperrors.Internal("erro ao buscar categoria", err))
 		return
 	}
 
 	if category == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "categoria não encontrada"})
+		c.Error(apperrors.NotFound("categoria não encontrada"))
 		return
 	}
 
@@ -54,32 +54,32 @@ func (h *CategoryHandler) GetByID(c *gin.Context) {
 }
 
 func (h *CategoryHandler) Update(c *gin.Context) {
-	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	id, err := parseIDParam(c, "id")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		c.Error(err)
 		return
 	}
 
 	var req models.UpdateCategoryRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "dados inválidos"})
+	if err := bindJSON(c, &req); err != nil {
+		c.Error(err)
 		return
 	}
 
-	category, err := h.repo.GetByID(id)
+	category, err := h.repo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "erro ao buscar categoria"})
+		c.Error(apperrors.Internal("erro ao buscar categoria", err))
 		return
 	}
 
 	if category == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "categoria não encontrada"})
+		c.Error(apperrors.NotFound("categoria não encontrada"))
 		return
 	}
 
 	category.Name = req.Name
-	if err := h.repo.Update(category); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "erro ao atualizar categoria"})
+	if err := h.repo.Update(c.Request.Context(), category); err != nil {
+		c.Error(apperrors.Wrap("erro ao atualizar categoria", err))
 		return
 	}
 
@@ -88,6 +88,5 @@ func (h *CategoryHandler) Update(c *gin.Context) {
 
 func (h *CategoryHandler) Delete(c *gin.Context) {
 	// Categorias são fixas e não podem ser deletadas
-	c.JSON(http.StatusForbidden, gin.H{"error": "categorias são fixas e não podem ser deletadas"})
+	c.Error(apperrors.Forbidden("categorias são fixas e não podem ser deletadas"))
 }
-
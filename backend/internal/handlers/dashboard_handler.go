@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+	"github.com/conquista-ai/conquista-ai/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type DashboardHandler struct {
+	service *services.DashboardService
+}
+
+func NewDashboardHandler(service *services.DashboardService) *DashboardHandler {
+	return &DashboardHandler{service: service}
+}
+
+// Get retorna totais, taxas de conclusão por categoria, progresso de cada OKR, prazos
+// próximos, atividade recente e o progresso de roadmaps e trilhas
+func (h *DashboardHandler) Get(c *gin.Context) {
+	dashboard, err := h.service.Get(c.Request.Context())
+	if err != nil {
+		c.Error(apperrors.Internal("erro ao calcular dashboard", err))
+		return
+	}
+
+	if ttl := int(h.service.TTL().Seconds()); ttl > 0 {
+		c.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", ttl))
+	}
+	c.JSON(http.StatusOK, dashboard)
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/conquista-ai/conquista-ai/internal/health"
+	"github.com/conquista-ai/conquista-ai/internal/version"
+	"github.com/gin-gonic/gin"
+)
+
+type HealthHandler struct {
+	checker *health.Checker
+}
+
+func NewHealthHandler(checker *health.Checker) *HealthHandler {
+	return &HealthHandler{checker: checker}
+}
+
+// Livez indica apenas que o processo está respondendo; não verifica dependências
+func (h *HealthHandler) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":  health.StatusOK,
+		"service": "conquista-ai",
+	})
+}
+
+// Readyz verifica o banco e o Spellbook (com cache) e responde 503 quando alguma
+// dependência falha ou quando o desligamento já foi iniciado
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	if h.checker.Draining() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status": "draining",
+		})
+		return
+	}
+
+	report := h.checker.Ready(c.Request.Context())
+	status := http.StatusOK
+	if report.Status != health.StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+
+	c.JSON(status, report)
+}
+
+// Version retorna as informações de build do binário
+func (h *HealthHandler) Version(c *gin.Context) {
+	c.JSON(http.StatusOK, version.Get())
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+	"github.com/conquista-ai/conquista-ai/internal/document"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Faz o validador reportar os campos pelo nome JSON (ex.: category_id) em vez do nome Go
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "" || name == "-" {
+				return field.Name
+			}
+			return name
+		})
+	}
+}
+
+// parseIDParam lê um parâmetro de rota numérico
+func parseIDParam(c *gin.Context, name string) (int64, error) {
+	id, err := strconv.ParseInt(c.Param(name), 10, 64)
+	if err != nil {
+		return 0, apperrors.InvalidField(name, "ID inválido")
+	}
+	return id, nil
+}
+
+// parseDateField interpreta uma data no formato YYYY-MM-DD enviada no campo field
+func parseDateField(field, value string) (time.Time, error) {
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, apperrors.InvalidField(field, "data inválida. Use YYYY-MM-DD")
+	}
+	return date, nil
+}
+
+// bindJSON decodifica o corpo da requisição e converte falhas de validação
+// em um erro de domínio com os detalhes de cada campo
+func bindJSON(c *gin.Context, req interface{}) error {
+	if err := c.ShouldBindJSON(req); err != nil {
+		return bindingError(err)
+	}
+	return nil
+}
+
+// bindOptionalJSON é como bindJSON, mas aceita corpo vazio mantendo os valores padrão de req
+func bindOptionalJSON(c *gin.Context, req interface{}) error {
+	if c.Request.ContentLength == 0 {
+		return binding.Validator.ValidateStruct(req)
+	}
+	return bindJSON(c, req)
+}
+
+func bindingError(err error) error {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return apperrors.Validation("dados inválidos: corpo da requisição malformado")
+	}
+
+	fields := make([]apperrors.FieldError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		fields = append(fields, apperrors.FieldError{
+			Field:   fe.Field(),
+			Message: fieldErrorMessage(fe),
+		})
+	}
+	return apperrors.Validation("dados inválidos", fields...)
+}
+
+func fieldErrorMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "campo obrigatório"
+	case "min":
+		return "deve ser no mínimo " + fe.Param()
+	case "max":
+		return "deve ser no máximo " + fe.Param()
+	case "email":
+		return "e-mail inválido"
+	case "url":
+		return "URL inválida"
+	case "gt":
+		return "deve ser maior que " + fe.Param()
+	case "oneof":
+		return "deve ser um de: " + fe.Param()
+	default:
+		return "valor inválido"
+	}
+}
+
+// writeDocument responde com o documento no formato pedido em ?format (md, o padrão,
+// ou pdf) como arquivo para download. O documento é gerado em memória antes da resposta
+// para que erros ainda possam ser devolvidos como JSON
+func writeDocument(c *gin.Context, doc *document.Document, basename string) {
+	var buf bytes.Buffer
+	var contentType string
+	var err error
+
+	format := c.DefaultQuery("format", "md")
+	switch format {
+	case "md":
+		contentType = "text/markdown; charset=utf-8"
+		err = doc.WriteMarkdown(&buf)
+	case "pdf":
+		contentType = "application/pdf"
+		err = doc.WritePDF(&buf)
+	default:
+		c.Error(apperrors.InvalidField("format", "format inválido: "+format+". Use md ou pdf"))
+		return
+	}
+	if err != nil {
+		c.Error(apperrors.Wrap("erro ao gerar documento", err))
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+basename+"."+format+`"`)
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/conquista-ai/conquista-ai/internal/repositories"
+	"github.com/conquista-ai/conquista-ai/internal/spreadsheet"
+	"github.com/gin-gonic/gin"
+)
+
+var keyResultExportHeader = []any{
+	"Objetivo", "Categoria", "Status do OKR", "Key Result", "Status", "Data esperada", "Roadmap (%)", "Trilhas (%)",
+}
+
+var okrStatusLabels = map[string]string{
+	models.OKRStatusDraft:     "Rascunho",
+	models.OKRStatusActive:    "Ativo",
+	models.OKRStatusOnHold:    "Pausado",
+	models.OKRStatusCompleted: "Concluído",
+	models.OKRStatusAbandoned: "Abandonado",
+}
+
+// ExportCSV baixa os Key Results em CSV, com os mesmos filtros de GetAll. O separador
+// padrão é vírgula; ?delimiter=; gera o formato esperado pelo Excel em português
+func (h *KeyResultHandler) ExportCSV(c *gin.Context) {
+	comma := ','
+	switch delimiter := c.DefaultQuery("delimiter", ","); delimiter {
+	case ",":
+	case ";":
+		comma = ';'
+	default:
+		c.Error(apperrors.InvalidField("delimiter", "delimiter inválido: "+delimiter+". Use , ou ;"))
+		return
+	}
+
+	h.export(c, "text/csv; charset=utf-8", "conquista-ai-key-results.csv", func(w io.Writer) (spreadsheet.Writer, error) {
+		return spreadsheet.NewCSV(w, comma)
+	})
+}
+
+// ExportXLSX baixa os Key Results em XLSX, com os mesmos filtros de GetAll
+func (h *KeyResultHandler) ExportXLSX(c *gin.Context) {
+	h.export(c, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "conquista-ai-key-results.xlsx", func(w io.Writer) (spreadsheet.Writer, error) {
+		return spreadsheet.NewXLSX(w, "Key Results")
+	})
+}
+
+// export grava a planilha à medida que as linhas chegam do banco. A resposta só começa
+// na primeira linha (ou ao fim de uma consulta vazia), de modo que erros de filtro ou de
+// consulta ainda voltam como JSON; depois disso um erro apenas interrompe o download
+func (h *KeyResultHandler) export(c *gin.Context, contentType, filename string, newWriter func(io.Writer) (spreadsheet.Writer, error)) {
+	query, err := parseListQuery(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	filter := query.keyResultFilter()
+	if raw := c.Query("okr_id"); raw != "" {
+		okrID, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			c.Error(apperrors.InvalidField("okr_id", "okr_id inválido"))
+			return
+		}
+		filter.OKRID = &okrID
+	}
+
+	var sheet spreadsheet.Writer
+	start := func() error {
+		c.Header("Content-Type", contentType)
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+		var err error
+		if sheet, err = newWriter(c.Writer); err != nil {
+			return err
+		}
+		return sheet.WriteRow(keyResultExportHeader...)
+	}
+
+	err = h.repo.EachExportRow(c.Request.Context(), filter, func(row repositories.KeyResultExportRow) error {
+		if sheet == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		status := "Pendente"
+		if row.Completed {
+			status = "Concluído"
+		}
+		return sheet.WriteRow(
+			row.Objective,
+			row.Category,
+			okrStatusLabels[row.OKRStatus],
+			row.Title,
+			status,
+			row.ExpectedCompletionDate,
+			row.RoadmapProgress,
+			row.TrailProgress,
+		)
+	})
+	if err == nil && sheet == nil {
+		err = start()
+	}
+	if err == nil {
+		err = sheet.Close()
+	}
+	if err != nil {
+		c.Error(apperrors.Wrap("erro ao exportar Key Results", err))
+	}
+}
@@ -1,43 +1,57 @@
 package handlers
 
 import (
-	"fmt"
 	"net/http"
 	"strconv"
 	"time"
 
-	"github.com/gin-gonic/gin"
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
 	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/conquista-ai/conquista-ai/internal/planning"
 	"github.com/conquista-ai/conquista-ai/internal/repositories"
+	"github.com/gin-gonic/gin"
 )
 
 type KeyResultHandler struct {
-	repo     *repositories.KeyResultRepository
-	okrRepo  *repositories.OKRRepository
+	repo    *repositories.KeyResultRepository
+	okrRepo *repositories.OKRRepository
+	planner *planning.Planner
 }
 
-func NewKeyResultHandler(repo *repositories.KeyResultRepository, okrRepo *repositories.OKRRepository) *KeyResultHandler {
+func NewKeyResultHandler(repo *repositories.KeyResultRepository, okrRepo *repositories.OKRRepository, planner *planning.Planner) *KeyResultHandler {
 	return &KeyResultHandler{
 		repo:    repo,
 		okrRepo: okrRepo,
+		planner: planner,
+	}
+}
+
+// withoutExpectedDate retorna os Key Results sem data esperada de conclusão definida
+func withoutExpectedDate(keyResults []models.KeyResult) []models.KeyResult {
+	undated := make([]models.KeyResult, 0, len(keyResults))
+	for _, kr := range keyResults {
+		if kr.ExpectedCompletionDate == nil {
+			undated = append(undated, kr)
+		}
 	}
+	return undated
 }
 
 func (h *KeyResultHandler) Create(c *gin.Context) {
 	var req models.CreateKeyResultRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "dados inválidos"})
+	if err := bindJSON(c, &req); err != nil {
+		c.Error(err)
 		return
 	}
 
 	// Validar se o OKR existe
-	okr, err := h.okrRepo.GetByID(req.OKRID)
+	okr, err := h.okrRepo.GetByID(c.Request.Context(), req.OKRID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "erro ao buscar OKR"})
+		c.Error(apperrors.Internal("erro ao buscar OKR", err))
 		return
 	}
 	if okr == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "OKR não encontrado"})
+		c.Error(apperrors.NotFound("OKR não encontrado"))
 		return
 	}
 
@@ -47,68 +61,35 @@ func (h *KeyResultHandler) Create(c *gin.Context) {
 		Completed: false,
 	}
 
+	if req.Difficulty != nil {
+		keyResult.Difficulty = *req.Difficulty
+	}
+
 	// Processar expected_completion_date se fornecido
 	if req.ExpectedCompletionDate != nil && *req.ExpectedCompletionDate != "" {
 		parsedDate, err := time.Parse("2006-01-02", *req.ExpectedCompletionDate)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "formato de data de conclusão inválido. Use YYYY-MM-DD"})
+			c.Error(apperrors.InvalidField("expected_completion_date", "formato de data de conclusão inválido. Use YYYY-MM-DD"))
 			return
 		}
 		keyResult.ExpectedCompletionDate = &parsedDate
-	} else {
-		// Se não foi fornecida data, calcular automaticamente baseado no OKR
-		if okr.CompletionDate != nil {
-			// Buscar todos os Key Results existentes do OKR
-			existingKeyResults, err := h.repo.GetByOKRID(req.OKRID)
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "erro ao buscar Key Results existentes"})
-				return
-			}
-			
-			// Contar quantos Key Results não têm data definida (incluindo o que está sendo criado)
-			keyResultsWithoutDate := 0
-			for _, kr := range existingKeyResults {
-				if kr.ExpectedCompletionDate == nil {
-					keyResultsWithoutDate++
-				}
-			}
-			// Adicionar 1 para o Key Result que está sendo criado
-			keyResultsWithoutDate++
-			
-			now := time.Now()
-			completionDate := *okr.CompletionDate
-			daysRemaining := int(completionDate.Sub(now).Hours() / 24)
-			
-			if daysRemaining > 0 && keyResultsWithoutDate > 0 {
-				// Dividir o tempo pelo número de Key Results sem data
-				daysPerKeyResult := daysRemaining / keyResultsWithoutDate
-				
-				// Calcular a posição deste Key Result na sequência (último)
-				position := keyResultsWithoutDate - 1
-				
-				// Calcular dias acumulados até este Key Result
-				accumulatedDays := 0
-				for i := 0; i <= position; i++ {
-					daysForThisKR := daysPerKeyResult
-					// Se houver resto na divisão, distribuir nos primeiros Key Results
-					if i < daysRemaining%keyResultsWithoutDate {
-						daysForThisKR++
-					}
-					accumulatedDays += daysForThisKR
-				}
-				
-				// Calcular data esperada: hoje + dias acumulados até este Key Result
-				expectedDate := now.AddDate(0, 0, accumulatedDays)
-				keyResult.ExpectedCompletionDate = &expectedDate
-			} else if daysRemaining > 0 {
-				// Se a data já passou, usar a data de conclusão do OKR
-				keyResult.ExpectedCompletionDate = okr.CompletionDate
-			}
+	} else if okr.CompletionDate != nil {
+		// Se não foi fornecida data, calcular automaticamente baseado no OKR: o novo Key
+		// Result entra no fim da sequência dos que ainda não têm data definida
+		existingKeyResults, err := h.repo.GetByOKRID(c.Request.Context(), req.OKRID)
+		if err != nil {
+			c.Error(apperrors.Internal("erro ao buscar Key Results existentes", err))
+			return
 		}
+
+		undated := append(withoutExpectedDate(existingKeyResults), *keyResult)
+		dueDates := h.planner.DueDates(*okr.CompletionDate, planning.KeyResultWeights(undated))
+		keyResult.ExpectedCompletionDate = &dueDates[len(dueDates)-1]
+		keyResult.DueDateComputed = true
 	}
 
-	if err := h.repo.Create(keyResult); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "erro ao criar Key Result"})
+	if err := h.repo.Create(c.Request.Context(), keyResult); err != nil {
+		c.Error(apperrors.Internal("erro ao criar Key Result", err))
 		return
 	}
 
@@ -116,102 +97,53 @@ func (h *KeyResultHandler) Create(c *gin.Context) {
 }
 
 func (h *KeyResultHandler) GetByOKRID(c *gin.Context) {
-	okrID, err := strconv.ParseInt(c.Param("id"), 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
-		return
-	}
-
-	keyResults, err := h.repo.GetByOKRID(okrID)
+	okrID, err := parseIDParam(c, "id")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "erro ao buscar Key Results"})
+		c.Error(err)
 		return
 	}
 
-	// Buscar OKR para calcular expected_completion_date
-	okr, err := h.okrRepo.GetByID(okrID)
+	keyResults, err := h.repo.GetByOKRID(c.Request.Context(), okrID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "erro ao buscar OKR"})
+		c.Error(apperrors.Internal("erro ao buscar Key Results", err))
 		return
 	}
 
-	// Calcular expected_completion_date apenas para Key Results que não têm data definida
-	if okr != nil && okr.CompletionDate != nil && len(keyResults) > 0 {
-		now := time.Now()
-		completionDate := *okr.CompletionDate
-		
-		// Calcular dias restantes do OKR
-		daysRemaining := int(completionDate.Sub(now).Hours() / 24)
-		
-		// Contar quantos Key Results não têm data definida
-		keyResultsWithoutDate := make([]int, 0)
-		for i := range keyResults {
-			if keyResults[i].ExpectedCompletionDate == nil {
-				keyResultsWithoutDate = append(keyResultsWithoutDate, i)
-			}
-		}
-		
-		if len(keyResultsWithoutDate) > 0 && daysRemaining > 0 {
-			// Dividir o tempo pelo número de Key Results sem data
-			daysPerKeyResult := daysRemaining / len(keyResultsWithoutDate)
-			
-			// Distribuir progressivamente apenas para os que não têm data
-			accumulatedDays := 0
-			for idx, i := range keyResultsWithoutDate {
-				// Calcular dias acumulados até este Key Result
-				daysForThisKR := daysPerKeyResult
-				
-				// Se houver resto na divisão, distribuir nos primeiros Key Results
-				if idx < daysRemaining%len(keyResultsWithoutDate) {
-					daysForThisKR++
-				}
-				
-				accumulatedDays += daysForThisKR
-				
-				// Calcular data esperada: hoje + dias acumulados até este Key Result
-				expectedDate := now.AddDate(0, 0, accumulatedDays)
-				keyResults[i].ExpectedCompletionDate = &expectedDate
-			}
-		} else if len(keyResultsWithoutDate) > 0 {
-			// Se a data já passou, usar a data de conclusão do OKR
-			for _, i := range keyResultsWithoutDate {
-				keyResults[i].ExpectedCompletionDate = okr.CompletionDate
-			}
-		}
-	}
-
 	c.JSON(http.StatusOK, keyResults)
 }
 
 func (h *KeyResultHandler) Update(c *gin.Context) {
-	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	id, err := parseIDParam(c, "id")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		c.Error(err)
 		return
 	}
 
 	var req models.UpdateKeyResultRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "dados inválidos"})
+	if err := bindJSON(c, &req); err != nil {
+		c.Error(err)
 		return
 	}
 
-	kr, err := h.repo.GetByID(id)
+	kr, err := h.repo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "erro ao buscar Key Result"})
+		c.Error(apperrors.Internal("erro ao buscar Key Result", err))
 		return
 	}
 
 	if kr == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Key Result não encontrado"})
+		c.Error(apperrors.NotFound("Key Result não encontrado"))
 		return
 	}
 
 	kr.Title = req.Title
 	kr.Completed = req.Completed
+	if req.Difficulty != nil {
+		kr.Difficulty = *req.Difficulty
+	}
 
-	if err := h.repo.Update(kr); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "erro ao atualizar Key Result"})
+	if err := h.repo.Update(c.Request.Context(), kr); err != nil {
+		c.Error(apperrors.Wrap("erro ao atualizar Key Result", err))
 		return
 	}
 
@@ -219,51 +151,76 @@ func (h *KeyResultHandler) Update(c *gin.Context) {
 }
 
 func (h *KeyResultHandler) Delete(c *gin.Context) {
-	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	id, err := parseIDParam(c, "id")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		c.Error(err)
 		return
 	}
 
-	if err := h.repo.Delete(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "erro ao deletar Key Result"})
+	if err := h.repo.Delete(c.Request.Context(), id); err != nil {
+		c.Error(apperrors.Wrap("erro ao deletar Key Result", err))
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Key Result deletado com sucesso"})
 }
 
-// GetAll retorna todos os Key Results com informações do OKR, ordenados por data de expiração
+// GetAll retorna uma página de Key Results com informações do OKR, ordenados por data de expiração por padrão
 func (h *KeyResultHandler) GetAll(c *gin.Context) {
-	keyResultsWithOKR, err := h.repo.GetAllWithOKR()
+	query, err := parseListQuery(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("erro ao buscar Key Results: %v", err)})
+		c.Error(err)
 		return
 	}
 
-	// Converter para formato de resposta JSON
-	type KeyResultResponse struct {
-		ID                   int64      `json:"id"`
-		OKRID                int64      `json:"okr_id"`
-		Title                string     `json:"title"`
-		Completed            bool       `json:"completed"`
-		ExpectedCompletionDate *string   `json:"expected_completion_date,omitempty"`
-		CreatedAt            string     `json:"created_at"`
-		UpdatedAt            string     `json:"updated_at"`
-		OKRTitle             string     `json:"okr_title"`
-		OKRCompletionDate    *string    `json:"okr_completion_date,omitempty"`
+	filter := query.keyResultFilter()
+	if raw := c.Query("okr_id"); raw != "" {
+		okrID, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			c.Error(apperrors.InvalidField("okr_id", "okr_id inválido"))
+			return
+		}
+		filter.OKRID = &okrID
+	}
+
+	page, err := h.repo.ListWithOKR(c.Request.Context(), filter)
+	if err != nil {
+		c.Error(apperrors.Wrap("erro ao buscar Key Results", err))
+		return
 	}
 
-	response := make([]KeyResultResponse, 0, len(keyResultsWithOKR))
-	for _, krw := range keyResultsWithOKR {
+	// Converter para formato de resposta JSON
+	type KeyResultResponse struct {
+		ID                     int64   `json:"id"`
+		OKRID                  int64   `json:"okr_id"`
+		Title                  string  `json:"title"`
+		Completed              bool    `json:"completed"`
+		Difficulty             int     `json:"difficulty"`
+		ExpectedCompletionDate *string `json:"expected_completion_date,omitempty"`
+		DueDateComputed        bool    `json:"due_date_computed"`
+		CreatedAt              string  `json:"created_at"`
+		UpdatedAt              string  `json:"updated_at"`
+		OKRTitle               string  `json:"okr_title"`
+		OKRCompletionDate      *string `json:"okr_completion_date,omitempty"`
+	}
+
+	response := models.Page[KeyResultResponse]{
+		Items:      make([]KeyResultResponse, 0, len(page.Items)),
+		Total:      page.Total,
+		Limit:      page.Limit,
+		NextCursor: page.NextCursor,
+	}
+	for _, krw := range page.Items {
 		kr := KeyResultResponse{
-			ID:        krw.KeyResult.ID,
-			OKRID:     krw.KeyResult.OKRID,
-			Title:     krw.KeyResult.Title,
-			Completed: krw.KeyResult.Completed,
-			CreatedAt: krw.KeyResult.CreatedAt.Format(time.RFC3339),
-			UpdatedAt: krw.KeyResult.UpdatedAt.Format(time.RFC3339),
-			OKRTitle:  krw.OKRTitle,
+			ID:              krw.KeyResult.ID,
+			OKRID:           krw.KeyResult.OKRID,
+			Title:           krw.KeyResult.Title,
+			Completed:       krw.KeyResult.Completed,
+			Difficulty:      krw.KeyResult.Difficulty,
+			DueDateComputed: krw.KeyResult.DueDateComputed,
+			CreatedAt:       krw.KeyResult.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:       krw.KeyResult.UpdatedAt.Format(time.RFC3339),
+			OKRTitle:        krw.OKRTitle,
 		}
 
 		if krw.KeyResult.ExpectedCompletionDate != nil {
@@ -276,9 +233,8 @@ func (h *KeyResultHandler) GetAll(c *gin.Context) {
 			kr.OKRCompletionDate = &dateStr
 		}
 
-		response = append(response, kr)
+		response.Items = append(response.Items, kr)
 	}
 
 	c.JSON(http.StatusOK, response)
 }
-
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// cyclePattern aceita ciclos anuais ("2025") ou trimestrais ("2025-Q3")
+var cyclePattern = regexp.MustCompile(`^(\d{4})(?:-Q([1-4]))?$`)
+
+// listQuery reúne os parâmetros de query comuns às listagens de OKRs e Key Results
+type listQuery struct {
+	models.ListParams
+	CategoryID *int64
+	CycleStart *time.Time
+	CycleEnd   *time.Time
+	Completed  *bool
+	DueBefore  *time.Time
+	DueAfter   *time.Time
+	Search     string
+	Archived   *bool
+	Status     string
+}
+
+func parseListQuery(c *gin.Context) (*listQuery, error) {
+	q := &listQuery{
+		ListParams: models.ListParams{
+			Limit:  models.DefaultPageLimit,
+			Cursor: c.Query("cursor"),
+			Sort:   c.Query("sort"),
+		},
+		Search: strings.TrimSpace(c.Query("q")),
+	}
+
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return nil, apperrors.InvalidField("limit", "limit inválido: "+raw)
+		}
+		if limit > models.MaxPageLimit {
+			limit = models.MaxPageLimit
+		}
+		q.Limit = limit
+	}
+
+	if raw := c.Query("category_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, apperrors.InvalidField("category_id", "category_id inválido: "+raw)
+		}
+		q.CategoryID = &id
+	}
+
+	if raw := c.Query("cycle"); raw != "" {
+		start, end, err := parseCycle(raw)
+		if err != nil {
+			return nil, err
+		}
+		q.CycleStart = &start
+		q.CycleEnd = &end
+	}
+
+	if raw := c.Query("completed"); raw != "" {
+		completed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, apperrors.InvalidField("completed", "completed inválido: "+raw)
+		}
+		q.Completed = &completed
+	}
+
+	// Por padrão os OKRs arquivados (e seus Key Results) ficam de fora; archived=all
+	// lista todos
+	if raw := c.DefaultQuery("archived", "false"); raw != "all" {
+		archived, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, apperrors.InvalidField("archived", "archived inválido: "+raw+". Use true, false ou all")
+		}
+		q.Archived = &archived
+	}
+
+	if raw := c.Query("status"); raw != "" {
+		if !slices.Contains(models.OKRStatuses, raw) {
+			return nil, apperrors.InvalidField("status", "status inválido: "+raw)
+		}
+		q.Status = raw
+	}
+
+	var err error
+	if q.DueBefore, err = parseDateQuery(c, "due_before"); err != nil {
+		return nil, err
+	}
+	if q.DueAfter, err = parseDateQuery(c, "due_after"); err != nil {
+		return nil, err
+	}
+
+	return q, nil
+}
+
+func (q *listQuery) okrFilter() models.OKRFilter {
+	return models.OKRFilter{
+		ListParams: q.ListParams,
+		CategoryID: q.CategoryID,
+		CycleStart: q.CycleStart,
+		CycleEnd:   q.CycleEnd,
+		Completed:  q.Completed,
+		DueBefore:  q.DueBefore,
+		DueAfter:   q.DueAfter,
+		Search:     q.Search,
+		Archived:   q.Archived,
+		Status:     q.Status,
+	}
+}
+
+func (q *listQuery) keyResultFilter() models.KeyResultFilter {
+	return models.KeyResultFilter{
+		ListParams: q.ListParams,
+		CategoryID: q.CategoryID,
+		CycleStart: q.CycleStart,
+		CycleEnd:   q.CycleEnd,
+		Completed:  q.Completed,
+		DueBefore:  q.DueBefore,
+		DueAfter:   q.DueAfter,
+		Search:     q.Search,
+		Archived:   q.Archived,
+		Status:     q.Status,
+	}
+}
+
+// parseCycle converte um ciclo ("2025" ou "2025-Q3") no intervalo [início, fim)
+func parseCycle(raw string) (time.Time, time.Time, error) {
+	matches := cyclePattern.FindStringSubmatch(raw)
+	if matches == nil {
+		return time.Time{}, time.Time{}, apperrors.InvalidField("cycle", "cycle inválido: "+raw+". Use YYYY ou YYYY-QN")
+	}
+
+	year, _ := strconv.Atoi(matches[1])
+	if matches[2] == "" {
+		start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(1, 0, 0), nil
+	}
+
+	quarter, _ := strconv.Atoi(matches[2])
+	start := time.Date(year, time.Month((quarter-1)*3+1), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 3, 0), nil
+}
+
+func parseDateQuery(c *gin.Context, key string) (*time.Time, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+
+	date, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		return nil, apperrors.InvalidField(key, key+" inválido: "+raw+". Use YYYY-MM-DD")
+	}
+	return &date, nil
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/conquista-ai/conquista-ai/internal/notifications"
+	"github.com/conquista-ai/conquista-ai/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultInboxLimit é a quantidade de lembretes retornada quando limit não é informado
+const defaultInboxLimit = 50
+
+type NotificationHandler struct {
+	repo    *repositories.NotificationRepository
+	service *notifications.Service
+}
+
+func NewNotificationHandler(repo *repositories.NotificationRepository, service *notifications.Service) *NotificationHandler {
+	return &NotificationHandler{repo: repo, service: service}
+}
+
+// Inbox lista a caixa de entrada. Parâmetros: unread (true para apenas não lidos) e limit
+func (h *NotificationHandler) Inbox(c *gin.Context) {
+	unreadOnly := false
+	if raw := c.Query("unread"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.Error(apperrors.InvalidField("unread", "use true ou false"))
+			return
+		}
+		unreadOnly = parsed
+	}
+
+	limit := defaultInboxLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > models.MaxPageLimit {
+			c.Error(apperrors.InvalidField("limit", "deve ser um número entre 1 e "+strconv.Itoa(models.MaxPageLimit)))
+			return
+		}
+		limit = parsed
+	}
+
+	inbox, err := h.repo.Inbox(c.Request.Context(), unreadOnly, limit)
+	if err != nil {
+		c.Error(apperrors.Internal("erro ao buscar notificações", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, inbox)
+}
+
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.repo.MarkRead(c.Request.Context(), id); err != nil {
+		c.Error(apperrors.Wrap("erro ao marcar notificação como lida", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "notificação marcada como lida"})
+}
+
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	if err := h.repo.MarkAllRead(c.Request.Context()); err != nil {
+		c.Error(apperrors.Internal("erro ao marcar notificações como lidas", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "notificações marcadas como lidas"})
+}
+
+// SendTest envia uma notificação de teste por cada canal ativado
+func (h *NotificationHandler) SendTest(c *gin.Context) {
+	results, err := h.service.SendTest(c.Request.Context())
+	if err != nil {
+		c.Error(apperrors.Wrap("erro ao enviar notificação de teste", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	prefs, err := h.repo.GetPreferences(c.Request.Context())
+	if err != nil {
+		c.Error(apperrors.Internal("erro ao buscar preferências de notificação", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
+
+// UpdatePreferences substitui as preferências. Sem kinds, todos os tipos de lembrete
+// ficam ativos; envie uma lista vazia para desativar todos
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	var req models.UpdateNotificationPreferencesRequest
+	if err := bindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	var problems []apperrors.FieldError
+	invalid := func(field, message string) {
+		problems = append(problems, apperrors.FieldError{Field: field, Message: message})
+	}
+
+	if req.EmailEnabled && req.EmailAddress == "" {
+		invalid("email_address", "obrigatório quando email_enabled é true")
+	}
+	if req.WebhookEnabled && req.WebhookURL == "" {
+		invalid("webhook_url", "obrigatória quando webhook_enabled é true")
+	}
+	if req.WebhookURL != "" {
+		if u, err := url.Parse(req.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			invalid("webhook_url", "deve ser uma URL http(s)")
+		}
+	}
+	if (req.QuietHoursStart == nil) != (req.QuietHoursEnd == nil) {
+		invalid("quiet_hours_end", "informe o início e o fim do horário de silêncio")
+	}
+	if req.QuietHoursStart != nil {
+		if _, err := notifications.ParseClock(*req.QuietHoursStart); err != nil {
+			invalid("quiet_hours_start", "horário inválido. Use HH:MM")
+		}
+	}
+	if req.QuietHoursEnd != nil {
+		if _, err := notifications.ParseClock(*req.QuietHoursEnd); err != nil {
+			invalid("quiet_hours_end", "horário inválido. Use HH:MM")
+		}
+	}
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = models.DefaultNotificationTimezone
+	}
+	if _, err := notifications.LoadTimezone(timezone); err != nil {
+		invalid("timezone", "fuso horário inválido. Use um nome IANA, ex.: America/Sao_Paulo")
+	}
+	if len(problems) > 0 {
+		c.Error(apperrors.Validation("dados inválidos", problems...))
+		return
+	}
+
+	kinds := req.Kinds
+	if kinds == nil {
+		kinds = models.NotificationKinds
+	}
+
+	prefs := &models.NotificationPreferences{
+		InboxEnabled:         req.InboxEnabled,
+		EmailEnabled:         req.EmailEnabled,
+		EmailAddress:         req.EmailAddress,
+		WebhookEnabled:       req.WebhookEnabled,
+		WebhookURL:           req.WebhookURL,
+		Kinds:                slices.Compact(slices.Sorted(slices.Values(kinds))),
+		QuietHoursStart:      req.QuietHoursStart,
+		QuietHoursEnd:        req.QuietHoursEnd,
+		WeeklyCheckInWeekday: req.WeeklyCheckInWeekday,
+		Timezone:             timezone,
+	}
+	if err := h.repo.UpdatePreferences(c.Request.Context(), prefs); err != nil {
+		c.Error(apperrors.Internal("erro ao atualizar preferências de notificação", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
 	"github.com/conquista-ai/conquista-ai/internal/models"
 	"github.com/conquista-ai/conquista-ai/internal/services"
 )
@@ -19,14 +22,14 @@ func NewOKRHandler(service *services.OKRService) *OKRHandler {
 
 func (h *OKRHandler) Create(c *gin.Context) {
 	var req models.CreateOKRRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "dados inválidos"})
+	if err := bindJSON(c, &req); err != nil {
+		c.Error(err)
 		return
 	}
 
-	okr, err := h.service.CreateOKR(req)
+	okr, err := h.service.CreateOKR(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -34,44 +37,36 @@ func (h *OKRHandler) Create(c *gin.Context) {
 }
 
 func (h *OKRHandler) GetAll(c *gin.Context) {
-	categoryID := c.Query("category_id")
-	if categoryID != "" {
-		id, err := strconv.ParseInt(categoryID, 10, 64)
-		if err == nil {
-			okrs, err := h.service.GetOKRsByCategory(id)
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "erro ao buscar OKRs"})
-				return
-			}
-			c.JSON(http.StatusOK, okrs)
-			return
-		}
+	query, err := parseListQuery(c)
+	if err != nil {
+		c.Error(err)
+		return
 	}
 
-	okrs, err := h.service.GetAllOKRs()
+	page, err := h.service.ListOKRs(c.Request.Context(), query.okrFilter())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "erro ao buscar OKRs"})
+		c.Error(apperrors.Wrap("erro ao buscar OKRs", err))
 		return
 	}
 
-	c.JSON(http.StatusOK, okrs)
+	c.JSON(http.StatusOK, page)
 }
 
 func (h *OKRHandler) GetByID(c *gin.Context) {
-	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	id, err := parseIDParam(c, "id")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		c.Error(err)
 		return
 	}
 
-	okr, err := h.service.GetOKRByID(id)
+	okr, err := h.service.GetOKRByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "erro ao buscar OKR"})
+		c.Error(apperrors.Internal("erro ao buscar OKR", err))
 		return
 	}
 
 	if okr == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "OKR não encontrado"})
+		c.Error(apperrors.NotFound("OKR não encontrado"))
 		return
 	}
 
@@ -79,21 +74,21 @@ func (h *OKRHandler) GetByID(c *gin.Context) {
 }
 
 func (h *OKRHandler) Update(c *gin.Context) {
-	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	id, err := parseIDParam(c, "id")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		c.Error(err)
 		return
 	}
 
 	var req models.UpdateOKRRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "dados inválidos"})
+	if err := bindJSON(c, &req); err != nil {
+		c.Error(err)
 		return
 	}
 
-	okr, err := h.service.UpdateOKR(id, req)
+	okr, err := h.service.UpdateOKR(c.Request.Context(), id, req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -101,32 +96,169 @@ func (h *OKRHandler) Update(c *gin.Context) {
 }
 
 func (h *OKRHandler) Delete(c *gin.Context) {
-	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	id, err := parseIDParam(c, "id")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		c.Error(err)
 		return
 	}
 
-	if err := h.service.DeleteOKR(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "erro ao deletar OKR"})
+	if err := h.service.DeleteOKR(c.Request.Context(), id); err != nil {
+		c.Error(apperrors.Wrap("erro ao deletar OKR", err))
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "OKR deletado com sucesso"})
 }
 
+// Archive arquiva o OKR. Seus Key Results e roadmaps deixam as listagens junto com ele
+func (h *OKRHandler) Archive(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	okr, err := h.service.ArchiveOKR(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, okr)
+}
+
+func (h *OKRHandler) Unarchive(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	okr, err := h.service.UnarchiveOKR(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, okr)
+}
+
+// ChangeStatus aplica uma transição de status ao OKR ({"status": "abandoned", "reason": "..."})
+func (h *OKRHandler) ChangeStatus(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req models.ChangeOKRStatusRequest
+	if err := bindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	okr, err := h.service.ChangeOKRStatus(c.Request.Context(), id, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, okr)
+}
+
+// StatusHistory retorna as mudanças de status do OKR, da mais antiga para a mais recente
+func (h *OKRHandler) StatusHistory(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	history, err := h.service.OKRStatusHistory(c.Request.Context(), id)
+	if err != nil {
+		c.Error(apperrors.Wrap("erro ao buscar histórico de status", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
 func (h *OKRHandler) GenerateKeyResults(c *gin.Context) {
-	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	id, err := parseIDParam(c, "id")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		c.Error(err)
 		return
 	}
 
-	if err := h.service.GenerateKeyResults(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.service.GenerateKeyResults(c.Request.Context(), id); err != nil {
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Key Results gerados com sucesso"})
 }
 
+// Schedule distribui o prazo do OKR entre seus Key Results pendentes. Sem "apply": true
+// a resposta é apenas uma prévia das datas propostas
+func (h *OKRHandler) Schedule(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req models.ScheduleRequest
+	if err := bindOptionalJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	result, err := h.service.ScheduleKeyResults(c.Request.Context(), id, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ImportCSV importa OKRs e Key Results de uma planilha CSV, enviada como corpo da
+// requisição ou no campo "file" de um formulário multipart
+// (?dry_run=true&generate_key_results=true)
+func (h *OKRHandler) ImportCSV(c *gin.Context) {
+	var opts models.OKRImportOptions
+	for name, target := range map[string]*bool{"dry_run": &opts.DryRun, "generate_key_results": &opts.GenerateKeyResults} {
+		if raw := c.Query(name); raw != "" {
+			value, err := strconv.ParseBool(raw)
+			if err != nil {
+				c.Error(apperrors.InvalidField(name, name+" inválido: "+raw))
+				return
+			}
+			*target = value
+		}
+	}
+
+	var body io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		header, err := c.FormFile("file")
+		if err != nil {
+			c.Error(apperrors.InvalidField("file", "arquivo CSV não enviado no campo file"))
+			return
+		}
+		file, err := header.Open()
+		if err != nil {
+			c.Error(apperrors.Wrap("erro ao ler o arquivo enviado", err))
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	result, err := h.service.ImportOKRsCSV(c.Request.Context(), body, opts)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
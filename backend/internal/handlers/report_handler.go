@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+	"github.com/conquista-ai/conquista-ai/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type ReportHandler struct {
+	riskService *services.RiskService
+}
+
+func NewReportHandler(riskService *services.RiskService) *ReportHandler {
+	return &ReportHandler{riskService: riskService}
+}
+
+// Risk classifica cada OKR, Key Result e trilha como on_track, at_risk ou off_track
+func (h *ReportHandler) Risk(c *gin.Context) {
+	report, err := h.riskService.Report(c.Request.Context())
+	if err != nil {
+		c.Error(apperrors.Wrap("erro ao gerar relatório de risco", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -3,8 +3,10 @@ package handlers
 import (
 	"fmt"
 	"net/http"
-	"strconv"
+	"time"
 
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+	"github.com/conquista-ai/conquista-ai/internal/models"
 	"github.com/conquista-ai/conquista-ai/internal/services"
 	"github.com/gin-gonic/gin"
 )
@@ -18,15 +20,15 @@ func NewRoadmapHandler(service *services.RoadmapService) *RoadmapHandler {
 }
 
 func (h *RoadmapHandler) GenerateRoadmap(c *gin.Context) {
-	keyResultID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	keyResultID, err := parseIDParam(c, "id")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		c.Error(err)
 		return
 	}
 
-	roadmap, err := h.service.GenerateRoadmap(keyResultID)
+	roadmap, err := h.service.GenerateRoadmap(c.Request.Context(), keyResultID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -34,20 +36,20 @@ func (h *RoadmapHandler) GenerateRoadmap(c *gin.Context) {
 }
 
 func (h *RoadmapHandler) GetByKeyResultID(c *gin.Context) {
-	keyResultID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	keyResultID, err := parseIDParam(c, "id")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		c.Error(err)
 		return
 	}
 
-	roadmap, err := h.service.GetRoadmapByKeyResultID(keyResultID)
+	roadmap, err := h.service.GetRoadmapByKeyResultID(c.Request.Context(), keyResultID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "erro ao buscar roadmap"})
+		c.Error(apperrors.Wrap("erro ao buscar roadmap", err))
 		return
 	}
 
 	if roadmap == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "roadmap não encontrado"})
+		c.Error(apperrors.NotFound("roadmap não encontrado"))
 		return
 	}
 
@@ -55,22 +57,22 @@ func (h *RoadmapHandler) GetByKeyResultID(c *gin.Context) {
 }
 
 func (h *RoadmapHandler) UpdateItem(c *gin.Context) {
-	itemID, err := strconv.ParseInt(c.Param("item_id"), 10, 64)
+	itemID, err := parseIDParam(c, "item_id")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		c.Error(err)
 		return
 	}
 
 	var req struct {
 		Completed bool `json:"completed"`
 	}
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "dados inválidos"})
+	if err := bindJSON(c, &req); err != nil {
+		c.Error(err)
 		return
 	}
 
-	if err := h.service.UpdateRoadmapItem(itemID, req.Completed); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "erro ao atualizar item"})
+	if err := h.service.UpdateRoadmapItem(c.Request.Context(), itemID, req.Completed); err != nil {
+		c.Error(apperrors.Wrap("erro ao atualizar item", err))
 		return
 	}
 
@@ -82,14 +84,14 @@ func (h *RoadmapHandler) GenerateEducationalRoadmap(c *gin.Context) {
 		RoadmapItemID int64  `json:"roadmap_item_id" binding:"required"`
 		ItemTitle     string `json:"item_title" binding:"required"`
 	}
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "roadmap_item_id e item_title são obrigatórios"})
+	if err := bindJSON(c, &req); err != nil {
+		c.Error(err)
 		return
 	}
 
-	educationalRoadmap, err := h.service.GenerateEducationalRoadmap(req.RoadmapItemID, req.ItemTitle)
+	educationalRoadmap, err := h.service.GenerateEducationalRoadmap(c.Request.Context(), req.RoadmapItemID, req.ItemTitle)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -97,20 +99,20 @@ func (h *RoadmapHandler) GenerateEducationalRoadmap(c *gin.Context) {
 }
 
 func (h *RoadmapHandler) GetEducationalRoadmapByRoadmapItemID(c *gin.Context) {
-	roadmapItemID, err := strconv.ParseInt(c.Param("roadmap_item_id"), 10, 64)
+	roadmapItemID, err := parseIDParam(c, "roadmap_item_id")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		c.Error(err)
 		return
 	}
 
-	educationalRoadmap, err := h.service.GetEducationalRoadmapByRoadmapItemID(roadmapItemID)
+	educationalRoadmap, err := h.service.GetEducationalRoadmapByRoadmapItemID(c.Request.Context(), roadmapItemID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "erro ao buscar roadmap educacional"})
+		c.Error(apperrors.Wrap("erro ao buscar roadmap educacional", err))
 		return
 	}
 
 	if educationalRoadmap == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "roadmap educacional não encontrado"})
+		c.Error(apperrors.NotFound("roadmap educacional não encontrado"))
 		return
 	}
 
@@ -118,22 +120,22 @@ func (h *RoadmapHandler) GetEducationalRoadmapByRoadmapItemID(c *gin.Context) {
 }
 
 func (h *RoadmapHandler) UpdateEducationalResource(c *gin.Context) {
-	resourceID, err := strconv.ParseInt(c.Param("resource_id"), 10, 64)
+	resourceID, err := parseIDParam(c, "resource_id")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		c.Error(err)
 		return
 	}
 
 	var req struct {
 		Completed bool `json:"completed"`
 	}
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "dados inválidos"})
+	if err := bindJSON(c, &req); err != nil {
+		c.Error(err)
 		return
 	}
 
-	if err := h.service.UpdateEducationalResourceCompleted(resourceID, req.Completed); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "erro ao atualizar recurso"})
+	if err := h.service.UpdateEducationalResourceCompleted(c.Request.Context(), resourceID, req.Completed); err != nil {
+		c.Error(apperrors.Wrap("erro ao atualizar recurso", err))
 		return
 	}
 
@@ -145,14 +147,14 @@ func (h *RoadmapHandler) GenerateEducationalTrail(c *gin.Context) {
 		RoadmapItemID int64  `json:"roadmap_item_id" binding:"required"`
 		ItemTitle     string `json:"item_title" binding:"required"`
 	}
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "roadmap_item_id e item_title são obrigatórios"})
+	if err := bindJSON(c, &req); err != nil {
+		c.Error(err)
 		return
 	}
 
-	trail, err := h.service.GenerateEducationalTrail(req.RoadmapItemID, req.ItemTitle)
+	trail, err := h.service.GenerateEducationalTrail(c.Request.Context(), req.RoadmapItemID, req.ItemTitle)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -160,20 +162,54 @@ func (h *RoadmapHandler) GenerateEducationalTrail(c *gin.Context) {
 }
 
 func (h *RoadmapHandler) GetEducationalTrailByRoadmapItemID(c *gin.Context) {
-	roadmapItemID, err := strconv.ParseInt(c.Param("roadmap_item_id"), 10, 64)
+	roadmapItemID, err := parseIDParam(c, "roadmap_item_id")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		c.Error(err)
 		return
 	}
 
-	trail, err := h.service.GetEducationalTrailByRoadmapItemID(roadmapItemID)
+	trail, err := h.service.GetEducationalTrailByRoadmapItemID(c.Request.Context(), roadmapItemID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "erro ao buscar trilha educacional"})
+		c.Error(apperrors.Wrap("erro ao buscar trilha educacional", err))
 		return
 	}
 
 	if trail == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "trilha educacional não encontrada"})
+		c.Error(apperrors.NotFound("trilha educacional não encontrada"))
+		return
+	}
+
+	c.JSON(http.StatusOK, trail)
+}
+
+// ReplanEducationalTrail reagenda as etapas pendentes da trilha a partir de start_date
+// (padrão: hoje), usado quando o usuário fica para trás no cronograma
+func (h *RoadmapHandler) ReplanEducationalTrail(c *gin.Context) {
+	roadmapItemID, err := parseIDParam(c, "roadmap_item_id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req models.ReplanTrailRequest
+	if err := bindOptionalJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	var startDate *time.Time
+	if req.StartDate != nil && *req.StartDate != "" {
+		parsed, err := parseDateField("start_date", *req.StartDate)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		startDate = &parsed
+	}
+
+	trail, err := h.service.ReplanEducationalTrail(c.Request.Context(), roadmapItemID, startDate)
+	if err != nil {
+		c.Error(apperrors.Wrap("erro ao replanejar trilha educacional", err))
 		return
 	}
 
@@ -181,22 +217,22 @@ func (h *RoadmapHandler) GetEducationalTrailByRoadmapItemID(c *gin.Context) {
 }
 
 func (h *RoadmapHandler) UpdateTrailActivity(c *gin.Context) {
-	activityID, err := strconv.ParseInt(c.Param("activity_id"), 10, 64)
+	activityID, err := parseIDParam(c, "activity_id")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		c.Error(err)
 		return
 	}
 
 	var req struct {
 		Completed bool `json:"completed"`
 	}
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "dados inválidos"})
+	if err := bindJSON(c, &req); err != nil {
+		c.Error(err)
 		return
 	}
 
-	if err := h.service.UpdateTrailActivityCompleted(activityID, req.Completed); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "erro ao atualizar atividade"})
+	if err := h.service.UpdateTrailActivityCompleted(c.Request.Context(), activityID, req.Completed); err != nil {
+		c.Error(apperrors.Wrap("erro ao atualizar atividade", err))
 		return
 	}
 
@@ -204,18 +240,14 @@ func (h *RoadmapHandler) UpdateTrailActivity(c *gin.Context) {
 }
 
 func (h *RoadmapHandler) DeleteEducationalTrail(c *gin.Context) {
-	roadmapItemID, err := strconv.ParseInt(c.Param("roadmap_item_id"), 10, 64)
+	roadmapItemID, err := parseIDParam(c, "roadmap_item_id")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		c.Error(err)
 		return
 	}
 
-	if err := h.service.DeleteEducationalTrail(roadmapItemID); err != nil {
-		if err.Error() == fmt.Sprintf("trilha educacional não encontrada para roadmap_item_id %d", roadmapItemID) {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "erro ao deletar trilha educacional"})
+	if err := h.service.DeleteEducationalTrail(c.Request.Context(), roadmapItemID); err != nil {
+		c.Error(apperrors.Wrap("erro ao deletar trilha educacional", err))
 		return
 	}
 
@@ -223,20 +255,51 @@ func (h *RoadmapHandler) DeleteEducationalTrail(c *gin.Context) {
 }
 
 func (h *RoadmapHandler) DeleteRoadmap(c *gin.Context) {
-	keyResultID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	keyResultID, err := parseIDParam(c, "id")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		c.Error(err)
 		return
 	}
 
-	if err := h.service.DeleteRoadmap(keyResultID); err != nil {
-		if err.Error() == fmt.Sprintf("roadmap não encontrado para key_result_id %d", keyResultID) {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "erro ao deletar roadmap"})
+	if err := h.service.DeleteRoadmap(c.Request.Context(), keyResultID); err != nil {
+		c.Error(apperrors.Wrap("erro ao deletar roadmap", err))
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "roadmap deletado com sucesso"})
 }
+
+// ExportRoadmap baixa o roadmap do Key Result para impressão (?format=md|pdf, padrão md)
+func (h *RoadmapHandler) ExportRoadmap(c *gin.Context) {
+	keyResultID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	doc, err := h.service.RoadmapDocument(c.Request.Context(), keyResultID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	writeDocument(c, doc, fmt.Sprintf("roadmap-%d", keyResultID))
+}
+
+// ExportEducationalTrail baixa a trilha do item do roadmap para impressão
+// (?format=md|pdf, padrão md)
+func (h *RoadmapHandler) ExportEducationalTrail(c *gin.Context) {
+	roadmapItemID, err := parseIDParam(c, "roadmap_item_id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	doc, err := h.service.EducationalTrailDocument(c.Request.Context(), roadmapItemID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	writeDocument(c, doc, fmt.Sprintf("trilha-%d", roadmapItemID))
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/conquista-ai/conquista-ai/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct {
+	repo *repositories.SearchRepository
+}
+
+func NewSearchHandler(repo *repositories.SearchRepository) *SearchHandler {
+	return &SearchHandler{repo: repo}
+}
+
+// Search busca em OKRs, Key Results, itens de roadmap e conteúdo das trilhas.
+// Parâmetros: q (obrigatório), types (lista separada por vírgula) e limit
+func (h *SearchHandler) Search(c *gin.Context) {
+	term := strings.TrimSpace(c.Query("q"))
+	if term == "" {
+		c.Error(apperrors.InvalidField("q", "parâmetro q é obrigatório"))
+		return
+	}
+
+	var types []string
+	if raw := c.Query("types"); raw != "" {
+		for _, t := range strings.Split(raw, ",") {
+			t = strings.TrimSpace(t)
+			if !slices.Contains(repositories.SearchTypes, t) {
+				c.Error(apperrors.InvalidField("types", "tipo de busca inválido: "+t))
+				return
+			}
+			types = append(types, t)
+		}
+	}
+
+	limit := models.DefaultPageLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			c.Error(apperrors.InvalidField("limit", "limit inválido"))
+			return
+		}
+		limit = parsed
+	}
+
+	results, err := h.repo.Search(c.Request.Context(), term, types, limit)
+	if err != nil {
+		c.Error(apperrors.Internal("erro ao realizar busca", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SearchResponse{Query: term, Results: results})
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/conquista-ai/conquista-ai/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+const sharePath = "/public/share/"
+
+type ShareHandler struct {
+	service *services.ShareService
+}
+
+func NewShareHandler(service *services.ShareService) *ShareHandler {
+	return &ShareHandler{service: service}
+}
+
+// Create gera um link público de leitura para o roadmap do Key Result. O token e a URL
+// só são exibidos nesta resposta
+func (h *ShareHandler) Create(c *gin.Context) {
+	keyResultID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req models.CreateShareLinkRequest
+	if err := bindOptionalJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	link, err := h.service.Create(c.Request.Context(), keyResultID, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	link.URL = absoluteURL(c, sharePath+link.Token, "")
+	c.JSON(http.StatusCreated, link)
+}
+
+// List retorna os links do roadmap do Key Result com a contagem de visualizações
+func (h *ShareHandler) List(c *gin.Context) {
+	keyResultID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	links, err := h.service.List(c.Request.Context(), keyResultID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, links)
+}
+
+// Revoke desativa o link
+func (h *ShareHandler) Revoke(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	link, err := h.service.Revoke(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, link)
+}
+
+// View retorna a visão pública do roadmap compartilhado. Não exige autenticação: o
+// token do link é a credencial
+func (h *ShareHandler) View(c *gin.Context) {
+	shared, err := h.service.View(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Cada acesso deve chegar ao servidor para contar a visualização e respeitar a revogação
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Robots-Tag", "noindex, nofollow")
+	c.JSON(http.StatusOK, shared)
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/conquista-ai/conquista-ai/internal/repositories"
+	"github.com/conquista-ai/conquista-ai/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type StudyHandler struct {
+	repo    *repositories.StudyRepository
+	service *services.StudyService
+}
+
+func NewStudyHandler(repo *repositories.StudyRepository, service *services.StudyService) *StudyHandler {
+	return &StudyHandler{repo: repo, service: service}
+}
+
+// StartTimer inicia o cronômetro de estudo da atividade
+func (h *StudyHandler) StartTimer(c *gin.Context) {
+	activityID, err := parseIDParam(c, "activity_id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	session, err := h.service.StartTimer(c.Request.Context(), activityID)
+	if err != nil {
+		c.Error(apperrors.Wrap("erro ao iniciar cronômetro", err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, session)
+}
+
+// StopTimer encerra o cronômetro da atividade e registra o tempo decorrido
+func (h *StudyHandler) StopTimer(c *gin.Context) {
+	activityID, err := parseIDParam(c, "activity_id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	session, err := h.service.StopTimer(c.Request.Context(), activityID)
+	if err != nil {
+		c.Error(apperrors.Wrap("erro ao parar cronômetro", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+func (h *StudyHandler) ListSessions(c *gin.Context) {
+	activityID, err := parseIDParam(c, "activity_id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	sessions, err := h.repo.ListSessions(c.Request.Context(), activityID)
+	if err != nil {
+		c.Error(apperrors.Internal("erro ao listar sessões de estudo", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+// CreateSession lança manualmente um tempo de estudo na atividade
+func (h *StudyHandler) CreateSession(c *gin.Context) {
+	activityID, err := parseIDParam(c, "activity_id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req models.CreateStudySessionRequest
+	if err := bindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	var date *time.Time
+	if req.Date != nil && *req.Date != "" {
+		parsed, err := parseDateField("date", *req.Date)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		date = &parsed
+	}
+
+	session, err := h.service.CreateSession(c.Request.Context(), activityID, req.Minutes, date, req.Note)
+	if err != nil {
+		c.Error(apperrors.Wrap("erro ao registrar sessão de estudo", err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, session)
+}
+
+func (h *StudyHandler) DeleteSession(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.repo.DeleteSession(c.Request.Context(), id); err != nil {
+		c.Error(apperrors.Wrap("erro ao remover sessão de estudo", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "sessão de estudo removida com sucesso"})
+}
+
+// Stats retorna a sequência de dias de estudo, os minutos por semana e categoria das
+// últimas semanas (?weeks=, padrão 8) e a comparação entre tempo estimado e real
+func (h *StudyHandler) Stats(c *gin.Context) {
+	weeks := services.DefaultStudyStatsWeeks
+	if raw := c.Query("weeks"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > services.MaxStudyStatsWeeks {
+			c.Error(apperrors.InvalidField("weeks", "weeks deve ser um número entre 1 e 52"))
+			return
+		}
+		weeks = n
+	}
+
+	stats, err := h.service.Stats(c.Request.Context(), weeks)
+	if err != nil {
+		c.Error(apperrors.Internal("erro ao calcular estatísticas de estudo", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/conquista-ai/conquista-ai/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+type StudySettingsHandler struct {
+	repo *repositories.StudySettingsRepository
+}
+
+func NewStudySettingsHandler(repo *repositories.StudySettingsRepository) *StudySettingsHandler {
+	return &StudySettingsHandler{repo: repo}
+}
+
+func (h *StudySettingsHandler) Get(c *gin.Context) {
+	settings, err := h.repo.Get(c.Request.Context())
+	if err != nil {
+		c.Error(apperrors.Internal("erro ao buscar configurações de estudo", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// Update altera os dias da semana e as horas de estudo. As trilhas já agendadas não
+// mudam; use o replanejamento da trilha para aplicar a nova disponibilidade
+func (h *StudySettingsHandler) Update(c *gin.Context) {
+	var req models.UpdateStudySettingsRequest
+	if err := bindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	weekdays := slices.Clone(req.StudyWeekdays)
+	slices.Sort(weekdays)
+	weekdays = slices.Compact(weekdays)
+
+	if err := h.repo.Update(c.Request.Context(), weekdays, req.HoursPerDay); err != nil {
+		c.Error(apperrors.Internal("erro ao atualizar configurações de estudo", err))
+		return
+	}
+
+	h.Get(c)
+}
+
+// CreateDayOff cadastra um feriado ou um bloqueio de datas sem estudo
+func (h *StudySettingsHandler) CreateDayOff(c *gin.Context) {
+	var req models.CreateStudyDayOffRequest
+	if err := bindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	startDate, err := parseDateField("start_date", req.StartDate)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	endDate := startDate
+	if req.EndDate != nil && *req.EndDate != "" {
+		if req.Kind == models.DayOffHoliday {
+			c.Error(apperrors.InvalidField("end_date", "feriados têm um único dia; use kind blackout para intervalos"))
+			return
+		}
+		endDate, err = parseDateField("end_date", *req.EndDate)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		if endDate.Before(startDate) {
+			c.Error(apperrors.InvalidField("end_date", "deve ser igual ou posterior a start_date"))
+			return
+		}
+	}
+
+	dayOff := &models.StudyDayOff{
+		Kind:        req.Kind,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Description: req.Description,
+	}
+	if err := h.repo.CreateDayOff(c.Request.Context(), dayOff); err != nil {
+		c.Error(apperrors.Internal("erro ao cadastrar dia sem estudo", err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, dayOff)
+}
+
+func (h *StudySettingsHandler) DeleteDayOff(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.repo.DeleteDayOff(c.Request.Context(), id); err != nil {
+		c.Error(apperrors.Wrap("erro ao remover dia sem estudo", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "dia sem estudo removido com sucesso"})
+}
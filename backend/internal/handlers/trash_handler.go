@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+	"github.com/conquista-ai/conquista-ai/internal/repositories"
+	"github.com/conquista-ai/conquista-ai/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type TrashHandler struct {
+	trashService  *services.TrashService
+	okrRepo       *repositories.OKRRepository
+	keyResultRepo *repositories.KeyResultRepository
+	roadmapRepo   *repositories.RoadmapRepository
+}
+
+func NewTrashHandler(trashService *services.TrashService, okrRepo *repositories.OKRRepository, keyResultRepo *repositories.KeyResultRepository, roadmapRepo *repositories.RoadmapRepository) *TrashHandler {
+	return &TrashHandler{
+		trashService:  trashService,
+		okrRepo:       okrRepo,
+		keyResultRepo: keyResultRepo,
+		roadmapRepo:   roadmapRepo,
+	}
+}
+
+// List retorna os itens da lixeira, dos excluídos mais recentemente para os mais antigos
+func (h *TrashHandler) List(c *gin.Context) {
+	items, err := h.trashService.List(c.Request.Context())
+	if err != nil {
+		c.Error(apperrors.Wrap("erro ao listar a lixeira", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// RestoreOKR restaura o OKR com os Key Results e roadmaps excluídos junto com ele
+func (h *TrashHandler) RestoreOKR(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.okrRepo.Restore(ctx, id); err != nil {
+		c.Error(apperrors.Wrap("erro ao restaurar OKR", err))
+		return
+	}
+
+	okr, err := h.okrRepo.GetByID(ctx, id)
+	if err != nil {
+		c.Error(apperrors.Internal("erro ao buscar OKR", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, okr)
+}
+
+// RestoreKeyResult restaura o Key Result com o roadmap excluído junto com ele
+func (h *TrashHandler) RestoreKeyResult(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.keyResultRepo.Restore(ctx, id); err != nil {
+		c.Error(apperrors.Wrap("erro ao restaurar Key Result", err))
+		return
+	}
+
+	keyResult, err := h.keyResultRepo.GetByID(ctx, id)
+	if err != nil {
+		c.Error(apperrors.Internal("erro ao buscar Key Result", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, keyResult)
+}
+
+// RestoreRoadmap restaura um roadmap pelo seu ID
+func (h *TrashHandler) RestoreRoadmap(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	keyResultID, err := h.roadmapRepo.Restore(ctx, id)
+	if err != nil {
+		c.Error(apperrors.Wrap("erro ao restaurar roadmap", err))
+		return
+	}
+
+	roadmap, err := h.roadmapRepo.GetByKeyResultID(ctx, keyResultID)
+	if err != nil {
+		c.Error(apperrors.Internal("erro ao buscar roadmap", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, roadmap)
+}
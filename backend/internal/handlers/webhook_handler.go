@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/conquista-ai/conquista-ai/internal/planning"
+	"github.com/conquista-ai/conquista-ai/internal/repositories"
+	"github.com/conquista-ai/conquista-ai/internal/webhooks"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	repo  *repositories.WebhookRepository
+	clock planning.Clock
+}
+
+func NewWebhookHandler(repo *repositories.WebhookRepository, clock planning.Clock) *WebhookHandler {
+	return &WebhookHandler{repo: repo, clock: clock}
+}
+
+func (h *WebhookHandler) List(c *gin.Context) {
+	subs, err := h.repo.ListSubscriptions(c.Request.Context())
+	if err != nil {
+		c.Error(apperrors.Internal("erro ao listar webhooks", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, subs)
+}
+
+// Create cadastra a assinatura. O segredo (informado ou gerado) só é exibido nesta resposta
+func (h *WebhookHandler) Create(c *gin.Context) {
+	var req models.CreateWebhookSubscriptionRequest
+	if err := bindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		var err error
+		if secret, err = webhooks.NewSecret(); err != nil {
+			c.Error(apperrors.Internal("erro ao gerar segredo do webhook", err))
+			return
+		}
+	}
+
+	sub := &models.WebhookSubscription{
+		URL:    req.URL,
+		Events: normalizeEvents(req.Events),
+		Secret: secret,
+		Active: true,
+	}
+	if err := h.repo.CreateSubscription(c.Request.Context(), sub); err != nil {
+		c.Error(apperrors.Internal("erro ao criar webhook", err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, sub)
+}
+
+func (h *WebhookHandler) Get(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	sub, err := h.repo.GetSubscription(c.Request.Context(), id)
+	if err != nil {
+		c.Error(apperrors.Internal("erro ao buscar webhook", err))
+		return
+	}
+	if sub == nil {
+		c.Error(apperrors.NotFound("assinatura de webhook não encontrada"))
+		return
+	}
+
+	c.JSON(http.StatusOK, sub)
+}
+
+func (h *WebhookHandler) Update(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req models.UpdateWebhookSubscriptionRequest
+	if err := bindJSON(c, &req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	sub := &models.WebhookSubscription{
+		ID:     id,
+		URL:    req.URL,
+		Events: normalizeEvents(req.Events),
+		Active: req.Active,
+	}
+	if err := h.repo.UpdateSubscription(c.Request.Context(), sub); err != nil {
+		c.Error(apperrors.Wrap("erro ao atualizar webhook", err))
+		return
+	}
+
+	h.Get(c)
+}
+
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.repo.DeleteSubscription(c.Request.Context(), id); err != nil {
+		c.Error(apperrors.Wrap("erro ao remover webhook", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "webhook removido com sucesso"})
+}
+
+// RotateSecret gera um novo segredo. Entregas pendentes passam a ser assinadas com ele
+func (h *WebhookHandler) RotateSecret(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	secret, err := webhooks.NewSecret()
+	if err != nil {
+		c.Error(apperrors.Internal("erro ao gerar segredo do webhook", err))
+		return
+	}
+	if err := h.repo.UpdateSecret(c.Request.Context(), id, secret); err != nil {
+		c.Error(apperrors.Wrap("erro ao atualizar segredo do webhook", err))
+		return
+	}
+
+	sub, err := h.repo.GetSubscription(c.Request.Context(), id)
+	if err != nil || sub == nil {
+		c.Error(apperrors.Internal("erro ao buscar webhook", err))
+		return
+	}
+	sub.Secret = secret
+
+	c.JSON(http.StatusOK, sub)
+}
+
+// Deliveries retorna o log de entregas da assinatura (?status=pending|succeeded|failed&limit=)
+func (h *WebhookHandler) Deliveries(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	filter := models.WebhookDeliveryFilter{
+		SubscriptionID: id,
+		Status:         c.Query("status"),
+		Limit:          models.DefaultPageLimit,
+	}
+	if filter.Status != "" && !slices.Contains([]string{models.WebhookDeliveryPending, models.WebhookDeliverySucceeded, models.WebhookDeliveryFailed}, filter.Status) {
+		c.Error(apperrors.InvalidField("status", "status inválido: "+filter.Status))
+		return
+	}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			c.Error(apperrors.InvalidField("limit", "limit inválido: "+raw))
+			return
+		}
+		filter.Limit = min(limit, models.MaxPageLimit)
+	}
+
+	sub, err := h.repo.GetSubscription(c.Request.Context(), id)
+	if err != nil {
+		c.Error(apperrors.Internal("erro ao buscar webhook", err))
+		return
+	}
+	if sub == nil {
+		c.Error(apperrors.NotFound("assinatura de webhook não encontrada"))
+		return
+	}
+
+	deliveries, err := h.repo.ListDeliveries(c.Request.Context(), filter)
+	if err != nil {
+		c.Error(apperrors.Internal("erro ao listar entregas do webhook", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// Redeliver agenda o reenvio imediato de uma entrega, com as tentativas zeradas
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	deliveryID, err := parseIDParam(c, "delivery_id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	delivery, err := h.repo.Redeliver(c.Request.Context(), id, deliveryID, h.clock.Now())
+	if err != nil {
+		c.Error(apperrors.Wrap("erro ao reagendar entrega do webhook", err))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+func normalizeEvents(events []string) []string {
+	events = slices.Clone(events)
+	slices.Sort(events)
+	return slices.Compact(events)
+}
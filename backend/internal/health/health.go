@@ -0,0 +1,120 @@
+// Package health verifica as dependências da aplicação para os probes de readiness.
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// CheckTimeout limita o tempo de cada verificação de dependência
+const CheckTimeout = 3 * time.Second
+
+// Check verifica uma dependência; retorna erro quando ela está indisponível
+type Check struct {
+	Name string
+	Fn   func(ctx context.Context) error
+}
+
+// CheckResult é o resultado de uma verificação
+type CheckResult struct {
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// Report consolida as verificações de todas as dependências
+type Report struct {
+	Status    string                 `json:"status"`
+	Checks    map[string]CheckResult `json:"checks"`
+	CheckedAt time.Time              `json:"checked_at"`
+}
+
+// Checker executa as verificações e guarda o resultado por ttl, evitando que probes
+// frequentes sobrecarreguem o banco e o Spellbook
+type Checker struct {
+	checks   []Check
+	ttl      time.Duration
+	draining atomic.Bool
+
+	mu     sync.Mutex
+	cached *Report
+}
+
+func NewChecker(ttl time.Duration, checks ...Check) *Checker {
+	return &Checker{checks: checks, ttl: ttl}
+}
+
+// SetDraining marca a aplicação como em desligamento; a partir daí Ready retorna falha
+// para que o balanceador pare de enviar novas requisições
+func (c *Checker) SetDraining() {
+	c.draining.Store(true)
+}
+
+// Draining informa se o desligamento já foi iniciado
+func (c *Checker) Draining() bool {
+	return c.draining.Load()
+}
+
+// Ready retorna o relatório das dependências, reaproveitando o último resultado
+// enquanto ele estiver dentro do ttl
+func (c *Checker) Ready(ctx context.Context) Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cached == nil || time.Since(c.cached.CheckedAt) >= c.ttl {
+		report := c.run(ctx)
+		c.cached = &report
+	}
+
+	return *c.cached
+}
+
+func (c *Checker) run(ctx context.Context) Report {
+	report := Report{
+		Status:    StatusOK,
+		Checks:    make(map[string]CheckResult, len(c.checks)),
+		CheckedAt: time.Now(),
+	}
+
+	results := make([]CheckResult, len(c.checks))
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = runCheck(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	for i, check := range c.checks {
+		report.Checks[check.Name] = results[i]
+		if results[i].Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+
+	return report
+}
+
+func runCheck(ctx context.Context, check Check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, CheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Fn(ctx)
+	result := CheckResult{Status: StatusOK, DurationMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+
+	return result
+}
@@ -0,0 +1,160 @@
+// Package ical gera calendários no formato iCalendar (RFC 5545) com eventos e
+// tarefas de dia inteiro, suficientes para assinatura em Google Calendar e Outlook.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Tipos de componente suportados
+const (
+	KindEvent = "VEVENT"
+	KindTodo  = "VTODO"
+)
+
+const (
+	dateFormat     = "20060102"
+	dateTimeFormat = "20060102T150405Z"
+	// Tamanho máximo de uma linha em octetos, sem contar o CRLF (RFC 5545, seção 3.1)
+	maxLineOctets = 75
+)
+
+// Component é um evento (VEVENT) ou tarefa (VTODO) de dia inteiro. O UID deve ser
+// estável para que os aplicativos de calendário atualizem o item em vez de duplicá-lo
+type Component struct {
+	Kind        string
+	UID         string
+	Summary     string
+	Description string
+	Categories  []string
+	// Date é o dia do evento (DTSTART) ou o prazo da tarefa (DUE)
+	Date time.Time
+	// Completed marca a tarefa como concluída (ignorado em eventos)
+	Completed bool
+	// Modified é usado em DTSTAMP e LAST-MODIFIED
+	Modified time.Time
+}
+
+// Calendar é um VCALENDAR publicado para assinatura
+type Calendar struct {
+	ProdID string
+	Name   string
+	// RefreshInterval sugere aos clientes o intervalo de atualização do feed
+	RefreshInterval time.Duration
+	Components      []Component
+}
+
+// Write escreve o calendário em w, com linhas terminadas em CRLF e dobradas em 75 octetos
+func (c *Calendar) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	line := func(name, value string) {
+		writeFolded(bw, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", c.ProdID)
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		line("X-WR-CALNAME", escapeText(c.Name))
+	}
+	if c.RefreshInterval > 0 {
+		interval := formatDuration(c.RefreshInterval)
+		line("REFRESH-INTERVAL;VALUE=DURATION", interval)
+		line("X-PUBLISHED-TTL", interval)
+	}
+
+	for _, comp := range c.Components {
+		line("BEGIN", comp.Kind)
+		line("UID", comp.UID)
+		line("DTSTAMP", comp.Modified.UTC().Format(dateTimeFormat))
+		line("LAST-MODIFIED", comp.Modified.UTC().Format(dateTimeFormat))
+		line("SUMMARY", escapeText(comp.Summary))
+		if comp.Description != "" {
+			line("DESCRIPTION", escapeText(comp.Description))
+		}
+		if len(comp.Categories) > 0 {
+			categories := make([]string, len(comp.Categories))
+			for i, category := range comp.Categories {
+				categories[i] = escapeText(category)
+			}
+			line("CATEGORIES", strings.Join(categories, ","))
+		}
+
+		switch comp.Kind {
+		case KindTodo:
+			line("DUE;VALUE=DATE", comp.Date.Format(dateFormat))
+			if comp.Completed {
+				line("STATUS", "COMPLETED")
+				line("PERCENT-COMPLETE", "100")
+			} else {
+				line("STATUS", "NEEDS-ACTION")
+			}
+		default:
+			// Evento de dia inteiro: DTEND é exclusivo, ou seja, o dia seguinte
+			line("DTSTART;VALUE=DATE", comp.Date.Format(dateFormat))
+			line("DTEND;VALUE=DATE", comp.Date.AddDate(0, 0, 1).Format(dateFormat))
+			line("TRANSP", "TRANSPARENT")
+		}
+		line("END", comp.Kind)
+	}
+
+	line("END", "VCALENDAR")
+	return bw.Flush()
+}
+
+// escapeText escapa um valor do tipo TEXT (RFC 5545, seção 3.3.11)
+func escapeText(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	)
+	return replacer.Replace(value)
+}
+
+// writeFolded escreve a linha dobrando-a a cada 75 octetos sem quebrar caracteres UTF-8;
+// as linhas de continuação começam com um espaço
+func writeFolded(w *bufio.Writer, content string) {
+	limit := maxLineOctets
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		w.WriteString(content[:cut])
+		w.WriteString("\r\n ")
+		content = content[cut:]
+		// O espaço inicial conta no limite da linha de continuação
+		limit = maxLineOctets - 1
+	}
+	w.WriteString(content)
+	w.WriteString("\r\n")
+}
+
+// formatDuration formata a duração no formato DURATION (ex.: PT1H, PT30M)
+func formatDuration(d time.Duration) string {
+	var b strings.Builder
+	b.WriteString("PT")
+	if hours := int(d.Hours()); hours > 0 {
+		b.WriteString(strconv.Itoa(hours) + "H")
+		d -= time.Duration(hours) * time.Hour
+	}
+	if minutes := int(d.Minutes()); minutes > 0 {
+		b.WriteString(strconv.Itoa(minutes) + "M")
+		d -= time.Duration(minutes) * time.Minute
+	}
+	if seconds := int(d.Seconds()); seconds > 0 || b.Len() == 2 {
+		b.WriteString(strconv.Itoa(seconds) + "S")
+	}
+	return b.String()
+}
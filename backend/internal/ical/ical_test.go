@@ -0,0 +1,172 @@
+package ical
+
+import (
+	"bufio"
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func folded(content string) string {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	writeFolded(w, content)
+	w.Flush()
+	return buf.String()
+}
+
+// unfold desfaz a dobra de linhas (RFC 5545, seção 3.1)
+func unfold(s string) string {
+	return strings.ReplaceAll(s, "\r\n ", "")
+}
+
+func TestWriteFolded(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "linha curta",
+			content: "SUMMARY:Estudar Go",
+			want:    []string{"SUMMARY:Estudar Go"},
+		},
+		{
+			name:    "exatamente 75 octetos",
+			content: "SUMMARY:" + strings.Repeat("a", 67),
+			want:    []string{"SUMMARY:" + strings.Repeat("a", 67)},
+		},
+		{
+			name:    "76 octetos",
+			content: "SUMMARY:" + strings.Repeat("a", 68),
+			want:    []string{"SUMMARY:" + strings.Repeat("a", 67), " a"},
+		},
+		{
+			// "é" ocupa os octetos 75 e 76 e não pode ser partido
+			name:    "caractere multibyte na fronteira",
+			content: "SUMMARY:" + strings.Repeat("a", 66) + "é" + "b",
+			want:    []string{"SUMMARY:" + strings.Repeat("a", 66), " éb"},
+		},
+		{
+			// A continuação tem 74 octetos de conteúdo além do espaço inicial
+			name:    "várias continuações",
+			content: "DESCRIPTION:" + strings.Repeat("x", 63+74+10),
+			want:    []string{"DESCRIPTION:" + strings.Repeat("x", 63), " " + strings.Repeat("x", 74), " " + strings.Repeat("x", 10)},
+		},
+		{
+			name:    "texto só com caracteres de 3 octetos",
+			content: "SUMMARY:" + strings.Repeat("€", 30),
+			want:    []string{"SUMMARY:" + strings.Repeat("€", 22), " " + strings.Repeat("€", 8)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := folded(tt.content)
+			want := strings.Join(tt.want, "\r\n") + "\r\n"
+			if got != want {
+				t.Errorf("writeFolded =\n%q\nwant\n%q", got, want)
+			}
+			if unfold(strings.TrimSuffix(got, "\r\n")) != tt.content {
+				t.Errorf("desdobrar não recupera o conteúdo original")
+			}
+		})
+	}
+}
+
+func TestCalendarWrite(t *testing.T) {
+	modified := time.Date(2026, 3, 2, 15, 4, 5, 0, time.UTC)
+	cal := &Calendar{
+		ProdID:          "-//Conquista AI//Planejamento//PT",
+		Name:            "Conquista AI",
+		RefreshInterval: 90 * time.Minute,
+		Components: []Component{
+			{
+				Kind:        KindEvent,
+				UID:         "okr-1@conquista-ai",
+				Summary:     "Prazo: Aprender Go, Rust; e Zig",
+				Description: "Linha 1\nLinha 2 com acentuação e um texto longo o bastante para ser dobrado em mais de uma linha",
+				Categories:  []string{"OKR", "Carreira, tecnologia"},
+				Date:        time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+				Modified:    modified,
+			},
+			{
+				Kind:      KindTodo,
+				UID:       "kr-2@conquista-ai",
+				Summary:   "Concluir curso",
+				Date:      time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC),
+				Completed: true,
+				Modified:  modified,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := cal.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	if !strings.HasSuffix(out, "END:VCALENDAR\r\n") {
+		t.Errorf("o calendário deve terminar com END:VCALENDAR e CRLF")
+	}
+	for i, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if strings.ContainsAny(line, "\r\n") {
+			t.Errorf("linha %d tem quebra de linha sem CRLF: %q", i+1, line)
+		}
+		if len(line) > maxLineOctets {
+			t.Errorf("linha %d tem %d octetos: %q", i+1, len(line), line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("linha %d parte um caractere UTF-8: %q", i+1, line)
+		}
+	}
+
+	lines := strings.Split(unfold(out), "\r\n")
+	for _, want := range []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"X-WR-CALNAME:Conquista AI",
+		"REFRESH-INTERVAL;VALUE=DURATION:PT1H30M",
+		"X-PUBLISHED-TTL:PT1H30M",
+		"BEGIN:VEVENT",
+		"UID:okr-1@conquista-ai",
+		"DTSTAMP:20260302T150405Z",
+		`SUMMARY:Prazo: Aprender Go\, Rust\; e Zig`,
+		`DESCRIPTION:Linha 1\nLinha 2 com acentuação e um texto longo o bastante para ser dobrado em mais de uma linha`,
+		`CATEGORIES:OKR,Carreira\, tecnologia`,
+		"DTSTART;VALUE=DATE:20260331",
+		"DTEND;VALUE=DATE:20260401",
+		"END:VEVENT",
+		"BEGIN:VTODO",
+		"DUE;VALUE=DATE:20260315",
+		"STATUS:COMPLETED",
+		"PERCENT-COMPLETE:100",
+		"END:VTODO",
+	} {
+		if !slices.Contains(lines, want) {
+			t.Errorf("linha ausente: %q", want)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{time.Hour, "PT1H"},
+		{30 * time.Minute, "PT30M"},
+		{90 * time.Minute, "PT1H30M"},
+		{45 * time.Second, "PT45S"},
+		{0, "PT0S"},
+	}
+	for _, tt := range tests {
+		if got := formatDuration(tt.d); got != tt.want {
+			t.Errorf("formatDuration(%s) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
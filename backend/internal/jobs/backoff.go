@@ -0,0 +1,16 @@
+package jobs
+
+import "time"
+
+// Backoff retorna a espera antes da próxima tentativa após attempts falhas: base
+// dobrando a cada falha, limitada a max (inclusive quando o deslocamento estoura)
+func Backoff(base, max time.Duration, attempts int) time.Duration {
+	if attempts < 1 {
+		return base
+	}
+	delay := base << (attempts - 1)
+	if delay <= 0 || delay > max {
+		return max
+	}
+	return delay
+}
@@ -0,0 +1,32 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	base, max := 30*time.Second, 6*time.Hour
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{10, 256 * time.Minute},
+		// A partir daqui a espera passaria do limite de 6 horas
+		{11, max},
+		{40, max},
+		// Deslocamentos que estouram o int64 também ficam no limite
+		{63, max},
+		{64, max},
+		{1000, max},
+	}
+	for _, tt := range tests {
+		if got := Backoff(base, max, tt.attempts); got != tt.want {
+			t.Errorf("Backoff(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
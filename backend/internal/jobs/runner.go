@@ -0,0 +1,74 @@
+// Package jobs executa tarefas em segundo plano ligadas ao ciclo de vida da aplicação.
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Runner acompanha as goroutines de tarefas em segundo plano para que o desligamento
+// gracioso possa cancelá-las e aguardar que terminem
+type Runner struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewRunner() *Runner {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Runner{ctx: ctx, cancel: cancel}
+}
+
+// Go executa fn em uma goroutine. O contexto recebido é cancelado no Shutdown
+func (r *Runner) Go(name string, fn func(ctx context.Context)) {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer func() {
+			if rec := recover(); rec != nil {
+				slog.Error("job interrompido por panic", "job", name, "panic", rec)
+			}
+		}()
+
+		fn(r.ctx)
+	}()
+}
+
+// Every executa fn a cada intervalo até o Shutdown. Erros são apenas registrados
+func (r *Runner) Every(name string, interval time.Duration, fn func(ctx context.Context) error) {
+	r.Go(name, func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := fn(ctx); err != nil {
+					slog.Error("erro ao executar job", "job", name, "error", err)
+				}
+			}
+		}
+	})
+}
+
+// Shutdown cancela o contexto dos jobs e aguarda que terminem ou que ctx expire
+func (r *Runner) Shutdown(ctx context.Context) error {
+	r.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,53 @@
+// Package logging configura o logger estruturado (log/slog) da aplicação.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/conquista-ai/conquista-ai/internal/requestid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Setup cria o logger com o nível ("debug", "info", "warn", "error") e o formato
+// ("json" ou "text") informados e o define como logger padrão do processo
+func Setup(level, format string) (*slog.Logger, error) {
+	var slogLevel slog.Level
+	if err := slogLevel.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("nível de log inválido: %s", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: slogLevel}
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case "", "json":
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	case "text":
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	default:
+		return nil, fmt.Errorf("formato de log inválido: %s", format)
+	}
+
+	logger := slog.New(handler)
+	slog.SetDefault(logger)
+	return logger, nil
+}
+
+// FromContext retorna o logger padrão enriquecido com o ID da requisição
+// e os identificadores de trace/span presentes no contexto
+func FromContext(ctx context.Context) *slog.Logger {
+	logger := slog.Default()
+
+	if id := requestid.FromContext(ctx); id != "" {
+		logger = logger.With("request_id", id)
+	}
+
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.IsValid() {
+		logger = logger.With("trace_id", spanCtx.TraceID().String(), "span_id", spanCtx.SpanID().String())
+	}
+
+	return logger
+}
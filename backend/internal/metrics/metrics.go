@@ -0,0 +1,163 @@
+// Package metrics define as métricas Prometheus expostas em /metrics.
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "conquista"
+
+var (
+	// HTTPRequestDuration mede a latência das requisições HTTP por rota
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latência das requisições HTTP por método, rota e status.",
+		Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 180},
+	}, []string{"method", "route", "status"})
+
+	// SpellbookRequests conta as chamadas ao Spellbook por endpoint e resultado
+	SpellbookRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "spellbook",
+		Name:      "requests_total",
+		Help:      "Chamadas à API Spellbook por endpoint e resultado (success ou error).",
+	}, []string{"endpoint", "outcome"})
+
+	// SpellbookErrors conta as falhas nas chamadas ao Spellbook por endpoint e motivo
+	SpellbookErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "spellbook",
+		Name:      "errors_total",
+		Help:      "Falhas nas chamadas à API Spellbook por endpoint e motivo.",
+	}, []string{"endpoint", "reason"})
+
+	// SpellbookRequestDuration mede a latência das chamadas ao Spellbook por endpoint
+	SpellbookRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "spellbook",
+		Name:      "request_duration_seconds",
+		Help:      "Latência das chamadas à API Spellbook por endpoint.",
+		Buckets:   []float64{0.5, 1, 2.5, 5, 10, 20, 30, 60, 90, 120, 180},
+	}, []string{"endpoint"})
+
+	// URLValidations conta os resultados da validação de URLs retornadas pelo Spellbook
+	URLValidations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "url_validations_total",
+		Help:      "Validações de URLs de recursos e atividades por origem e resultado (valid ou invalid).",
+	}, []string{"source", "outcome"})
+
+	// NotificationDeliveries conta as tentativas de entrega de lembretes
+	NotificationDeliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notification_deliveries_total",
+		Help:      "Tentativas de entrega de lembretes por canal e resultado (sent, retry ou failed).",
+	}, []string{"channel", "outcome"})
+
+	// WebhookDeliveries conta as tentativas de entrega de eventos aos webhooks de integração
+	WebhookDeliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_deliveries_total",
+		Help:      "Tentativas de entrega de eventos por tipo e resultado (succeeded, retry ou failed).",
+	}, []string{"event", "outcome"})
+)
+
+// Motivos de falha registrados em SpellbookErrors
+const (
+	SpellbookErrorTransport = "transport"
+	SpellbookErrorStatus    = "status"
+	SpellbookErrorDecode    = "decode"
+)
+
+// ObserveSpellbookCall registra uma chamada ao Spellbook. reason vazio indica sucesso
+func ObserveSpellbookCall(endpoint string, duration time.Duration, reason string) {
+	SpellbookRequestDuration.WithLabelValues(endpoint).Observe(duration.Seconds())
+	if reason == "" {
+		SpellbookRequests.WithLabelValues(endpoint, "success").Inc()
+		return
+	}
+	SpellbookRequests.WithLabelValues(endpoint, "error").Inc()
+	SpellbookErrors.WithLabelValues(endpoint, reason).Inc()
+}
+
+// ObserveURLValidation registra o resultado da validação de uma URL
+func ObserveURLValidation(source string, valid bool) {
+	outcome := "valid"
+	if !valid {
+		outcome = "invalid"
+	}
+	URLValidations.WithLabelValues(source, outcome).Inc()
+}
+
+// StatsSource fornece os números de negócio expostos como gauges
+type StatsSource interface {
+	Stats(ctx context.Context) (*models.OKRStats, error)
+}
+
+// Register registra as métricas que dependem do banco: estatísticas do pool de
+// conexões (sql.DB.Stats) e os gauges de negócio calculados a cada coleta
+func Register(db *sql.DB, stats StatsSource) error {
+	if err := prometheus.Register(collectors.NewDBStatsCollector(db, "conquista")); err != nil {
+		return err
+	}
+	return prometheus.Register(newBusinessCollector(stats))
+}
+
+// businessCollector consulta o banco a cada scrape para expor os gauges de negócio
+type businessCollector struct {
+	stats          StatsSource
+	activeOKRs     *prometheus.Desc
+	totalOKRs      *prometheus.Desc
+	keyResults     *prometheus.Desc
+	completionRate *prometheus.Desc
+}
+
+func newBusinessCollector(stats StatsSource) *businessCollector {
+	return &businessCollector{
+		stats: stats,
+		activeOKRs: prometheus.NewDesc(namespace+"_okrs_active",
+			"OKRs com status active e não arquivados.", nil, nil),
+		totalOKRs: prometheus.NewDesc(namespace+"_okrs_total",
+			"Total de OKRs cadastrados.", nil, nil),
+		keyResults: prometheus.NewDesc(namespace+"_key_results",
+			"Key Results por situação (completed ou pending).", []string{"status"}, nil),
+		completionRate: prometheus.NewDesc(namespace+"_key_results_completion_ratio",
+			"Fração de Key Results concluídos (0 a 1).", nil, nil),
+	}
+}
+
+func (c *businessCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.activeOKRs
+	ch <- c.totalOKRs
+	ch <- c.keyResults
+	ch <- c.completionRate
+}
+
+func (c *businessCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stats, err := c.stats.Stats(ctx)
+	if err != nil {
+		slog.Error("erro ao coletar métricas de negócio", "error", err)
+		ch <- prometheus.NewInvalidMetric(c.activeOKRs, err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.activeOKRs, prometheus.GaugeValue, float64(stats.ActiveOKRs))
+	ch <- prometheus.MustNewConstMetric(c.totalOKRs, prometheus.GaugeValue, float64(stats.TotalOKRs))
+	ch <- prometheus.MustNewConstMetric(c.keyResults, prometheus.GaugeValue, float64(stats.CompletedKeyResults), "completed")
+	ch <- prometheus.MustNewConstMetric(c.keyResults, prometheus.GaugeValue,
+		float64(stats.TotalKeyResults-stats.CompletedKeyResults), "pending")
+	ch <- prometheus.MustNewConstMetric(c.completionRate, prometheus.GaugeValue, stats.CompletionRate())
+}
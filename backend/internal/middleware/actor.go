@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/conquista-ai/conquista-ai/internal/actor"
+	"github.com/gin-gonic/gin"
+)
+
+// Actor guarda no contexto da requisição o autor informado no cabeçalho X-Actor. Sem o
+// cabeçalho (ou com um valor longo demais) a requisição é atribuída a anonymous
+func Actor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := strings.TrimSpace(c.GetHeader(actor.Header))
+		if name == "" || len(name) > actor.MaxLength {
+			name = actor.Anonymous
+		}
+
+		c.Request = c.Request.WithContext(actor.WithActor(c.Request.Context(), name))
+		c.Next()
+	}
+}
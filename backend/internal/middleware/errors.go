@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+	"github.com/conquista-ai/conquista-ai/internal/logging"
+	"github.com/gin-gonic/gin"
+)
+
+const problemContentType = "application/problem+json"
+
+// Problem é o corpo de erro no formato RFC 7807 (application/problem+json)
+type Problem struct {
+	Type     string                 `json:"type"`
+	Title    string                 `json:"title"`
+	Status   int                    `json:"status"`
+	Detail   string                 `json:"detail,omitempty"`
+	Instance string                 `json:"instance,omitempty"`
+	Errors   []apperrors.FieldError `json:"errors,omitempty"`
+}
+
+type problemKind struct {
+	status int
+	slug   string
+	title  string
+}
+
+var problemKinds = []struct {
+	kind error
+	problemKind
+}{
+	{apperrors.ErrNotFound, problemKind{http.StatusNotFound, "not-found", "Recurso não encontrado"}},
+	{apperrors.ErrValidation, problemKind{http.StatusBadRequest, "validation", "Dados inválidos"}},
+	{apperrors.ErrConflict, problemKind{http.StatusConflict, "conflict", "Conflito"}},
+	{apperrors.ErrUnauthorized, problemKind{http.StatusUnauthorized, "unauthorized", "Não autenticado"}},
+	{apperrors.ErrForbidden, problemKind{http.StatusForbidden, "forbidden", "Operação não permitida"}},
+	{apperrors.ErrUpstream, problemKind{http.StatusBadGateway, "upstream", "Falha em serviço externo"}},
+}
+
+var internalProblem = problemKind{http.StatusInternalServerError, "internal", "Erro interno"}
+
+// ErrorHandler converte os erros registrados pelos handlers via c.Error em respostas
+// application/problem+json. Apenas o último erro é respondido
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		problem := NewProblem(err, c.Request.URL.Path)
+		if problem.Status >= http.StatusInternalServerError {
+			logging.FromContext(c.Request.Context()).Error("erro ao processar requisição",
+				"method", c.Request.Method, "path", c.Request.URL.Path, "error", err)
+		}
+
+		c.Header("Content-Type", problemContentType)
+		c.AbortWithStatusJSON(problem.Status, problem)
+	}
+}
+
+// NewProblem monta o Problem correspondente a um erro de domínio
+func NewProblem(err error, instance string) Problem {
+	kind := internalProblem
+	for _, pk := range problemKinds {
+		if errors.Is(err, pk.kind) {
+			kind = pk.problemKind
+			break
+		}
+	}
+
+	problem := Problem{
+		Type:     "/problems/" + kind.slug,
+		Title:    kind.title,
+		Status:   kind.status,
+		Instance: instance,
+	}
+
+	var appErr *apperrors.Error
+	if errors.As(err, &appErr) {
+		problem.Detail = appErr.Detail
+		problem.Errors = appErr.Fields
+	}
+
+	return problem
+}
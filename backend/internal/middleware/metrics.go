@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics registra a latência de cada requisição no histograma por rota. A rota é o
+// padrão registrado no Gin (ex.: /api/v1/okrs/:id) para manter a cardinalidade baixa
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		metrics.HTTPRequestDuration.
+			WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
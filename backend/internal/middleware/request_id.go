@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/logging"
+	"github.com/conquista-ai/conquista-ai/internal/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// RequestID reaproveita o cabeçalho X-Request-ID recebido ou gera um novo ID,
+// devolvendo-o na resposta e guardando-o no contexto da requisição
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestid.Header)
+		if id == "" || len(id) > 128 {
+			id = requestid.New()
+		}
+
+		c.Request = c.Request.WithContext(requestid.WithID(c.Request.Context(), id))
+		c.Header(requestid.Header, id)
+		c.Next()
+	}
+}
+
+// RequestLogger registra cada requisição com log/slog ao final do processamento
+func RequestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		logger := logging.FromContext(c.Request.Context())
+		attrs := []any{
+			"method", c.Request.Method,
+			"route", c.FullPath(),
+			"path", c.Request.URL.Path,
+			"status", status,
+			"duration_ms", time.Since(start).Milliseconds(),
+			"client_ip", c.ClientIP(),
+		}
+
+		switch {
+		case status >= 500:
+			logger.Error("requisição concluída", attrs...)
+		case status >= 400:
+			logger.Warn("requisição concluída", attrs...)
+		default:
+			logger.Info("requisição concluída", attrs...)
+		}
+	}
+}
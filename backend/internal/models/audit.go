@@ -0,0 +1,62 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Entidades registradas no log de auditoria
+const (
+	AuditEntityCategory            = "category"
+	AuditEntityOKR                 = "okr"
+	AuditEntityKeyResult           = "key_result"
+	AuditEntityRoadmapItem         = "roadmap_item"
+	AuditEntityEducationalResource = "educational_resource"
+	AuditEntityTrailActivity       = "trail_activity"
+)
+
+var AuditEntities = []string{
+	AuditEntityCategory,
+	AuditEntityOKR,
+	AuditEntityKeyResult,
+	AuditEntityRoadmapItem,
+	AuditEntityEducationalResource,
+	AuditEntityTrailActivity,
+}
+
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionRestore = "restore"
+)
+
+// AuditChange é o valor de um campo antes e depois da mudança. Na criação before é
+// null; na exclusão, after
+type AuditChange struct {
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// AuditEvent é uma mudança registrada no log de auditoria. Changes traz apenas os
+// campos alterados, com os nomes das colunas do banco
+type AuditEvent struct {
+	ID         int64                  `json:"id"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	Actor      string                 `json:"actor"`
+	RequestID  string                 `json:"request_id,omitempty"`
+	Entity     string                 `json:"entity"`
+	EntityID   int64                  `json:"entity_id"`
+	Action     string                 `json:"action"`
+	Changes    map[string]AuditChange `json:"changes"`
+}
+
+// AuditEventFilter filtra GET /activity. Os eventos são listados do mais recente para o
+// mais antigo
+type AuditEventFilter struct {
+	Entity   string
+	EntityID *int64
+	Action   string
+	Limit    int
+	Cursor   string
+}
@@ -0,0 +1,196 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Identificação e versão do formato do arquivo de backup. A versão muda quando o
+// formato deixa de ser compatível com os arquivos anteriores
+const (
+	BackupFormat  = "conquista-ai-backup"
+	BackupVersion = 1
+)
+
+// Estratégias de importação para OKRs que já existem no destino (mesmo objetivo na
+// mesma categoria)
+const (
+	// ImportSkip mantém o OKR existente e ignora o do arquivo
+	ImportSkip = "skip"
+	// ImportOverwrite move o OKR existente para a lixeira e importa o do arquivo
+	ImportOverwrite = "overwrite"
+	// ImportDuplicate importa o OKR do arquivo ao lado do existente
+	ImportDuplicate = "duplicate"
+)
+
+// ImportStrategies lista as estratégias aceitas
+var ImportStrategies = []string{ImportSkip, ImportOverwrite, ImportDuplicate}
+
+// Backup é o documento de exportação completa dos dados. Os IDs são os da instância de
+// origem e servem apenas para ligar os registros dentro do arquivo; na importação todos
+// recebem novos IDs
+type Backup struct {
+	Format     string           `json:"format"`
+	Version    int              `json:"version"`
+	ExportedAt time.Time        `json:"exported_at"`
+	Categories []BackupCategory `json:"categories"`
+	OKRs       []BackupOKR      `json:"okrs"`
+}
+
+// Validate confere o formato e a versão do arquivo e as referências entre os registros
+func (b *Backup) Validate() error {
+	if b.Format != BackupFormat {
+		return fmt.Errorf("formato de arquivo desconhecido: %q", b.Format)
+	}
+	if b.Version < 1 || b.Version > BackupVersion {
+		return fmt.Errorf("versão %d do arquivo não é suportada (máxima: %d)", b.Version, BackupVersion)
+	}
+
+	categories := make(map[int64]bool, len(b.Categories))
+	for _, c := range b.Categories {
+		categories[c.ID] = true
+	}
+	for _, okr := range b.OKRs {
+		if !categories[okr.CategoryID] {
+			return fmt.Errorf("OKR %d referencia a categoria %d, ausente no arquivo", okr.ID, okr.CategoryID)
+		}
+		if okr.Status != "" && !slices.Contains(OKRStatuses, okr.Status) {
+			return fmt.Errorf("OKR %d com status inválido: %q", okr.ID, okr.Status)
+		}
+		for _, kr := range okr.KeyResults {
+			// Dificuldade ausente (0) assume o valor padrão na importação
+			if kr.Difficulty != 0 && (kr.Difficulty < MinKeyResultDifficulty || kr.Difficulty > MaxKeyResultDifficulty) {
+				return fmt.Errorf("Key Result %d com dificuldade inválida: %d", kr.ID, kr.Difficulty)
+			}
+		}
+	}
+	return nil
+}
+
+type BackupCategory struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type BackupOKR struct {
+	ID             int64             `json:"id"`
+	Objective      string            `json:"objective"`
+	CategoryID     int64             `json:"category_id"`
+	CompletionDate *time.Time        `json:"completion_date,omitempty"`
+	Status         string            `json:"status"`
+	StatusReason   *string           `json:"status_reason,omitempty"`
+	ArchivedAt     *time.Time        `json:"archived_at,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	KeyResults     []BackupKeyResult `json:"key_results"`
+}
+
+type BackupKeyResult struct {
+	ID                     int64          `json:"id"`
+	Title                  string         `json:"title"`
+	Completed              bool           `json:"completed"`
+	Difficulty             int            `json:"difficulty"`
+	ExpectedCompletionDate *time.Time     `json:"expected_completion_date,omitempty"`
+	DueDateComputed        bool           `json:"due_date_computed"`
+	CreatedAt              time.Time      `json:"created_at"`
+	Roadmap                *BackupRoadmap `json:"roadmap,omitempty"`
+}
+
+type BackupRoadmap struct {
+	ID         int64                   `json:"id"`
+	Topic      string                  `json:"topic"`
+	Categories []BackupRoadmapCategory `json:"categories"`
+}
+
+type BackupRoadmapCategory struct {
+	ID    int64               `json:"id"`
+	Name  string              `json:"name"`
+	Items []BackupRoadmapItem `json:"items"`
+}
+
+type BackupRoadmapItem struct {
+	ID                 int64                     `json:"id"`
+	Title              string                    `json:"title"`
+	Completed          bool                      `json:"completed"`
+	EducationalRoadmap *BackupEducationalRoadmap `json:"educational_roadmap,omitempty"`
+	Trail              *BackupTrail              `json:"trail,omitempty"`
+}
+
+type BackupEducationalRoadmap struct {
+	ID        int64                       `json:"id"`
+	Topic     string                      `json:"topic"`
+	Resources []BackupEducationalResource `json:"resources"`
+}
+
+type BackupEducationalResource struct {
+	ID          int64    `json:"id"`
+	Type        string   `json:"type"`
+	Title       string   `json:"title"`
+	Description *string  `json:"description,omitempty"`
+	URL         *string  `json:"url,omitempty"`
+	Author      *string  `json:"author,omitempty"`
+	Duration    *string  `json:"duration,omitempty"`
+	Completed   bool     `json:"completed"`
+	Chapters    []string `json:"chapters,omitempty"`
+}
+
+type BackupTrail struct {
+	ID          int64                 `json:"id"`
+	Topic       string                `json:"topic"`
+	TotalDays   int                   `json:"total_days"`
+	Description *string               `json:"description,omitempty"`
+	StartDate   *time.Time            `json:"start_date,omitempty"`
+	Resources   []BackupTrailResource `json:"resources"`
+	Steps       []BackupTrailStep     `json:"steps"`
+}
+
+type BackupTrailResource struct {
+	ID int64 `json:"id"`
+	// Identificador do recurso dentro da trilha, referenciado pelas atividades
+	ResourceID  string   `json:"resource_id"`
+	Title       string   `json:"title"`
+	Description *string  `json:"description,omitempty"`
+	Author      *string  `json:"author,omitempty"`
+	Duration    *string  `json:"duration,omitempty"`
+	URL         *string  `json:"url,omitempty"`
+	Chapters    []string `json:"chapters,omitempty"`
+}
+
+type BackupTrailStep struct {
+	ID            int64                 `json:"id"`
+	Day           int                   `json:"day"`
+	Title         string                `json:"title"`
+	Description   *string               `json:"description,omitempty"`
+	ScheduledDate *time.Time            `json:"scheduled_date,omitempty"`
+	Activities    []BackupTrailActivity `json:"activities"`
+}
+
+type BackupTrailActivity struct {
+	ID          int64      `json:"id"`
+	Type        string     `json:"type"`
+	ResourceID  string     `json:"resource_id"`
+	Title       string     `json:"title"`
+	Description *string    `json:"description,omitempty"`
+	Duration    *string    `json:"duration,omitempty"`
+	URL         *string    `json:"url,omitempty"`
+	Progress    *string    `json:"progress,omitempty"`
+	Completed   bool       `json:"completed"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	Chapters    []string   `json:"chapters,omitempty"`
+}
+
+// ImportResult resume uma importação. Em dry-run nada é gravado e os IDs de IDMap são
+// apenas os que seriam atribuídos
+type ImportResult struct {
+	DryRun   bool   `json:"dry_run"`
+	Strategy string `json:"strategy"`
+	// Registros criados por tipo (category, okr, key_result, roadmap, roadmap_item,
+	// educational_roadmap, educational_resource, trail, trail_step, trail_activity)
+	Created map[string]int `json:"created"`
+	// OKRs existentes mantidos (skip) ou enviados para a lixeira (overwrite)
+	Skipped     int `json:"skipped"`
+	Overwritten int `json:"overwritten"`
+	// Correspondência entre os IDs do arquivo e os da instância, por tipo (category, okr
+	// e key_result). OKRs ignorados apontam para o OKR existente
+	IDMap map[string]map[int64]int64 `json:"id_map"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Origens dos itens do feed de calendário
+const (
+	CalendarEntryOKR       = "okr"
+	CalendarEntryKeyResult = "key_result"
+	CalendarEntryTrailStep = "trail_step"
+)
+
+// CalendarEntry é uma data do planejamento exibida no feed iCalendar: prazo de OKR,
+// data esperada de Key Result ou etapa agendada de trilha educacional
+type CalendarEntry struct {
+	Kind  string
+	ID    int64
+	Title string
+	// Contexto do item: objetivo do OKR (Key Results) ou tópico da trilha (etapas)
+	Context   string
+	Date      time.Time
+	Completed bool
+	UpdatedAt time.Time
+}
+
+// CalendarFeedToken é retornado apenas na geração; o token não pode ser consultado depois
+type CalendarFeedToken struct {
+	Token     string    `json:"token"`
+	FeedURL   string    `json:"feed_url"`
+	CreatedAt time.Time `json:"created_at"`
+}
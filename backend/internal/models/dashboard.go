@@ -0,0 +1,94 @@
+package models
+
+import "time"
+
+// Dashboard é a resposta de GET /dashboard
+type Dashboard struct {
+	GeneratedAt       time.Time          `json:"generated_at"`
+	Totals            DashboardTotals    `json:"totals"`
+	Categories        []CategoryProgress `json:"categories"`
+	OKRs              []OKRProgress      `json:"okrs"`
+	UpcomingDeadlines []UpcomingDeadline `json:"upcoming_deadlines"`
+	RecentActivity    []RecentActivity   `json:"recent_activity"`
+	Roadmaps          RoadmapProgress    `json:"roadmaps"`
+	Trails            TrailProgress      `json:"trails"`
+}
+
+// DashboardTotals resume OKRs e Key Results. Um OKR está concluído quando todos os
+// seus Key Results estão; em andamento quando apenas parte deles está
+type DashboardTotals struct {
+	OKRs                int     `json:"okrs"`
+	CompletedOKRs       int     `json:"completed_okrs"`
+	InProgressOKRs      int     `json:"in_progress_okrs"`
+	NotStartedOKRs      int     `json:"not_started_okrs"`
+	KeyResults          int     `json:"key_results"`
+	CompletedKeyResults int     `json:"completed_key_results"`
+	OverdueKeyResults   int     `json:"overdue_key_results"`
+	CompletionRate      float64 `json:"completion_rate"`
+	// Média do progresso (0 a 100) dos OKRs
+	AverageProgress int `json:"average_progress"`
+}
+
+// CategoryProgress é a taxa de conclusão dos Key Results dos OKRs de uma categoria
+type CategoryProgress struct {
+	CategoryID          int64   `json:"category_id"`
+	Name                string  `json:"name"`
+	OKRs                int     `json:"okrs"`
+	KeyResults          int     `json:"key_results"`
+	CompletedKeyResults int     `json:"completed_key_results"`
+	CompletionRate      float64 `json:"completion_rate"`
+}
+
+// OKRProgress é o progresso (0 a 100) de um OKR pela fração de Key Results concluídos
+type OKRProgress struct {
+	OKRID               int64 `json:"okr_id"`
+	KeyResults          int   `json:"key_results"`
+	CompletedKeyResults int   `json:"completed_key_results"`
+	Progress            int   `json:"progress"`
+}
+
+// UpcomingDeadline é um OKR ou Key Result pendente com prazo nos próximos dias
+type UpcomingDeadline struct {
+	Kind  string `json:"kind"` // okr ou key_result
+	ID    int64  `json:"id"`
+	OKRID int64  `json:"okr_id"`
+	Title string `json:"title"`
+	// Data no formato YYYY-MM-DD
+	DueDate       string `json:"due_date"`
+	DaysRemaining int    `json:"days_remaining"`
+}
+
+// Tipos de atividade recente
+const (
+	ActivityOKRCreated             = "okr_created"
+	ActivityKeyResultCompleted     = "key_result_completed"
+	ActivityTrailActivityCompleted = "trail_activity_completed"
+	ActivityStudySession           = "study_session"
+)
+
+// RecentActivity é um acontecimento recente. Para Key Results concluídos o instante é o
+// da última atualização do Key Result
+type RecentActivity struct {
+	Kind    string    `json:"kind"`
+	OKRID   int64     `json:"okr_id"`
+	Title   string    `json:"title"`
+	Minutes *int      `json:"minutes,omitempty"`
+	At      time.Time `json:"at"`
+}
+
+type RoadmapProgress struct {
+	Roadmaps       int     `json:"roadmaps"`
+	Items          int     `json:"items"`
+	CompletedItems int     `json:"completed_items"`
+	CompletionRate float64 `json:"completion_rate"`
+}
+
+type TrailProgress struct {
+	Trails              int     `json:"trails"`
+	Activities          int     `json:"activities"`
+	CompletedActivities int     `json:"completed_activities"`
+	CompletionRate      float64 `json:"completion_rate"`
+	// Etapas pendentes agendadas para antes de hoje
+	OverdueSteps   int `json:"overdue_steps"`
+	StudiedMinutes int `json:"studied_minutes"`
+}
@@ -8,6 +8,10 @@ type EducationalTrail struct {
 	Topic        string                   `json:"topic"`
 	TotalDays    int                      `json:"total_days"`
 	Description  string                   `json:"description"`
+	// Data a partir da qual as etapas foram agendadas no calendário de estudo
+	StartDate    *time.Time               `json:"start_date,omitempty"`
+	// Etapas pendentes com data agendada anterior a hoje (indica que a trilha precisa ser replanejada)
+	OverdueSteps int                      `json:"overdue_steps"`
 	Steps        []EducationalTrailStep   `json:"steps"`
 	Resources    map[string]TrailResource `json:"resources"`
 	CreatedAt    time.Time                `json:"created_at"`
@@ -18,12 +22,24 @@ type EducationalTrailStep struct {
 	ID          int64                  `json:"id"`
 	TrailID     int64                  `json:"trail_id"`
 	Day         int                    `json:"day"`
+	// Data real do "dia" da etapa segundo o calendário de estudo
+	ScheduledDate *time.Time           `json:"scheduled_date,omitempty"`
 	Title       string                 `json:"title"`
 	Description string                 `json:"description"`
 	Activities  []TrailActivity        `json:"activities"`
 	CreatedAt   time.Time              `json:"created_at"`
 }
 
+// Completed indica se todas as atividades da etapa foram concluídas
+func (s EducationalTrailStep) Completed() bool {
+	for _, activity := range s.Activities {
+		if !activity.Completed {
+			return false
+		}
+	}
+	return true
+}
+
 type TrailActivity struct {
 	ID          int64     `json:"id"`
 	StepID      int64     `json:"step_id"`
@@ -36,6 +52,12 @@ type TrailActivity struct {
 	URL         string    `json:"url,omitempty"`
 	Progress    string    `json:"progress,omitempty"`
 	Completed   bool      `json:"completed"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	// Duração estimada em minutos, interpretada a partir de Duration (ausente quando não reconhecida)
+	EstimatedMinutes *int `json:"estimated_minutes,omitempty"`
+	// Tempo de estudo registrado nas sessões (cronômetro e lançamentos manuais)
+	SpentMinutes   int        `json:"spent_minutes"`
+	TimerStartedAt *time.Time `json:"timer_started_at,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
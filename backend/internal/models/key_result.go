@@ -2,12 +2,22 @@ package models
 
 import "time"
 
+// Escala de dificuldade de um Key Result, usada como peso na distribuição de prazos
+const (
+	MinKeyResultDifficulty     = 1
+	MaxKeyResultDifficulty     = 5
+	DefaultKeyResultDifficulty = 3
+)
+
 type KeyResult struct {
 	ID                   int64      `json:"id"`
 	OKRID                int64      `json:"okr_id"`
 	Title                string     `json:"title"`
 	Completed            bool       `json:"completed"`
+	Difficulty           int        `json:"difficulty"`
 	ExpectedCompletionDate *time.Time `json:"expected_completion_date,omitempty"`
+	// true quando a data esperada foi calculada pelo planejamento (POST /okrs/:id/schedule)
+	DueDateComputed      bool       `json:"due_date_computed"`
 	CreatedAt            time.Time  `json:"created_at"`
 	UpdatedAt            time.Time  `json:"updated_at"`
 }
@@ -15,11 +25,50 @@ type KeyResult struct {
 type CreateKeyResultRequest struct {
 	OKRID                int64   `json:"okr_id" binding:"required"`
 	Title                string  `json:"title" binding:"required"`
+	Difficulty           *int    `json:"difficulty,omitempty" binding:"omitempty,min=1,max=5"`
 	ExpectedCompletionDate *string `json:"expected_completion_date,omitempty"`
 }
 
 type UpdateKeyResultRequest struct {
-	Title     string `json:"title" binding:"required"`
-	Completed bool   `json:"completed"`
+	Title      string `json:"title" binding:"required"`
+	Completed  bool   `json:"completed"`
+	Difficulty *int   `json:"difficulty,omitempty" binding:"omitempty,min=1,max=5"`
 }
 
+
+// EffectiveDifficulty retorna a dificuldade do Key Result ou a padrão quando não informada
+func (kr KeyResult) EffectiveDifficulty() int {
+	if kr.Difficulty < MinKeyResultDifficulty || kr.Difficulty > MaxKeyResultDifficulty {
+		return DefaultKeyResultDifficulty
+	}
+	return kr.Difficulty
+}
+
+// ScheduleRequest controla o recálculo das datas esperadas dos Key Results de um OKR
+type ScheduleRequest struct {
+	// Apply grava as datas propostas; sem ele a resposta é apenas uma prévia
+	Apply bool `json:"apply"`
+	// Strategy sobrescreve a estratégia configurada (even, weighted, front_loaded, back_loaded)
+	Strategy string `json:"strategy,omitempty" binding:"omitempty,oneof=even weighted front_loaded back_loaded"`
+	// IncludeManual também recalcula as datas definidas manualmente
+	IncludeManual bool `json:"include_manual"`
+}
+
+// ScheduleChange é a data proposta para um Key Result
+type ScheduleChange struct {
+	KeyResultID  int64   `json:"key_result_id"`
+	Title        string  `json:"title"`
+	Difficulty   int     `json:"difficulty"`
+	CurrentDate  *string `json:"current_date,omitempty"`
+	ProposedDate string  `json:"proposed_date"`
+	Changed      bool    `json:"changed"`
+}
+
+// ScheduleResult descreve o recálculo das datas de um OKR
+type ScheduleResult struct {
+	OKRID          int64            `json:"okr_id"`
+	Strategy       string           `json:"strategy"`
+	CompletionDate string           `json:"completion_date"`
+	Applied        bool             `json:"applied"`
+	Changes        []ScheduleChange `json:"changes"`
+}
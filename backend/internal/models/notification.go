@@ -0,0 +1,121 @@
+package models
+
+import "time"
+
+// Tipos de lembrete gerados pelo job de notificações
+const (
+	NotificationTrailToday       = "trail_today"         // atividades de trilha agendadas para hoje
+	NotificationKeyResultDueSoon = "key_result_due_soon" // Key Results que vencem em poucos dias
+	NotificationOverdue          = "overdue"             // Key Results e etapas de trilha atrasados
+	NotificationWeeklyCheckIn    = "weekly_checkin"      // convite semanal para revisar o progresso
+)
+
+// NotificationKinds lista os tipos de lembrete
+var NotificationKinds = []string{
+	NotificationTrailToday,
+	NotificationKeyResultDueSoon,
+	NotificationOverdue,
+	NotificationWeeklyCheckIn,
+}
+
+// DefaultNotificationTimezone é o fuso das preferências que não informam um
+const DefaultNotificationTimezone = "America/Sao_Paulo"
+
+// Canais de entrega
+const (
+	ChannelInbox   = "inbox"
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+)
+
+// Situação de uma entrega
+const (
+	DeliveryPending = "pending"
+	DeliverySent    = "sent"
+	DeliveryFailed  = "failed"
+)
+
+// Notification é um lembrete. DedupKey identifica o lembrete entre execuções do job
+// (ex.: key_result_due_soon:42:2026-10-22) e não é exposto na caixa de entrada
+type Notification struct {
+	ID        int64      `json:"id"`
+	Kind      string     `json:"kind"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	DedupKey  string     `json:"-"`
+	CreatedAt time.Time  `json:"created_at"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+}
+
+// NotificationDelivery é a entrega de um lembrete por um canal
+type NotificationDelivery struct {
+	ID            int64
+	Channel       string
+	Status        string
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	Notification  Notification
+}
+
+// NotificationPreferences define os canais, os tipos de lembrete e o horário de
+// silêncio do usuário. Horários e datas são avaliados no fuso Timezone. Durante o horário de silêncio apenas a caixa de entrada recebe
+// lembretes; e-mails e webhooks são entregues quando ele termina
+type NotificationPreferences struct {
+	InboxEnabled   bool     `json:"inbox_enabled"`
+	EmailEnabled   bool     `json:"email_enabled"`
+	EmailAddress   string   `json:"email_address"`
+	WebhookEnabled bool     `json:"webhook_enabled"`
+	WebhookURL     string   `json:"webhook_url"`
+	Kinds          []string `json:"kinds"`
+	// Horário de silêncio no formato HH:MM (pode cruzar a meia-noite, ex.: 22:00 a 07:00)
+	QuietHoursStart *string `json:"quiet_hours_start,omitempty"`
+	QuietHoursEnd   *string `json:"quiet_hours_end,omitempty"`
+	// Dia da semana do check-in semanal: 0 = domingo ... 6 = sábado
+	WeeklyCheckInWeekday int `json:"weekly_checkin_weekday"`
+	// Fuso horário IANA do usuário (ex.: America/Sao_Paulo)
+	Timezone  string    `json:"timezone"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Channels retorna os canais ativados
+func (p NotificationPreferences) Channels() []string {
+	var channels []string
+	if p.InboxEnabled {
+		channels = append(channels, ChannelInbox)
+	}
+	if p.EmailEnabled {
+		channels = append(channels, ChannelEmail)
+	}
+	if p.WebhookEnabled {
+		channels = append(channels, ChannelWebhook)
+	}
+	return channels
+}
+
+type UpdateNotificationPreferencesRequest struct {
+	InboxEnabled         bool     `json:"inbox_enabled"`
+	EmailEnabled         bool     `json:"email_enabled"`
+	EmailAddress         string   `json:"email_address" binding:"omitempty,email"`
+	WebhookEnabled       bool     `json:"webhook_enabled"`
+	WebhookURL           string   `json:"webhook_url" binding:"omitempty,url"`
+	Kinds                []string `json:"kinds" binding:"dive,oneof=trail_today key_result_due_soon overdue weekly_checkin"`
+	QuietHoursStart      *string  `json:"quiet_hours_start,omitempty"`
+	QuietHoursEnd        *string  `json:"quiet_hours_end,omitempty"`
+	WeeklyCheckInWeekday int      `json:"weekly_checkin_weekday" binding:"min=0,max=6"`
+	// Sem timezone, vale DefaultNotificationTimezone
+	Timezone string `json:"timezone"`
+}
+
+// NotificationInbox é a resposta de GET /notifications
+type NotificationInbox struct {
+	Items       []Notification `json:"items"`
+	UnreadCount int            `json:"unread_count"`
+}
+
+// NotificationTestResult informa o resultado do envio de teste em cada canal ativado
+type NotificationTestResult struct {
+	Channel string `json:"channel"`
+	Sent    bool   `json:"sent"`
+	Error   string `json:"error,omitempty"`
+}
@@ -8,14 +8,25 @@ type OKR struct {
 	CategoryID     int64      `json:"category_id"`
 	Category       *Category  `json:"category,omitempty"`
 	CompletionDate *time.Time `json:"completion_date,omitempty"`
-	CreatedAt      time.Time  `json:"created_at"`
-	UpdatedAt      time.Time  `json:"updated_at"`
+	// Preenchido quando o OKR está arquivado; seus Key Results e roadmaps são
+	// considerados arquivados junto com ele
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+	Status     string     `json:"status"`
+	// Motivo informado na última mudança de status (obrigatório ao abandonar)
+	StatusReason    *string    `json:"status_reason,omitempty"`
+	StatusChangedAt *time.Time `json:"status_changed_at,omitempty"`
+	// Situação do OKR em relação ao prazo (preenchida nas respostas de leitura)
+	Risk      *RiskAssessment `json:"risk,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
 }
 
 type CreateOKRRequest struct {
 	Objective      string  `json:"objective" binding:"required"`
 	CategoryID     int64   `json:"category_id" binding:"required"`
 	CompletionDate *string `json:"completion_date,omitempty"`
+	// Status inicial: draft ou active (padrão)
+	Status string `json:"status,omitempty" binding:"omitempty,oneof=draft active"`
 }
 
 type UpdateOKRRequest struct {
@@ -24,3 +35,49 @@ type UpdateOKRRequest struct {
 	CompletionDate *string `json:"completion_date,omitempty"`
 }
 
+// Status do ciclo de vida de um OKR
+const (
+	OKRStatusDraft     = "draft"
+	OKRStatusActive    = "active"
+	OKRStatusOnHold    = "on_hold"
+	OKRStatusCompleted = "completed"
+	OKRStatusAbandoned = "abandoned"
+)
+
+// OKRStatuses lista os status válidos
+var OKRStatuses = []string{OKRStatusDraft, OKRStatusActive, OKRStatusOnHold, OKRStatusCompleted, OKRStatusAbandoned}
+
+// OKRTrackedStatuses lista os status em que os prazos do OKR são acompanhados
+// (lembretes, risco e calendário). OKRs pausados ou encerrados ficam de fora
+var OKRTrackedStatuses = []string{OKRStatusDraft, OKRStatusActive}
+
+type ChangeOKRStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=draft active on_hold completed abandoned"`
+	Reason string `json:"reason"`
+}
+
+// OKRStatusChange é uma transição do histórico de status. From é nulo no status inicial
+type OKRStatusChange struct {
+	ID        int64     `json:"id"`
+	OKRID     int64     `json:"okr_id"`
+	From      *string   `json:"from,omitempty"`
+	To        string    `json:"to"`
+	Reason    *string   `json:"reason,omitempty"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// OKRStats resume a situação geral dos OKRs e Key Results
+type OKRStats struct {
+	TotalOKRs           int
+	ActiveOKRs          int
+	TotalKeyResults     int
+	CompletedKeyResults int
+}
+
+// CompletionRate retorna a fração de Key Results concluídos (0 quando não há Key Results)
+func (s OKRStats) CompletionRate() float64 {
+	if s.TotalKeyResults == 0 {
+		return 0
+	}
+	return float64(s.CompletedKeyResults) / float64(s.TotalKeyResults)
+}
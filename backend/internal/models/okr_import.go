@@ -0,0 +1,45 @@
+package models
+
+// OKRImportOptions controla a importação de OKRs por planilha
+type OKRImportOptions struct {
+	// DryRun valida e simula a gravação sem persistir nada
+	DryRun bool
+	// GenerateKeyResults gera Key Results no Spellbook para os objetivos importados sem
+	// nenhum Key Result na planilha
+	GenerateKeyResults bool
+}
+
+// OKRImport é um OKR montado a partir das linhas da planilha, com seus Key Results
+type OKRImport struct {
+	OKR        OKR
+	KeyResults []KeyResult
+}
+
+// OKRImportRowError aponta um problema em uma linha da planilha. Row é a linha no
+// arquivo, contando o cabeçalho como linha 1
+type OKRImportRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// OKRImportGeneration é o resultado da geração de Key Results para um OKR importado
+type OKRImportGeneration struct {
+	OKRID      int64  `json:"okr_id"`
+	KeyResults int    `json:"key_results"`
+	Error      string `json:"error,omitempty"`
+}
+
+// OKRImportResult resume uma importação por planilha. Linhas com erro não são
+// gravadas, assim como as demais linhas do mesmo OKR; as válidas são gravadas em uma
+// única transação. Em dry-run os IDs são apenas os que seriam atribuídos
+type OKRImportResult struct {
+	DryRun            bool                  `json:"dry_run"`
+	Rows              int                   `json:"rows"`
+	ImportedRows      int                   `json:"imported_rows"`
+	OKRsCreated       int                   `json:"okrs_created"`
+	KeyResultsCreated int                   `json:"key_results_created"`
+	OKRIDs            []int64               `json:"okr_ids"`
+	Errors            []OKRImportRowError   `json:"errors"`
+	Generation        []OKRImportGeneration `json:"key_result_generation,omitempty"`
+}
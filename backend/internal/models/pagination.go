@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// ListParams agrupa os parâmetros de paginação e ordenação comuns aos endpoints de listagem
+type ListParams struct {
+	Limit  int
+	Cursor string
+	// Sort é o nome do campo de ordenação; o prefixo "-" indica ordem decrescente
+	Sort string
+}
+
+// OKRFilter define os filtros aceitos por GET /okrs
+type OKRFilter struct {
+	ListParams
+	CategoryID *int64
+	CycleStart *time.Time
+	CycleEnd   *time.Time
+	Completed  *bool
+	DueBefore  *time.Time
+	DueAfter   *time.Time
+	Search     string
+	// Archived filtra OKRs arquivados (true) ou não arquivados (false); nil lista ambos
+	Archived *bool
+	Status   string
+}
+
+// KeyResultFilter define os filtros aceitos por GET /key-results
+type KeyResultFilter struct {
+	ListParams
+	OKRID      *int64
+	CategoryID *int64
+	CycleStart *time.Time
+	CycleEnd   *time.Time
+	Completed  *bool
+	DueBefore  *time.Time
+	DueAfter   *time.Time
+	Search     string
+	// Archived filtra pelos Key Results de OKRs arquivados (true) ou não arquivados
+	// (false); nil lista ambos
+	Archived *bool
+	// Status filtra pelo status do OKR
+	Status string
+}
+
+// Page é o envelope de resposta das listagens paginadas por cursor
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	Total      int    `json:"total"`
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// Classificação de risco de OKRs, Key Results e trilhas educacionais
+const (
+	RiskOnTrack  = "on_track"
+	RiskAtRisk   = "at_risk"
+	RiskOffTrack = "off_track"
+)
+
+// RiskLevels lista as classificações, da melhor para a pior
+var RiskLevels = []string{RiskOnTrack, RiskAtRisk, RiskOffTrack}
+
+// RiskAssessment compara o tempo decorrido do prazo com o progresso concluído
+type RiskAssessment struct {
+	Level string `json:"level"`
+	// Fração concluída (0 a 1)
+	Progress float64 `json:"progress"`
+	// Fração do prazo já decorrida (0 a 1); nula quando não há prazo
+	TimeElapsed   *float64   `json:"time_elapsed,omitempty"`
+	DueDate       *time.Time `json:"due_date,omitempty"`
+	DaysRemaining *int       `json:"days_remaining,omitempty"`
+	Overdue       bool       `json:"overdue"`
+	Reason        string     `json:"reason"`
+}
+
+type TrailRisk struct {
+	TrailID       int64  `json:"trail_id"`
+	RoadmapItemID int64  `json:"roadmap_item_id"`
+	Topic         string `json:"topic"`
+	RiskAssessment
+}
+
+type KeyResultRisk struct {
+	KeyResultID int64       `json:"key_result_id"`
+	Title       string      `json:"title"`
+	Trails      []TrailRisk `json:"trails"`
+	RiskAssessment
+}
+
+type OKRRisk struct {
+	OKRID      int64           `json:"okr_id"`
+	Objective  string          `json:"objective"`
+	KeyResults []KeyResultRisk `json:"key_results"`
+	RiskAssessment
+}
+
+// RiskSummary conta os itens de cada nível (chave: on_track, at_risk, off_track)
+type RiskSummary struct {
+	OKRs       map[string]int `json:"okrs"`
+	KeyResults map[string]int `json:"key_results"`
+	Trails     map[string]int `json:"trails"`
+}
+
+// RiskReport é a resposta de GET /reports/risk
+type RiskReport struct {
+	GeneratedAt time.Time   `json:"generated_at"`
+	Summary     RiskSummary `json:"summary"`
+	OKRs        []OKRRisk   `json:"okrs"`
+}
@@ -0,0 +1,28 @@
+package models
+
+const (
+	SearchTypeOKR                 = "okr"
+	SearchTypeKeyResult           = "key_result"
+	SearchTypeRoadmapItem         = "roadmap_item"
+	SearchTypeTrailStep           = "trail_step"
+	SearchTypeTrailResource       = "trail_resource"
+	SearchTypeEducationalResource = "educational_resource"
+)
+
+// SearchResult é um resultado da busca textual, com os IDs necessários para navegar até o conteúdo. O
+// Highlight é HTML escapado, com os termos encontrados entre <mark>
+type SearchResult struct {
+	Type          string  `json:"type"`
+	ID            int64   `json:"id"`
+	Title         string  `json:"title"`
+	Highlight     string  `json:"highlight"`
+	Rank          float64 `json:"rank"`
+	OKRID         *int64  `json:"okr_id,omitempty"`
+	KeyResultID   *int64  `json:"key_result_id,omitempty"`
+	RoadmapItemID *int64  `json:"roadmap_item_id,omitempty"`
+}
+
+type SearchResponse struct {
+	Query   string         `json:"query"`
+	Results []SearchResult `json:"results"`
+}
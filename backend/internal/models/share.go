@@ -0,0 +1,80 @@
+package models
+
+import "time"
+
+// CreateShareLinkRequest cria um link público para o roadmap. Sem expires_in_days o
+// link vale até ser revogado
+type CreateShareLinkRequest struct {
+	ExpiresInDays *int `json:"expires_in_days,omitempty" binding:"omitempty,min=1,max=365"`
+}
+
+// ShareLink é um link público de leitura do roadmap de um Key Result
+type ShareLink struct {
+	ID           int64      `json:"id"`
+	KeyResultID  int64      `json:"key_result_id"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	ViewCount    int        `json:"view_count"`
+	LastViewedAt *time.Time `json:"last_viewed_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	// Token e URL só são retornados na criação; depois não podem ser consultados
+	Token string `json:"token,omitempty"`
+	URL   string `json:"url,omitempty"`
+}
+
+// SharedRoadmap é a visão pública, somente leitura, de um roadmap com suas trilhas.
+// Não expõe IDs, dados do OKR nem anotações e tempo de estudo
+type SharedRoadmap struct {
+	Topic      string                  `json:"topic"`
+	KeyResult  string                  `json:"key_result"`
+	Categories []SharedRoadmapCategory `json:"categories"`
+	ExpiresAt  *time.Time              `json:"expires_at,omitempty"`
+}
+
+type SharedRoadmapCategory struct {
+	Name  string              `json:"name"`
+	Items []SharedRoadmapItem `json:"items"`
+}
+
+type SharedRoadmapItem struct {
+	Title     string       `json:"title"`
+	Completed bool         `json:"completed"`
+	Trail     *SharedTrail `json:"trail,omitempty"`
+}
+
+type SharedTrail struct {
+	Topic       string                `json:"topic"`
+	Description string                `json:"description,omitempty"`
+	TotalDays   int                   `json:"total_days"`
+	StartDate   *time.Time            `json:"start_date,omitempty"`
+	Steps       []SharedTrailStep     `json:"steps"`
+	Resources   []SharedTrailResource `json:"resources"`
+}
+
+type SharedTrailStep struct {
+	Day           int                   `json:"day"`
+	Title         string                `json:"title"`
+	Description   string                `json:"description,omitempty"`
+	ScheduledDate *time.Time            `json:"scheduled_date,omitempty"`
+	Completed     bool                  `json:"completed"`
+	Activities    []SharedTrailActivity `json:"activities"`
+}
+
+type SharedTrailActivity struct {
+	Type        string   `json:"type"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Duration    string   `json:"duration,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	Chapters    []string `json:"chapters,omitempty"`
+	Completed   bool     `json:"completed"`
+}
+
+type SharedTrailResource struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Author      string   `json:"author,omitempty"`
+	Duration    string   `json:"duration,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	Chapters    []string `json:"chapters,omitempty"`
+}
@@ -0,0 +1,81 @@
+package models
+
+import "time"
+
+// Origem de uma sessão de estudo
+const (
+	StudySessionTimer  = "timer"
+	StudySessionManual = "manual"
+)
+
+// StudySession é um período de estudo de uma atividade de trilha. EndedAt nulo indica
+// um cronômetro em andamento
+type StudySession struct {
+	ID         int64      `json:"id"`
+	ActivityID int64      `json:"activity_id"`
+	Source     string     `json:"source"`
+	StartedAt  time.Time  `json:"started_at"`
+	EndedAt    *time.Time `json:"ended_at,omitempty"`
+	Minutes    int        `json:"minutes"`
+	Note       string     `json:"note,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateStudySessionRequest lança manualmente um tempo de estudo
+type CreateStudySessionRequest struct {
+	Minutes int `json:"minutes" binding:"required,min=1,max=1440"`
+	// Dia do estudo (YYYY-MM-DD); hoje quando omitido
+	Date *string `json:"date,omitempty"`
+	Note string  `json:"note,omitempty"`
+}
+
+// StudyStats é a resposta de GET /stats/study
+type StudyStats struct {
+	GeneratedAt time.Time           `json:"generated_at"`
+	Streak      StudyStreak         `json:"streak"`
+	Weekly      []WeeklyStudyTime   `json:"weekly"`
+	Estimates   StudyEstimateReport `json:"estimates"`
+}
+
+// StudyStreak conta os dias seguidos com estudo (sessão registrada ou atividade
+// concluída). Dias fora do calendário de estudo não interrompem a sequência
+type StudyStreak struct {
+	Current       int        `json:"current"`
+	Longest       int        `json:"longest"`
+	StudiedToday  bool       `json:"studied_today"`
+	LastStudyDate *time.Time `json:"last_study_date,omitempty"`
+}
+
+// WeeklyStudyTime soma os minutos estudados em uma semana (segunda a domingo)
+type WeeklyStudyTime struct {
+	WeekStart  time.Time           `json:"week_start"`
+	Minutes    int                 `json:"minutes"`
+	Categories []CategoryStudyTime `json:"categories"`
+}
+
+// CategoryStudyTime é o tempo estudado nas trilhas dos OKRs de uma categoria
+type CategoryStudyTime struct {
+	CategoryID int64  `json:"category_id"`
+	Name       string `json:"name"`
+	Minutes    int    `json:"minutes"`
+}
+
+// StudyEstimateReport compara a duração estimada com o tempo real das atividades
+// concluídas que têm estimativa reconhecida e tempo registrado
+type StudyEstimateReport struct {
+	StudyEstimate
+	ByActivityType []ActivityTypeEstimate `json:"by_activity_type"`
+}
+
+type StudyEstimate struct {
+	Activities       int `json:"activities"`
+	EstimatedMinutes int `json:"estimated_minutes"`
+	ActualMinutes    int `json:"actual_minutes"`
+	// Ratio é real/estimado: acima de 1 indica que as atividades levam mais tempo que o previsto
+	Ratio *float64 `json:"ratio,omitempty"`
+}
+
+type ActivityTypeEstimate struct {
+	Type string `json:"type"`
+	StudyEstimate
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// Tipos de dia sem estudo
+const (
+	DayOffHoliday  = "holiday"  // feriado: um único dia
+	DayOffBlackout = "blackout" // bloqueio: intervalo de dias (ex.: férias, viagem)
+)
+
+// StudySettings descreve a disponibilidade de estudo do usuário, usada para
+// materializar os dias das trilhas educacionais em datas reais. Dias da trilha cuja
+// carga estimada cabe em HoursPerDay dividem a mesma data
+type StudySettings struct {
+	// Dias da semana com estudo: 0 = domingo ... 6 = sábado
+	StudyWeekdays []int         `json:"study_weekdays"`
+	HoursPerDay   float64       `json:"hours_per_day"`
+	DaysOff       []StudyDayOff `json:"days_off"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+type StudyDayOff struct {
+	ID          int64     `json:"id"`
+	Kind        string    `json:"kind"`
+	StartDate   time.Time `json:"start_date"`
+	EndDate     time.Time `json:"end_date"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type UpdateStudySettingsRequest struct {
+	StudyWeekdays []int   `json:"study_weekdays" binding:"required,min=1,max=7,dive,min=0,max=6"`
+	HoursPerDay   float64 `json:"hours_per_day" binding:"required,gt=0,max=24"`
+}
+
+// CreateStudyDayOffRequest cadastra um feriado (start_date) ou um bloqueio
+// (start_date até end_date, inclusive)
+type CreateStudyDayOffRequest struct {
+	Kind        string  `json:"kind" binding:"required,oneof=holiday blackout"`
+	StartDate   string  `json:"start_date" binding:"required"`
+	EndDate     *string `json:"end_date,omitempty"`
+	Description string  `json:"description"`
+}
+
+// ReplanTrailRequest redistribui as etapas pendentes de uma trilha a partir de
+// start_date (padrão: hoje)
+type ReplanTrailRequest struct {
+	StartDate *string `json:"start_date,omitempty"`
+}
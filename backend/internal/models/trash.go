@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Tipos de item da lixeira
+const (
+	TrashTypeOKR       = "okr"
+	TrashTypeKeyResult = "key_result"
+	TrashTypeRoadmap   = "roadmap"
+)
+
+// TrashItem é um OKR, Key Result ou roadmap excluído. Context é o objetivo do OKR (para
+// Key Results) ou o título do Key Result (para roadmaps)
+type TrashItem struct {
+	Type        string    `json:"type"`
+	ID          int64     `json:"id"`
+	Title       string    `json:"title"`
+	Context     string    `json:"context,omitempty"`
+	OKRID       int64     `json:"okr_id"`
+	KeyResultID *int64    `json:"key_result_id,omitempty"`
+	DeletedAt   time.Time `json:"deleted_at"`
+	// Momento a partir do qual o item é removido definitivamente (ausente quando a
+	// lixeira não expira)
+	PurgeAt *time.Time `json:"purge_at,omitempty"`
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Eventos de domínio publicados nos webhooks de integração
+const (
+	EventOKRCreated                  = "okr.created"
+	EventOKRStatusChanged            = "okr.status_changed"
+	EventKeyResultCompleted          = "key_result.completed"
+	EventRoadmapGenerated            = "roadmap.generated"
+	EventEducationalRoadmapGenerated = "educational_roadmap.generated"
+	EventEducationalTrailGenerated   = "educational_trail.generated"
+	EventTrailActivityChecked        = "trail_activity.checked"
+)
+
+// WebhookEvents lista os eventos aceitos nas assinaturas
+var WebhookEvents = []string{
+	EventOKRCreated,
+	EventOKRStatusChanged,
+	EventKeyResultCompleted,
+	EventRoadmapGenerated,
+	EventEducationalRoadmapGenerated,
+	EventEducationalTrailGenerated,
+	EventTrailActivityChecked,
+}
+
+// Situação de uma entrega de webhook
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// WebhookSubscription é um destino de eventos. O segredo só é exposto na criação e
+// na rotação; nas demais respostas o campo é omitido
+type WebhookSubscription struct {
+	ID        int64     `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"secret,omitempty"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CreateWebhookSubscriptionRequest struct {
+	URL    string   `json:"url" binding:"required,url"`
+	Events []string `json:"events" binding:"required,min=1,dive,oneof=okr.created key_result.completed roadmap.generated educational_roadmap.generated educational_trail.generated trail_activity.checked"`
+	// Segredo da assinatura HMAC; gerado automaticamente quando omitido
+	Secret string `json:"secret,omitempty" binding:"omitempty,min=16"`
+}
+
+type UpdateWebhookSubscriptionRequest struct {
+	URL    string   `json:"url" binding:"required,url"`
+	Events []string `json:"events" binding:"required,min=1,dive,oneof=okr.created key_result.completed roadmap.generated educational_roadmap.generated educational_trail.generated trail_activity.checked"`
+	Active bool     `json:"active"`
+}
+
+// WebhookEvent é o corpo enviado aos assinantes
+type WebhookEvent struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// WebhookDelivery registra o envio de um evento a uma assinatura
+type WebhookDelivery struct {
+	ID             int64      `json:"id"`
+	SubscriptionID int64      `json:"subscription_id"`
+	EventID        string     `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	ResponseStatus *int       `json:"response_status,omitempty"`
+	ResponseBody   string     `json:"response_body,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	DurationMs     *int       `json:"duration_ms,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	LastAttemptAt  *time.Time `json:"last_attempt_at,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
+
+// WebhookDeliveryFilter define os filtros aceitos por GET /webhooks/:id/deliveries
+type WebhookDeliveryFilter struct {
+	SubscriptionID int64
+	Status         string
+	Limit          int
+}
+
+// TrailActivityCheckedData é o payload do evento trail_activity.checked
+type TrailActivityCheckedData struct {
+	ActivityID    int64  `json:"activity_id"`
+	Title         string `json:"title"`
+	StepID        int64  `json:"step_id"`
+	TrailID       int64  `json:"trail_id"`
+	RoadmapItemID int64  `json:"roadmap_item_id"`
+}
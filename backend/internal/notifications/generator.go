@@ -0,0 +1,179 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	// Embute a base de fusos horários: a imagem do container não traz o tzdata
+	_ "time/tzdata"
+
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/conquista-ai/conquista-ai/internal/planning"
+	"github.com/conquista-ai/conquista-ai/internal/repositories"
+)
+
+// displayDateFormat é o formato das datas no texto dos lembretes
+const displayDateFormat = "02/01/2006"
+
+// Generator monta os lembretes do dia a partir do planejamento. A chave de
+// deduplicação de cada lembrete inclui a data de referência, de modo que ele é
+// gerado uma vez e volta a ser gerado apenas se o prazo mudar
+type Generator struct {
+	repo        *repositories.NotificationRepository
+	okrRepo     *repositories.OKRRepository
+	clock       planning.Clock
+	dueSoonDays int
+}
+
+func NewGenerator(repo *repositories.NotificationRepository, okrRepo *repositories.OKRRepository, clock planning.Clock, dueSoonDays int) *Generator {
+	return &Generator{
+		repo:        repo,
+		okrRepo:     okrRepo,
+		clock:       clock,
+		dueSoonDays: dueSoonDays,
+	}
+}
+
+// Generate retorna os lembretes dos tipos ativados nas preferências. A data de
+// referência é a de hoje no fuso do usuário
+func (g *Generator) Generate(ctx context.Context, prefs *models.NotificationPreferences) ([]models.Notification, error) {
+	today := planning.Date(g.clock.Now().In(Location(prefs)))
+	var notifications []models.Notification
+
+	if slices.Contains(prefs.Kinds, models.NotificationTrailToday) {
+		trails, err := g.repo.TrailsScheduledOn(ctx, today)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar etapas de hoje: %w", err)
+		}
+		for _, trail := range trails {
+			notifications = append(notifications, models.Notification{
+				Kind:     models.NotificationTrailToday,
+				Title:    "Estudo de hoje: " + trail.Topic,
+				Body:     fmt.Sprintf("Etapas: %s\nAtividades pendentes: %d", strings.Join(trail.Steps, "; "), trail.PendingActivities),
+				DedupKey: fmt.Sprintf("%s:%d:%s", models.NotificationTrailToday, trail.TrailID, today.Format("2006-01-02")),
+			})
+		}
+	}
+
+	if slices.Contains(prefs.Kinds, models.NotificationKeyResultDueSoon) {
+		keyResults, err := g.repo.KeyResultsDueOn(ctx, today.AddDate(0, 0, g.dueSoonDays))
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar Key Results próximos do prazo: %w", err)
+		}
+		for _, kr := range keyResults {
+			notifications = append(notifications, models.Notification{
+				Kind:     models.NotificationKeyResultDueSoon,
+				Title:    fmt.Sprintf("Key Result vence em %d dia(s): %s", g.dueSoonDays, kr.Title),
+				Body:     fmt.Sprintf("OKR: %s\nData esperada: %s", kr.Objective, kr.DueDate.Format(displayDateFormat)),
+				DedupKey: fmt.Sprintf("%s:%d:%s", models.NotificationKeyResultDueSoon, kr.ID, kr.DueDate.Format("2006-01-02")),
+			})
+		}
+	}
+
+	if slices.Contains(prefs.Kinds, models.NotificationOverdue) {
+		keyResults, err := g.repo.OverdueKeyResults(ctx, today)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar Key Results atrasados: %w", err)
+		}
+		for _, kr := range keyResults {
+			notifications = append(notifications, models.Notification{
+				Kind:     models.NotificationOverdue,
+				Title:    "Key Result atrasado: " + kr.Title,
+				Body:     fmt.Sprintf("OKR: %s\nData esperada: %s", kr.Objective, kr.DueDate.Format(displayDateFormat)),
+				DedupKey: fmt.Sprintf("%s:key_result:%d:%s", models.NotificationOverdue, kr.ID, kr.DueDate.Format("2006-01-02")),
+			})
+		}
+
+		trails, err := g.repo.OverdueTrails(ctx, today)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar trilhas atrasadas: %w", err)
+		}
+		for _, trail := range trails {
+			notifications = append(notifications, models.Notification{
+				Kind:  models.NotificationOverdue,
+				Title: "Trilha atrasada: " + trail.Topic,
+				Body: fmt.Sprintf("%d atividade(s) pendente(s) desde %s. Replaneje a trilha para redistribuir as etapas restantes.",
+					trail.PendingActivities, trail.FirstDate.Format(displayDateFormat)),
+				DedupKey: fmt.Sprintf("%s:trail:%d:%s", models.NotificationOverdue, trail.TrailID, trail.FirstDate.Format("2006-01-02")),
+			})
+		}
+	}
+
+	if slices.Contains(prefs.Kinds, models.NotificationWeeklyCheckIn) && int(today.Weekday()) == prefs.WeeklyCheckInWeekday {
+		stats, err := g.okrRepo.Stats(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar estatísticas: %w", err)
+		}
+		year, week := today.ISOWeek()
+		notifications = append(notifications, models.Notification{
+			Kind:  models.NotificationWeeklyCheckIn,
+			Title: "Check-in semanal",
+			Body: fmt.Sprintf("OKRs ativos: %d\nKey Results concluídos: %d de %d (%.0f%%)\nReserve alguns minutos para revisar o progresso e ajustar os prazos da semana.",
+				stats.ActiveOKRs, stats.CompletedKeyResults, stats.TotalKeyResults, stats.CompletionRate()*100),
+			DedupKey: fmt.Sprintf("%s:%d-W%02d", models.NotificationWeeklyCheckIn, year, week),
+		})
+	}
+
+	return notifications, nil
+}
+
+// ParseClock converte um horário HH:MM em minutos desde a meia-noite
+func ParseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("horário inválido %q (use HH:MM)", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// LoadTimezone valida um nome de fuso horário IANA (ex.: America/Sao_Paulo)
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return nil, fmt.Errorf("fuso horário vazio")
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("fuso horário inválido %q", name)
+	}
+	return loc, nil
+}
+
+// Location retorna o fuso das preferências. Um fuso ausente ou inválido (gravado antes
+// da validação) cai no padrão DefaultNotificationTimezone
+func Location(prefs *models.NotificationPreferences) *time.Location {
+	if loc, err := LoadTimezone(prefs.Timezone); err == nil {
+		return loc
+	}
+	loc, _ := LoadTimezone(models.DefaultNotificationTimezone)
+	return loc
+}
+
+// InQuietHours indica se t, convertido para o fuso das preferências, está dentro do
+// horário de silêncio. O intervalo inclui o início e exclui o fim, podendo cruzar a
+// meia-noite
+func InQuietHours(prefs *models.NotificationPreferences, t time.Time) bool {
+	if prefs.QuietHoursStart == nil || prefs.QuietHoursEnd == nil {
+		return false
+	}
+	start, err := ParseClock(*prefs.QuietHoursStart)
+	if err != nil {
+		return false
+	}
+	end, err := ParseClock(*prefs.QuietHoursEnd)
+	if err != nil {
+		return false
+	}
+
+	t = t.In(Location(prefs))
+	minute := t.Hour()*60 + t.Minute()
+	switch {
+	case start == end:
+		return false
+	case start < end:
+		return minute >= start && minute < end
+	default:
+		return minute >= start || minute < end
+	}
+}
@@ -0,0 +1,69 @@
+package notifications
+
+import (
+	"testing"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/models"
+)
+
+func TestInQuietHours(t *testing.T) {
+	start, end := "22:00", "07:00"
+	saoPaulo := &models.NotificationPreferences{QuietHoursStart: &start, QuietHoursEnd: &end, Timezone: "America/Sao_Paulo"}
+	utc := &models.NotificationPreferences{QuietHoursStart: &start, QuietHoursEnd: &end, Timezone: "UTC"}
+	noTimezone := &models.NotificationPreferences{QuietHoursStart: &start, QuietHoursEnd: &end}
+
+	tests := []struct {
+		name  string
+		prefs *models.NotificationPreferences
+		at    string // instante em UTC
+		want  bool
+	}{
+		// 19:00 em UTC são 16:00 em São Paulo (UTC-3)
+		{"19h UTC em São Paulo", saoPaulo, "2026-03-02T19:00:00Z", false},
+		{"19h UTC em UTC", utc, "2026-03-02T19:00:00Z", false},
+		{"01h UTC em São Paulo (22h local)", saoPaulo, "2026-03-03T01:00:00Z", true},
+		{"00h59 UTC em São Paulo (21h59 local)", saoPaulo, "2026-03-03T00:59:00Z", false},
+		{"09h59 UTC em São Paulo (06h59 local)", saoPaulo, "2026-03-03T09:59:00Z", true},
+		{"10h UTC em São Paulo (07h local)", saoPaulo, "2026-03-03T10:00:00Z", false},
+		{"08h UTC em UTC", utc, "2026-03-03T08:00:00Z", false},
+		{"08h UTC em São Paulo (05h local)", saoPaulo, "2026-03-03T08:00:00Z", true},
+		{"sem fuso usa o padrão", noTimezone, "2026-03-03T08:00:00Z", true},
+		{"sem horário de silêncio", &models.NotificationPreferences{Timezone: "UTC"}, "2026-03-03T23:00:00Z", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at, err := time.Parse(time.RFC3339, tt.at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := InQuietHours(tt.prefs, at); got != tt.want {
+				t.Errorf("InQuietHours(%s) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocation(t *testing.T) {
+	tests := []struct {
+		timezone string
+		want     string
+	}{
+		{"Europe/Lisbon", "Europe/Lisbon"},
+		{"UTC", "UTC"},
+		{"", models.DefaultNotificationTimezone},
+		{"Marte/Olympus", models.DefaultNotificationTimezone},
+	}
+	for _, tt := range tests {
+		if got := Location(&models.NotificationPreferences{Timezone: tt.timezone}).String(); got != tt.want {
+			t.Errorf("Location(%q) = %s, want %s", tt.timezone, got, tt.want)
+		}
+	}
+
+	for _, name := range []string{"", "Marte/Olympus", "GMT-3:00"} {
+		if _, err := LoadTimezone(name); err == nil {
+			t.Errorf("LoadTimezone(%q): want erro", name)
+		}
+	}
+}
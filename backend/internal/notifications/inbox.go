@@ -0,0 +1,25 @@
+package notifications
+
+import (
+	"context"
+
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/conquista-ai/conquista-ai/internal/repositories"
+)
+
+// InboxNotifier grava o lembrete na caixa de entrada do aplicativo
+type InboxNotifier struct {
+	repo *repositories.NotificationRepository
+}
+
+func NewInboxNotifier(repo *repositories.NotificationRepository) *InboxNotifier {
+	return &InboxNotifier{repo: repo}
+}
+
+func (n *InboxNotifier) Channel() string {
+	return models.ChannelInbox
+}
+
+func (n *InboxNotifier) Deliver(ctx context.Context, notification models.Notification, _ *models.NotificationPreferences) error {
+	return n.repo.CreateInboxItem(ctx, &notification)
+}
@@ -0,0 +1,16 @@
+// Package notifications gera lembretes a partir do planejamento (atividades do dia,
+// prazos próximos, atrasos e check-in semanal) e os entrega pelos canais ativados
+// nas preferências do usuário: caixa de entrada, e-mail e webhook.
+package notifications
+
+import (
+	"context"
+
+	"github.com/conquista-ai/conquista-ai/internal/models"
+)
+
+// Notifier entrega um lembrete por um canal. O destino (e-mail, URL) vem das preferências
+type Notifier interface {
+	Channel() string
+	Deliver(ctx context.Context, n models.Notification, prefs *models.NotificationPreferences) error
+}
@@ -0,0 +1,167 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/jobs"
+	"github.com/conquista-ai/conquista-ai/internal/logging"
+	"github.com/conquista-ai/conquista-ai/internal/metrics"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/conquista-ai/conquista-ai/internal/planning"
+	"github.com/conquista-ai/conquista-ai/internal/repositories"
+)
+
+const (
+	// Entregas processadas por execução do job
+	dispatchBatchSize = 100
+	// Backoff das entregas que falharam (veja jobs.Backoff)
+	retryBaseDelay = time.Minute
+	retryMaxDelay  = time.Hour
+)
+
+// Service enfileira os lembretes gerados e os entrega pelos canais registrados
+type Service struct {
+	repo        *repositories.NotificationRepository
+	generator   *Generator
+	notifiers   map[string]Notifier
+	clock       planning.Clock
+	maxAttempts int
+}
+
+func NewService(repo *repositories.NotificationRepository, generator *Generator, clock planning.Clock, maxAttempts int, notifiers ...Notifier) *Service {
+	registry := make(map[string]Notifier, len(notifiers))
+	for _, notifier := range notifiers {
+		registry[notifier.Channel()] = notifier
+	}
+	return &Service{
+		repo:        repo,
+		generator:   generator,
+		notifiers:   registry,
+		clock:       clock,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Run gera os lembretes do momento, enfileira um por canal ativado e entrega os
+// pendentes. Executado periodicamente pelo job de notificações
+func (s *Service) Run(ctx context.Context) error {
+	prefs, err := s.repo.GetPreferences(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar preferências de notificação: %w", err)
+	}
+
+	notifications, err := s.generator.Generate(ctx, prefs)
+	if err != nil {
+		return err
+	}
+
+	channels := prefs.Channels()
+	now := s.clock.Now()
+	created := 0
+	for _, n := range notifications {
+		count, err := s.repo.Enqueue(ctx, n, channels, now)
+		if err != nil {
+			return fmt.Errorf("erro ao enfileirar lembrete: %w", err)
+		}
+		created += count
+	}
+	if created > 0 {
+		logging.FromContext(ctx).Info("lembretes enfileirados", "deliveries", created)
+	}
+
+	return s.dispatch(ctx, prefs)
+}
+
+// dispatch entrega as pendências. No horário de silêncio apenas a caixa de entrada
+// é atendida; as demais entregas continuam na fila até o horário terminar
+func (s *Service) dispatch(ctx context.Context, prefs *models.NotificationPreferences) error {
+	logger := logging.FromContext(ctx)
+	now := s.clock.Now()
+
+	deliveries, err := s.repo.DueDeliveries(ctx, now, dispatchBatchSize)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar entregas pendentes: %w", err)
+	}
+
+	quiet := InQuietHours(prefs, now)
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if quiet && delivery.Channel != models.ChannelInbox {
+			continue
+		}
+
+		notifier, ok := s.notifiers[delivery.Channel]
+		if !ok || !slices.Contains(prefs.Channels(), delivery.Channel) {
+			// Canal desativado nas preferências ou sem configuração no servidor
+			metrics.NotificationDeliveries.WithLabelValues(delivery.Channel, "failed").Inc()
+			if err := s.repo.MarkFailed(ctx, delivery.ID, "canal desativado ou não configurado", nil); err != nil {
+				return err
+			}
+			continue
+		}
+
+		deliverErr := notifier.Deliver(ctx, delivery.Notification, prefs)
+		if deliverErr == nil {
+			metrics.NotificationDeliveries.WithLabelValues(delivery.Channel, "sent").Inc()
+			if err := s.repo.MarkSent(ctx, delivery.ID); err != nil {
+				return err
+			}
+			continue
+		}
+
+		attempts := delivery.Attempts + 1
+		var nextAttempt *time.Time
+		outcome := "failed"
+		if attempts < s.maxAttempts {
+			next := now.Add(jobs.Backoff(retryBaseDelay, retryMaxDelay, attempts))
+			nextAttempt = &next
+			outcome = "retry"
+		}
+		metrics.NotificationDeliveries.WithLabelValues(delivery.Channel, outcome).Inc()
+		logger.Warn("falha ao entregar lembrete",
+			"channel", delivery.Channel, "delivery_id", delivery.ID, "attempts", attempts, "error", deliverErr)
+
+		if err := s.repo.MarkFailed(ctx, delivery.ID, deliverErr.Error(), nextAttempt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SendTest envia um lembrete de teste imediatamente por cada canal ativado, ignorando
+// o horário de silêncio. Útil para conferir SMTP e webhook
+func (s *Service) SendTest(ctx context.Context) ([]models.NotificationTestResult, error) {
+	prefs, err := s.repo.GetPreferences(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar preferências de notificação: %w", err)
+	}
+
+	notification := models.Notification{
+		Kind:      "test",
+		Title:     "Notificação de teste",
+		Body:      "Se você recebeu esta mensagem, o canal está configurado corretamente.",
+		CreatedAt: s.clock.Now(),
+	}
+
+	results := make([]models.NotificationTestResult, 0)
+	for _, channel := range prefs.Channels() {
+		result := models.NotificationTestResult{Channel: channel}
+		notifier, ok := s.notifiers[channel]
+		if !ok {
+			result.Error = "canal não configurado no servidor"
+		} else if err := notifier.Deliver(ctx, notification, prefs); err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Sent = true
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,123 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/config"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+)
+
+// smtpDialTimeout limita a conexão com o servidor SMTP quando o contexto não tem prazo
+const smtpDialTimeout = 10 * time.Second
+
+// SMTPNotifier envia o lembrete por e-mail para o endereço das preferências. Usa
+// STARTTLS quando o servidor oferece e autenticação PLAIN quando há usuário configurado
+type SMTPNotifier struct {
+	cfg config.SMTPConfig
+}
+
+func NewSMTPNotifier(cfg config.SMTPConfig) *SMTPNotifier {
+	return &SMTPNotifier{cfg: cfg}
+}
+
+func (n *SMTPNotifier) Channel() string {
+	return models.ChannelEmail
+}
+
+func (n *SMTPNotifier) Deliver(ctx context.Context, notification models.Notification, prefs *models.NotificationPreferences) error {
+	if prefs.EmailAddress == "" {
+		return errors.New("nenhum endereço de e-mail configurado nas preferências")
+	}
+	from, err := mail.ParseAddress(n.cfg.From)
+	if err != nil {
+		return fmt.Errorf("remetente inválido: %w", err)
+	}
+	to, err := mail.ParseAddress(prefs.EmailAddress)
+	if err != nil {
+		return fmt.Errorf("destinatário inválido: %w", err)
+	}
+
+	dialer := net.Dialer{Timeout: smtpDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(n.cfg.Host, strconv.Itoa(n.cfg.Port)))
+	if err != nil {
+		return fmt.Errorf("erro ao conectar ao servidor SMTP: %w", err)
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(smtpDialTimeout)
+	}
+	conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, n.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("erro ao iniciar sessão SMTP: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: n.cfg.Host}); err != nil {
+			return fmt.Errorf("erro no STARTTLS: %w", err)
+		}
+	}
+	if n.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, n.cfg.Host)); err != nil {
+			return fmt.Errorf("erro na autenticação SMTP: %w", err)
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("remetente recusado: %w", err)
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return fmt.Errorf("destinatário recusado: %w", err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar envio: %w", err)
+	}
+	if _, err := w.Write(buildMessage(from, to, notification)); err != nil {
+		return fmt.Errorf("erro ao enviar mensagem: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("mensagem recusada: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// buildMessage monta a mensagem em texto puro UTF-8 com linhas terminadas em CRLF
+func buildMessage(from, to *mail.Address, notification models.Notification) []byte {
+	var msg bytes.Buffer
+	header := func(name, value string) {
+		fmt.Fprintf(&msg, "%s: %s\r\n", name, value)
+	}
+
+	header("From", from.String())
+	header("To", to.String())
+	header("Subject", mime.QEncoding.Encode("utf-8", notification.Title))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", fmt.Sprintf("<%d.%s@conquista-ai>", time.Now().UnixNano(), notification.Kind))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=UTF-8")
+	header("Content-Transfer-Encoding", "8bit")
+	msg.WriteString("\r\n")
+
+	body := strings.ReplaceAll(notification.Body, "\r\n", "\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	msg.WriteString("\r\n")
+
+	return msg.Bytes()
+}
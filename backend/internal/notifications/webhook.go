@@ -0,0 +1,76 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// WebhookNotifier envia o lembrete como JSON (POST) para a URL das preferências.
+// Qualquer resposta fora da faixa 2xx é tratada como falha e a entrega é repetida
+type WebhookNotifier struct {
+	httpClient *http.Client
+}
+
+func NewWebhookNotifier(timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{
+		httpClient: &http.Client{
+			Timeout:   timeout,
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
+		},
+	}
+}
+
+func (n *WebhookNotifier) Channel() string {
+	return models.ChannelWebhook
+}
+
+type webhookPayload struct {
+	Kind      string    `json:"kind"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (n *WebhookNotifier) Deliver(ctx context.Context, notification models.Notification, prefs *models.NotificationPreferences) error {
+	if prefs.WebhookURL == "" {
+		return errors.New("nenhuma URL de webhook configurada nas preferências")
+	}
+
+	payload, err := json.Marshal(webhookPayload{
+		Kind:      notification.Kind,
+		Title:     notification.Title,
+		Body:      notification.Body,
+		CreatedAt: notification.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao serializar lembrete: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, prefs.WebhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("erro ao criar requisição: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "conquista-ai-notifications")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("erro ao chamar webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook respondeu com status %d", resp.StatusCode)
+	}
+	return nil
+}
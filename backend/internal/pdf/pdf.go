@@ -0,0 +1,288 @@
+// Package pdf gera documentos PDF simples (texto, linhas, retângulos e links) usando
+// apenas as fontes padrão Helvetica e Helvetica-Bold, que todo leitor de PDF já possui.
+// Não depende de fontes instaladas nem de bibliotecas externas, o que permite rodar na
+// imagem distroless; em troca, o texto fica restrito ao conjunto WinAnsi (Latin-1),
+// suficiente para português.
+package pdf
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// Font identifica uma das fontes padrão suportadas
+type Font int
+
+const (
+	Helvetica Font = iota
+	HelveticaBold
+)
+
+var fontNames = map[Font]string{
+	Helvetica:     "Helvetica",
+	HelveticaBold: "Helvetica-Bold",
+}
+
+// Dimensões de uma página A4 em pontos (1/72 de polegada)
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Document é um PDF em construção. As coordenadas das páginas seguem o padrão do PDF:
+// origem no canto inferior esquerdo, em pontos
+type Document struct {
+	Title   string
+	Created time.Time
+	pages   []*Page
+}
+
+// Page é uma página do documento
+type Page struct {
+	content bytes.Buffer
+	links   []link
+}
+
+type link struct {
+	x, y, w, h float64
+	uri        string
+}
+
+// AddPage acrescenta uma página A4 ao final do documento
+func (d *Document) AddPage() *Page {
+	p := &Page{}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// Pages retorna as páginas na ordem do documento
+func (d *Document) Pages() []*Page {
+	return d.pages
+}
+
+// Text escreve s a partir de (x, y), sendo y a linha de base do texto
+func (p *Page) Text(x, y float64, font Font, size float64, s string) {
+	fmt.Fprintf(&p.content, "BT /F%d %s Tf %s %s Td (%s) Tj ET\n",
+		font, num(size), num(x), num(y), escapeString(encode(s)))
+}
+
+// SetColor define a cor (RGB de 0 a 1) de preenchimento, usada no texto, e de traço
+func (p *Page) SetColor(r, g, b float64) {
+	fmt.Fprintf(&p.content, "%s %s %s rg %s %s %s RG\n", num(r), num(g), num(b), num(r), num(g), num(b))
+}
+
+// Rect desenha o contorno de um retângulo com canto inferior esquerdo em (x, y)
+func (p *Page) Rect(x, y, w, h, lineWidth float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s %s %s re S\n", num(lineWidth), num(x), num(y), num(w), num(h))
+}
+
+// Line desenha um segmento de (x1, y1) a (x2, y2)
+func (p *Page) Line(x1, y1, x2, y2, lineWidth float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s m %s %s l S\n", num(lineWidth), num(x1), num(y1), num(x2), num(y2))
+}
+
+// Link torna clicável a área indicada, abrindo uri
+func (p *Page) Link(x, y, w, h float64, uri string) {
+	p.links = append(p.links, link{x: x, y: y, w: w, h: h, uri: uri})
+}
+
+// Width retorna a largura de s, em pontos, na fonte e tamanho informados
+func Width(font Font, size float64, s string) float64 {
+	table := widths[font]
+	total := 0
+	for _, c := range encode(s) {
+		if c >= 32 {
+			total += int(table[c-32])
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// Write grava o documento completo em w. As páginas são comprimidas com FlateDecode
+func (d *Document) Write(w io.Writer) error {
+	pw := &pdfWriter{w: bufio.NewWriter(w)}
+	pw.printf("%%PDF-1.4\n%%\xe2\xe3\xcf\xd3\n")
+
+	// Objetos fixos: 1 catálogo, 2 árvore de páginas, 3 informações e as fontes
+	const catalogID, pagesID, infoID, firstFontID = 1, 2, 3, 4
+	nextID := firstFontID + len(fontNames)
+
+	pageIDs := make([]int, len(d.pages))
+	for i, p := range d.pages {
+		pageIDs[i] = nextID
+		// página, conteúdo e uma anotação por link
+		nextID += 2 + len(p.links)
+	}
+
+	pw.object(catalogID, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesID))
+
+	kids := make([]string, len(pageIDs))
+	for i, id := range pageIDs {
+		kids[i] = strconv.Itoa(id) + " 0 R"
+	}
+	pw.object(pagesID, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 %s %s] >>",
+		strings.Join(kids, " "), len(pageIDs), num(PageWidth), num(PageHeight)))
+
+	info := "<< /Producer (Conquista AI)"
+	if d.Title != "" {
+		info += " /Title " + textString(d.Title)
+	}
+	if !d.Created.IsZero() {
+		info += " /CreationDate (D:" + d.Created.UTC().Format("20060102150405") + "Z)"
+	}
+	pw.object(infoID, info+" >>")
+
+	fonts := make([]string, 0, len(fontNames))
+	for font := Helvetica; int(font) < len(fontNames); font++ {
+		pw.object(firstFontID+int(font), fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", fontNames[font]))
+		fonts = append(fonts, fmt.Sprintf("/F%d %d 0 R", font, firstFontID+int(font)))
+	}
+	resources := "<< /Font << " + strings.Join(fonts, " ") + " >> >>"
+
+	for i, p := range d.pages {
+		pageID, contentID := pageIDs[i], pageIDs[i]+1
+
+		annots := ""
+		if len(p.links) > 0 {
+			refs := make([]string, len(p.links))
+			for j := range p.links {
+				refs[j] = strconv.Itoa(contentID+1+j) + " 0 R"
+			}
+			annots = " /Annots [" + strings.Join(refs, " ") + "]"
+		}
+		pw.object(pageID, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /Resources %s /Contents %d 0 R%s >>",
+			pagesID, resources, contentID, annots))
+
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		zw.Write(p.content.Bytes())
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		pw.stream(contentID, "/Filter /FlateDecode", compressed.Bytes())
+
+		for j, l := range p.links {
+			pw.object(contentID+1+j, fmt.Sprintf("<< /Type /Annot /Subtype /Link /Rect [%s %s %s %s] /Border [0 0 0] /A << /S /URI /URI %s >> >>",
+				num(l.x), num(l.y), num(l.x+l.w), num(l.y+l.h), "("+escapeString([]byte(l.uri))+")"))
+		}
+	}
+
+	xref := pw.n
+	pw.printf("xref\n0 %d\n0000000000 65535 f \n", len(pw.offsets)+1)
+	for id := 1; id <= len(pw.offsets); id++ {
+		pw.printf("%010d 00000 n \n", pw.offsets[id])
+	}
+	pw.printf("trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(pw.offsets)+1, catalogID, infoID, xref)
+
+	if pw.err != nil {
+		return pw.err
+	}
+	return pw.w.Flush()
+}
+
+// pdfWriter grava os objetos registrando suas posições para a tabela xref
+type pdfWriter struct {
+	w       *bufio.Writer
+	n       int
+	offsets map[int]int
+	err     error
+}
+
+func (pw *pdfWriter) printf(format string, args ...any) {
+	if pw.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(pw.w, format, args...)
+	pw.n += n
+	pw.err = err
+}
+
+func (pw *pdfWriter) object(id int, body string) {
+	pw.begin(id)
+	pw.printf("%s\nendobj\n", body)
+}
+
+func (pw *pdfWriter) stream(id int, dict string, data []byte) {
+	pw.begin(id)
+	pw.printf("<< %s /Length %d >>\nstream\n", dict, len(data))
+	if pw.err == nil {
+		n, err := pw.w.Write(data)
+		pw.n += n
+		pw.err = err
+	}
+	pw.printf("\nendstream\nendobj\n")
+}
+
+func (pw *pdfWriter) begin(id int) {
+	if pw.offsets == nil {
+		pw.offsets = make(map[int]int)
+	}
+	pw.offsets[id] = pw.n
+	pw.printf("%d 0 obj\n", id)
+}
+
+// winAnsiExtras mapeia os caracteres da faixa 0x80–0x9F da WinAnsiEncoding, que difere
+// do Latin-1
+var winAnsiExtras = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88,
+	'‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91, '’': 0x92, '“': 0x93,
+	'”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B,
+	'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// encode converte o texto para WinAnsiEncoding; caracteres fora dela viram "?"
+func encode(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r == '\t':
+			out = append(out, ' ')
+		case r >= 0x20 && r < 0x7F, r >= 0xA0 && r <= 0xFF:
+			out = append(out, byte(r))
+		case winAnsiExtras[r] != 0:
+			out = append(out, winAnsiExtras[r])
+		case r < 0x20:
+			// caracteres de controle são descartados
+		default:
+			out = append(out, '?')
+		}
+	}
+	return out
+}
+
+// escapeString escapa os delimitadores de uma string literal do PDF
+func escapeString(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		if c == '\\' || c == '(' || c == ')' {
+			sb.WriteByte('\\')
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+// textString codifica s como string de texto do PDF em UTF-16BE, usada nos metadados
+func textString(s string) string {
+	var sb strings.Builder
+	sb.WriteString("<FEFF")
+	for _, u := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&sb, "%04X", u)
+	}
+	sb.WriteString(">")
+	return sb.String()
+}
+
+// num formata coordenadas com até duas casas decimais
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
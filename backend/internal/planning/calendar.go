@@ -0,0 +1,131 @@
+package planning
+
+import (
+	"math"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/models"
+)
+
+// maxCalendarSearchDays limita a busca por dias de estudo quando a disponibilidade
+// é muito restrita (ex.: todos os dias da semana bloqueados por um longo período)
+const maxCalendarSearchDays = 5 * 366
+
+// Calendar indica em quais datas o usuário estuda, segundo os dias da semana
+// escolhidos, feriados e bloqueios, e quantos minutos estuda em cada uma
+type Calendar struct {
+	weekdays      [7]bool
+	daysOff       []models.StudyDayOff
+	minutesPerDay int
+}
+
+// NewCalendar monta o calendário a partir das configurações de estudo
+func NewCalendar(settings *models.StudySettings) Calendar {
+	var cal Calendar
+	for _, weekday := range settings.StudyWeekdays {
+		if weekday >= 0 && weekday < len(cal.weekdays) {
+			cal.weekdays[weekday] = true
+		}
+	}
+	cal.daysOff = settings.DaysOff
+	cal.minutesPerDay = int(math.Round(settings.HoursPerDay * 60))
+	return cal
+}
+
+// Step é uma etapa da trilha a agendar: o dia relativo ("dia 1", "dia 2"...) e os
+// minutos estimados das suas atividades, nil quando alguma não tem estimativa
+type Step struct {
+	Day     int
+	Minutes *int
+}
+
+// IsStudyDay indica se há estudo na data de t
+func (c Calendar) IsStudyDay(t time.Time) bool {
+	if !c.weekdays[t.Weekday()] {
+		return false
+	}
+	// As datas de feriados e bloqueios vêm de colunas DATE (em UTC), então a comparação
+	// é feita pela data do calendário, no fuso de t
+	day := Date(t)
+	for _, off := range c.daysOff {
+		if !day.Before(dateIn(off.StartDate, t.Location())) && !day.After(dateIn(off.EndDate, t.Location())) {
+			return false
+		}
+	}
+	return true
+}
+
+// StudyDates retorna os n primeiros dias de estudo a partir de from (inclusive).
+// Retorna menos datas se não houver dias de estudo suficientes no horizonte de busca
+func (c Calendar) StudyDates(from time.Time, n int) []time.Time {
+	dates := make([]time.Time, 0, max(n, 0))
+	day := Date(from)
+	for i := 0; len(dates) < n && i < maxCalendarSearchDays; i++ {
+		if c.IsStudyDay(day) {
+			dates = append(dates, day)
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return dates
+}
+
+// ScheduleSteps converte os dias relativos das etapas em datas de estudo a partir de
+// start. Dias consecutivos cuja carga somada cabe nas horas de estudo de um dia dividem
+// a mesma data; um dia que sozinho excede esse limite ocupa uma data inteira, assim
+// como os dias sem estimativa. Lacunas na numeração são preservadas como datas livres
+// e dias menores que 1 são tratados como o dia 1. Retorna nil para as etapas que não
+// couberem no horizonte de busca
+func (c Calendar) ScheduleSteps(start time.Time, steps []Step) []*time.Time {
+	// Carga de cada dia relativo; etapas do mesmo dia somam suas estimativas
+	last := 1
+	loads := make(map[int]int)
+	for _, step := range steps {
+		day := max(step.Day, 1)
+		last = max(last, day)
+		if step.Minutes == nil || loads[day] < 0 {
+			loads[day] = -1
+			continue
+		}
+		loads[day] += *step.Minutes
+	}
+
+	// slots[d] é o índice da data de estudo do dia relativo d
+	slots := make([]int, last+1)
+	slot, used := 0, 0
+	for day := 1; day <= last; day++ {
+		load, ok := loads[day]
+		if !ok || load < 0 || c.minutesPerDay <= 0 {
+			// Lacunas e dias sem estimativa ocupam a data inteira
+			load = max(c.minutesPerDay, 1)
+		}
+		if day > 1 && used+load > c.minutesPerDay {
+			slot++
+			used = 0
+		}
+		used += load
+		slots[day] = slot
+	}
+	studyDates := c.StudyDates(start, slot+1)
+
+	scheduled := make([]*time.Time, len(steps))
+	for i, step := range steps {
+		index := slots[max(step.Day, 1)]
+		if index < len(studyDates) {
+			date := studyDates[index]
+			scheduled[i] = &date
+		}
+	}
+	return scheduled
+}
+
+// Date trunca t para a meia-noite do mesmo dia, no mesmo fuso
+func Date(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// dateIn retorna a meia-noite, no fuso loc, da data de calendário de t
+func dateIn(t time.Time, loc *time.Location) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
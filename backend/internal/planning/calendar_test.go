@@ -0,0 +1,120 @@
+package planning
+
+import (
+	"testing"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/models"
+)
+
+func TestCalendarScheduleSteps(t *testing.T) {
+	// 2 de março de 2026 é uma segunda-feira
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	day := func(d int) string { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC).Format("2006-01-02") }
+	minutes := func(m int) *int { return &m }
+	weekdays := []int{1, 2, 3, 4, 5}
+
+	tests := []struct {
+		name     string
+		hours    float64
+		weekdays []int
+		daysOff  []models.StudyDayOff
+		steps    []Step
+		want     []string
+	}{
+		{
+			name:  "sem horas configuradas, uma data por dia",
+			steps: []Step{{Day: 1, Minutes: minutes(10)}, {Day: 2, Minutes: minutes(10)}, {Day: 3, Minutes: minutes(10)}},
+			want:  []string{day(2), day(3), day(4)},
+		},
+		{
+			name:  "dias curtos dividem a data",
+			hours: 2,
+			steps: []Step{{Day: 1, Minutes: minutes(30)}, {Day: 2, Minutes: minutes(60)}, {Day: 3, Minutes: minutes(60)}},
+			want:  []string{day(2), day(2), day(3)},
+		},
+		{
+			name:  "carga exata das horas do dia",
+			hours: 1.5,
+			steps: []Step{{Day: 1, Minutes: minutes(45)}, {Day: 2, Minutes: minutes(45)}, {Day: 3, Minutes: minutes(45)}},
+			want:  []string{day(2), day(2), day(3)},
+		},
+		{
+			name:  "dia acima do limite ocupa a data inteira",
+			hours: 2,
+			steps: []Step{{Day: 1, Minutes: minutes(180)}, {Day: 2, Minutes: minutes(30)}},
+			want:  []string{day(2), day(3)},
+		},
+		{
+			name:  "dia sem estimativa ocupa a data inteira",
+			hours: 2,
+			steps: []Step{{Day: 1, Minutes: minutes(30)}, {Day: 2}, {Day: 3, Minutes: minutes(30)}},
+			want:  []string{day(2), day(3), day(4)},
+		},
+		{
+			name:  "etapas do mesmo dia somam a carga",
+			hours: 2,
+			steps: []Step{{Day: 1, Minutes: minutes(60)}, {Day: 1, Minutes: minutes(60)}, {Day: 2, Minutes: minutes(30)}},
+			want:  []string{day(2), day(2), day(3)},
+		},
+		{
+			name:  "lacunas na numeração viram datas livres",
+			hours: 2,
+			steps: []Step{{Day: 1, Minutes: minutes(30)}, {Day: 3, Minutes: minutes(30)}},
+			want:  []string{day(2), day(4)},
+		},
+		{
+			name:  "dia menor que 1 conta como o dia 1",
+			hours: 2,
+			steps: []Step{{Day: 0, Minutes: minutes(30)}, {Day: 2, Minutes: minutes(30)}},
+			want:  []string{day(2), day(2)},
+		},
+		{
+			name:  "fim de semana é pulado",
+			hours: 1,
+			steps: []Step{{Day: 1}, {Day: 2}, {Day: 3}, {Day: 4}, {Day: 5}, {Day: 6}},
+			want:  []string{day(2), day(3), day(4), day(5), day(6), day(9)},
+		},
+		{
+			name:  "feriado é pulado",
+			hours: 1,
+			daysOff: []models.StudyDayOff{{
+				Kind:      models.DayOffHoliday,
+				StartDate: time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC),
+				EndDate:   time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC),
+			}},
+			steps: []Step{{Day: 1}, {Day: 2}},
+			want:  []string{day(2), day(4)},
+		},
+		{
+			name:     "sem dias de estudo",
+			hours:    2,
+			weekdays: []int{},
+			steps:    []Step{{Day: 1, Minutes: minutes(30)}},
+			want:     []string{""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := &models.StudySettings{StudyWeekdays: weekdays, HoursPerDay: tt.hours, DaysOff: tt.daysOff}
+			if tt.weekdays != nil {
+				settings.StudyWeekdays = tt.weekdays
+			}
+
+			got := NewCalendar(settings).ScheduleSteps(start, tt.steps)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ScheduleSteps retornou %d datas, want %d", len(got), len(tt.want))
+			}
+			for i, date := range got {
+				formatted := ""
+				if date != nil {
+					formatted = date.Format("2006-01-02")
+				}
+				if formatted != tt.want[i] {
+					t.Errorf("etapa %d (dia %d) = %q, want %q", i, tt.steps[i].Day, formatted, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package planning
+
+import "time"
+
+// Clock fornece o instante atual. Permite injetar um relógio fixo para que os
+// cálculos de datas sejam determinísticos
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock usa o relógio do sistema
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FixedClock sempre retorna o mesmo instante
+type FixedClock struct {
+	Time time.Time
+}
+
+func (c FixedClock) Now() time.Time {
+	return c.Time
+}
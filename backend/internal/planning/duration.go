@@ -0,0 +1,66 @@
+package planning
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// 1:30 (horas:minutos)
+	clockDurationPattern = regexp.MustCompile(`^\s*(\d{1,2}):(\d{2})\s*$`)
+	// 1h30 (minutos sem unidade logo após as horas)
+	compactDurationPattern = regexp.MustCompile(`(\d+)\s*h\s*(\d{1,2})(?:\s*(?:min|mins|minutos?|m)\b|\s*$|[^\d])`)
+	// 30 min, 2 horas, 1,5h, 2-3 horas (intervalos usam a média). A unidade termina em
+	// qualquer caractere que não seja letra: \b não reconhece acentos e aceitaria "1 mês"
+	unitDurationPattern = regexp.MustCompile(`(\d+(?:[.,]\d+)?)(?:\s*(?:-|a|to)\s*(\d+(?:[.,]\d+)?))?\s*(horas?|hours?|hrs?|h|minutos?|minutes?|mins?|m)(?:[^\p{L}\d]|$)`)
+)
+
+// ParseMinutes interpreta a duração estimada em texto livre de uma atividade (ex.:
+// "30 min", "1h30", "2 horas", "1,5 h", "2-3 hours") e retorna o total em minutos.
+// Durações em dias ou semanas não representam tempo de estudo e não são convertidas
+func ParseMinutes(text string) (int, bool) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "" {
+		return 0, false
+	}
+
+	if m := clockDurationPattern.FindStringSubmatch(text); m != nil {
+		hours, _ := strconv.Atoi(m[1])
+		minutes, _ := strconv.Atoi(m[2])
+		return positive(hours*60 + minutes)
+	}
+
+	if m := compactDurationPattern.FindStringSubmatch(text); m != nil {
+		hours, _ := strconv.Atoi(m[1])
+		minutes, _ := strconv.Atoi(m[2])
+		return positive(hours*60 + minutes)
+	}
+
+	total := 0.0
+	matches := unitDurationPattern.FindAllStringSubmatch(text, -1)
+	for _, m := range matches {
+		value := parseDecimal(m[1])
+		if m[2] != "" {
+			value = (value + parseDecimal(m[2])) / 2
+		}
+		if strings.HasPrefix(m[3], "h") {
+			value *= 60
+		}
+		total += value
+	}
+	if len(matches) == 0 {
+		return 0, false
+	}
+	return positive(int(math.Round(total)))
+}
+
+func parseDecimal(s string) float64 {
+	value, _ := strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+	return value
+}
+
+func positive(minutes int) (int, bool) {
+	return minutes, minutes > 0
+}
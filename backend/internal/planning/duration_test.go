@@ -0,0 +1,52 @@
+package planning
+
+import "testing"
+
+func TestParseMinutes(t *testing.T) {
+	tests := []struct {
+		text    string
+		minutes int
+		ok      bool
+	}{
+		{"30 min", 30, true},
+		{"45 minutos", 45, true},
+		{"90m", 90, true},
+		{"2h", 120, true},
+		{"2 horas", 120, true},
+		{"1h30", 90, true},
+		{"1h30min", 90, true},
+		{"1H30", 90, true},
+		{"1:30", 90, true},
+		{"1,5 h", 90, true},
+		{"1.5h", 90, true},
+		{"1h 15m", 75, true},
+		{"1 hora e 30 minutos", 90, true},
+		{"2-3 horas", 150, true},
+		{"2 a 3 horas", 150, true},
+		{"2-3 hours", 150, true},
+		{"  20 min  ", 20, true},
+
+		// Dias, semanas e meses não são tempo de estudo
+		{"3 dias", 0, false},
+		{"1 dia", 0, false},
+		{"2 semanas", 0, false},
+		{"5 meses", 0, false},
+		{"1 mês", 0, false},
+		{"3 days", 0, false},
+		{"6 months", 0, false},
+
+		{"", 0, false},
+		{"   ", 0, false},
+		{"0 min", 0, false},
+		{"no seu ritmo", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			minutes, ok := ParseMinutes(tt.text)
+			if minutes != tt.minutes || ok != tt.ok {
+				t.Errorf("ParseMinutes(%q) = (%d, %v), want (%d, %v)", tt.text, minutes, ok, tt.minutes, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package planning
+
+import (
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/config"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+)
+
+// Planner aplica a estratégia de distribuição e os limites configurados aos prazos
+// de OKRs, Key Results, roadmaps e trilhas educacionais
+type Planner struct {
+	scheduler Scheduler
+	clock     Clock
+	limits    config.PlanningConfig
+}
+
+func NewPlanner(scheduler Scheduler, clock Clock, limits config.PlanningConfig) *Planner {
+	return &Planner{
+		scheduler: scheduler,
+		clock:     clock,
+		limits:    limits,
+	}
+}
+
+// WithScheduler retorna uma cópia do planner usando outra estratégia
+func (p *Planner) WithScheduler(scheduler Scheduler) *Planner {
+	clone := *p
+	clone.scheduler = scheduler
+	return &clone
+}
+
+// Now retorna o instante atual segundo o relógio do planner
+func (p *Planner) Now() time.Time {
+	return p.clock.Now()
+}
+
+// Scheduler retorna a estratégia em uso
+func (p *Planner) Scheduler() Scheduler {
+	return p.scheduler
+}
+
+// DefaultOKRDeadline é a data de conclusão usada quando o OKR é criado sem uma
+func (p *Planner) DefaultOKRDeadline() time.Time {
+	return p.Now().AddDate(0, p.limits.DefaultOKRDurationMonths, 0)
+}
+
+// DaysUntil retorna os dias inteiros restantes até deadline (negativo se já passou)
+func (p *Planner) DaysUntil(deadline time.Time) int {
+	return int(deadline.Sub(p.Now()).Hours() / 24)
+}
+
+// Allocate distribui totalDays entre as tarefas usando a estratégia configurada
+func (p *Planner) Allocate(totalDays int, weights []float64) []int {
+	return p.scheduler.Allocate(totalDays, weights)
+}
+
+// Share retorna os dias destinados à tarefa index dentre as tarefas com os pesos informados
+func (p *Planner) Share(totalDays int, weights []float64, index int) int {
+	if index < 0 || index >= len(weights) {
+		return 0
+	}
+	return p.Allocate(totalDays, weights)[index]
+}
+
+// DueDates distribui o tempo entre agora e deadline pelas tarefas, executadas em
+// sequência, e retorna a data esperada de conclusão de cada uma. Quando o prazo já
+// passou, todas recebem o próprio deadline
+func (p *Planner) DueDates(deadline time.Time, weights []float64) []time.Time {
+	dates := make([]time.Time, len(weights))
+	daysRemaining := p.DaysUntil(deadline)
+	if daysRemaining <= 0 {
+		for i := range dates {
+			dates[i] = deadline
+		}
+		return dates
+	}
+
+	now := p.Now()
+	accumulatedDays := 0
+	for i, days := range p.Allocate(daysRemaining, weights) {
+		accumulatedDays += days
+		dates[i] = now.AddDate(0, 0, accumulatedDays)
+	}
+	return dates
+}
+
+// ClampTrailDays aplica os limites de dias de uma trilha (padrão: mínimo 3, máximo 30)
+func (p *Planner) ClampTrailDays(days int) int {
+	if days < p.limits.TrailMinDays {
+		return p.limits.TrailMinDays
+	}
+	if days > p.limits.TrailMaxDays {
+		return p.limits.TrailMaxDays
+	}
+	return days
+}
+
+// MinTrailDays retorna o mínimo de dias de uma trilha
+func (p *Planner) MinTrailDays() int {
+	return p.limits.TrailMinDays
+}
+
+// DefaultRoadmapDays retorna os dias usados quando não há prazo para o roadmap
+func (p *Planner) DefaultRoadmapDays() int {
+	return p.limits.DefaultRoadmapDays
+}
+
+// RoadmapItemCount calcula quantos itens o roadmap deve ter para availableDays. Cada
+// item vira uma trilha educacional, com duração mínima que cresce com o prazo
+func (p *Planner) RoadmapItemCount(availableDays int) (itemCount, minDaysPerTrail int) {
+	switch {
+	case availableDays < 14:
+		// Tempo curto: trilhas curtas (3 dias)
+		minDaysPerTrail = 3
+	case availableDays <= 30:
+		// Tempo médio: trilhas médias (5 dias)
+		minDaysPerTrail = 5
+	case availableDays <= 60:
+		// Tempo médio-longo: trilhas mais longas (6 dias)
+		minDaysPerTrail = 6
+	default:
+		// Tempo longo: trilhas extensas (7 dias)
+		minDaysPerTrail = 7
+	}
+
+	// Garantir limites configurados (padrão: mínimo de 3 itens, máximo de 20 itens)
+	itemCount = availableDays / minDaysPerTrail
+	if itemCount < p.limits.RoadmapMinItems {
+		itemCount = p.limits.RoadmapMinItems
+	} else if itemCount > p.limits.RoadmapMaxItems {
+		itemCount = p.limits.RoadmapMaxItems
+	}
+
+	return itemCount, minDaysPerTrail
+}
+
+// KeyResultWeights retorna o peso de cada Key Result segundo sua dificuldade
+func KeyResultWeights(keyResults []models.KeyResult) []float64 {
+	weights := make([]float64, len(keyResults))
+	for i, kr := range keyResults {
+		weights[i] = float64(kr.EffectiveDifficulty())
+	}
+	return weights
+}
@@ -0,0 +1,111 @@
+package planning
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/config"
+)
+
+func TestPlannerDueDates(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	days := func(n int) time.Time { return now.AddDate(0, 0, n) }
+
+	tests := []struct {
+		name     string
+		strategy string
+		deadline time.Time
+		weights  []float64
+		want     []time.Time
+	}{
+		{
+			name:     "dias divididos igualmente",
+			strategy: StrategyEven,
+			deadline: days(10),
+			weights:  []float64{1, 1, 1},
+			want:     []time.Time{days(4), days(7), days(10)},
+		},
+		{
+			name:     "proporcional à dificuldade",
+			strategy: StrategyWeighted,
+			deadline: days(10),
+			weights:  []float64{1, 1, 2},
+			want:     []time.Time{days(3), days(5), days(10)},
+		},
+		{
+			name:     "mais Key Results que dias",
+			strategy: StrategyEven,
+			deadline: days(2),
+			weights:  []float64{1, 1, 1, 1},
+			want:     []time.Time{days(1), days(2), days(2), days(2)},
+		},
+		{
+			name:     "um Key Result",
+			strategy: StrategyFrontLoaded,
+			deadline: days(10),
+			weights:  []float64{2},
+			want:     []time.Time{days(10)},
+		},
+		{
+			name:     "prazo hoje",
+			strategy: StrategyEven,
+			deadline: now.Add(6 * time.Hour),
+			weights:  []float64{1, 1},
+			want:     []time.Time{now.Add(6 * time.Hour), now.Add(6 * time.Hour)},
+		},
+		{
+			name:     "prazo no passado",
+			strategy: StrategyBackLoaded,
+			deadline: days(-5),
+			weights:  []float64{1, 1},
+			want:     []time.Time{days(-5), days(-5)},
+		},
+		{
+			name:     "sem Key Results",
+			strategy: StrategyEven,
+			deadline: days(10),
+			weights:  nil,
+			want:     []time.Time{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheduler, err := NewScheduler(tt.strategy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			planner := NewPlanner(scheduler, FixedClock{Time: now}, config.PlanningConfig{})
+
+			got := planner.DueDates(tt.deadline, tt.weights)
+			if !slices.EqualFunc(got, tt.want, time.Time.Equal) {
+				t.Errorf("DueDates(%s, %v) = %v, want %v", tt.deadline, tt.weights, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlannerDaysUntil(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	planner := NewPlanner(EvenScheduler{}, FixedClock{Time: now}, config.PlanningConfig{DefaultOKRDurationMonths: 3})
+
+	tests := []struct {
+		deadline time.Time
+		want     int
+	}{
+		{now.AddDate(0, 0, 10), 10},
+		{now.Add(36 * time.Hour), 1},
+		{now.Add(6 * time.Hour), 0},
+		{now.AddDate(0, 0, -3), -3},
+	}
+	for _, tt := range tests {
+		if got := planner.DaysUntil(tt.deadline); got != tt.want {
+			t.Errorf("DaysUntil(%s) = %d, want %d", tt.deadline, got, tt.want)
+		}
+	}
+
+	if got, want := planner.DefaultOKRDeadline(), now.AddDate(0, 3, 0); !got.Equal(want) {
+		t.Errorf("DefaultOKRDeadline() = %s, want %s", got, want)
+	}
+}
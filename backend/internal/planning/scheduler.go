@@ -0,0 +1,145 @@
+// Package planning concentra a distribuição de prazos: quantos dias cada Key Result,
+// item de roadmap ou trilha educacional recebe e as datas esperadas de conclusão.
+package planning
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Nomes das estratégias aceitas em planning.strategy
+const (
+	StrategyEven        = "even"
+	StrategyWeighted    = "weighted"
+	StrategyFrontLoaded = "front_loaded"
+	StrategyBackLoaded  = "back_loaded"
+)
+
+// Strategies lista as estratégias disponíveis
+var Strategies = []string{StrategyEven, StrategyWeighted, StrategyFrontLoaded, StrategyBackLoaded}
+
+// Scheduler distribui um orçamento de dias entre tarefas executadas em sequência.
+// weights traz um peso por tarefa (ex.: dificuldade do Key Result); cada estratégia
+// decide como usá-lo. O resultado tem um valor por tarefa e soma exatamente totalDays
+type Scheduler interface {
+	Name() string
+	Allocate(totalDays int, weights []float64) []int
+}
+
+// NewScheduler retorna a estratégia pelo nome
+func NewScheduler(name string) (Scheduler, error) {
+	switch name {
+	case StrategyEven:
+		return EvenScheduler{}, nil
+	case StrategyWeighted:
+		return WeightedScheduler{}, nil
+	case StrategyFrontLoaded:
+		return FrontLoadedScheduler{}, nil
+	case StrategyBackLoaded:
+		return BackLoadedScheduler{}, nil
+	default:
+		return nil, fmt.Errorf("estratégia de planejamento desconhecida: %s", name)
+	}
+}
+
+// EvenScheduler divide os dias igualmente, ignorando os pesos. A sobra da divisão vai
+// para as primeiras tarefas
+type EvenScheduler struct{}
+
+func (EvenScheduler) Name() string { return StrategyEven }
+
+func (EvenScheduler) Allocate(totalDays int, weights []float64) []int {
+	return distribute(totalDays, Uniform(len(weights)))
+}
+
+// WeightedScheduler divide os dias proporcionalmente aos pesos (dificuldade)
+type WeightedScheduler struct{}
+
+func (WeightedScheduler) Name() string { return StrategyWeighted }
+
+func (WeightedScheduler) Allocate(totalDays int, weights []float64) []int {
+	return distribute(totalDays, weights)
+}
+
+// FrontLoadedScheduler dá mais tempo às primeiras tarefas, decrescendo linearmente
+// (a primeira de n tarefas recebe peso n, a última peso 1)
+type FrontLoadedScheduler struct{}
+
+func (FrontLoadedScheduler) Name() string { return StrategyFrontLoaded }
+
+func (FrontLoadedScheduler) Allocate(totalDays int, weights []float64) []int {
+	n := len(weights)
+	positional := make([]float64, n)
+	for i := range positional {
+		positional[i] = float64(n - i)
+	}
+	return distribute(totalDays, positional)
+}
+
+// BackLoadedScheduler dá mais tempo às últimas tarefas, crescendo linearmente
+// (a primeira tarefa recebe peso 1, a última peso n)
+type BackLoadedScheduler struct{}
+
+func (BackLoadedScheduler) Name() string { return StrategyBackLoaded }
+
+func (BackLoadedScheduler) Allocate(totalDays int, weights []float64) []int {
+	positional := make([]float64, len(weights))
+	for i := range positional {
+		positional[i] = float64(i + 1)
+	}
+	return distribute(totalDays, positional)
+}
+
+// Uniform retorna n pesos iguais
+func Uniform(n int) []float64 {
+	weights := make([]float64, n)
+	for i := range weights {
+		weights[i] = 1
+	}
+	return weights
+}
+
+// distribute reparte totalDays proporcionalmente aos pesos pelo método dos maiores
+// restos, garantindo que a soma seja exata. Pesos não positivos contam como 1 e, em
+// caso de empate, a sobra vai para as primeiras tarefas
+func distribute(totalDays int, weights []float64) []int {
+	n := len(weights)
+	days := make([]int, n)
+	if n == 0 || totalDays <= 0 {
+		return days
+	}
+
+	sum := 0.0
+	normalized := make([]float64, n)
+	for i, w := range weights {
+		if w <= 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			w = 1
+		}
+		normalized[i] = w
+		sum += w
+	}
+
+	type remainder struct {
+		index int
+		frac  float64
+	}
+	remainders := make([]remainder, n)
+	assigned := 0
+	for i, w := range normalized {
+		exact := float64(totalDays) * w / sum
+		days[i] = int(math.Floor(exact))
+		assigned += days[i]
+		remainders[i] = remainder{index: i, frac: exact - float64(days[i])}
+	}
+
+	sort.SliceStable(remainders, func(a, b int) bool {
+		return remainders[a].frac > remainders[b].frac+1e-9
+	})
+	for i := 0; assigned < totalDays; i++ {
+		days[remainders[i%n].index]++
+		assigned++
+	}
+
+	return days
+}
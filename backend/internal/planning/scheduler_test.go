@@ -0,0 +1,105 @@
+package planning
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/conquista-ai/conquista-ai/internal/config"
+)
+
+func TestDistribute(t *testing.T) {
+	tests := []struct {
+		name      string
+		totalDays int
+		weights   []float64
+		want      []int
+	}{
+		{"divisão exata", 10, []float64{1, 2, 3, 4}, []int{1, 2, 3, 4}},
+		{"sobra para as primeiras em empate", 10, []float64{1, 1, 1}, []int{4, 3, 3}},
+		{"sobra para o maior resto", 7, []float64{1, 2}, []int{2, 5}},
+		{"restos empatados em 0,5", 10, []float64{1, 3}, []int{3, 7}},
+		{"mais tarefas que dias", 2, []float64{1, 1, 1, 1, 1}, []int{1, 1, 0, 0, 0}},
+		{"uma tarefa", 30, []float64{2}, []int{30}},
+		{"zero dias", 0, []float64{1, 1}, []int{0, 0}},
+		{"dias negativos", -5, []float64{1, 1}, []int{0, 0}},
+		{"sem tarefas", 5, nil, []int{}},
+		{"pesos inválidos contam como 1", 10, []float64{0, -1, math.NaN()}, []int{4, 3, 3}},
+		{"peso infinito conta como 1", 4, []float64{math.Inf(1), 1}, []int{2, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := distribute(tt.totalDays, tt.weights)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("distribute(%d, %v) = %v, want %v", tt.totalDays, tt.weights, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrategiesMatchConfig(t *testing.T) {
+	if !slices.Equal(Strategies, config.PlanningStrategies) {
+		t.Errorf("Strategies = %v, want igual a config.PlanningStrategies = %v", Strategies, config.PlanningStrategies)
+	}
+	for _, name := range Strategies {
+		scheduler, err := NewScheduler(name)
+		if err != nil {
+			t.Errorf("NewScheduler(%q): %v", name, err)
+			continue
+		}
+		if scheduler.Name() != name {
+			t.Errorf("NewScheduler(%q).Name() = %q", name, scheduler.Name())
+		}
+	}
+}
+
+func TestSchedulers(t *testing.T) {
+	tests := []struct {
+		strategy  string
+		totalDays int
+		weights   []float64
+		want      []int
+	}{
+		{StrategyEven, 10, []float64{5, 1, 1}, []int{4, 3, 3}},
+		{StrategyEven, 2, []float64{1, 1, 1, 1}, []int{1, 1, 0, 0}},
+		{StrategyEven, 30, []float64{3}, []int{30}},
+		{StrategyEven, 0, []float64{1, 2, 3}, []int{0, 0, 0}},
+
+		{StrategyWeighted, 10, []float64{1, 1, 2}, []int{3, 2, 5}},
+		{StrategyWeighted, 1, []float64{1, 3}, []int{0, 1}},
+		{StrategyWeighted, 30, []float64{3}, []int{30}},
+		{StrategyWeighted, 0, []float64{1, 2, 3}, []int{0, 0, 0}},
+
+		{StrategyFrontLoaded, 10, []float64{1, 1, 1}, []int{5, 3, 2}},
+		{StrategyFrontLoaded, 2, []float64{1, 1, 1, 1}, []int{1, 1, 0, 0}},
+		{StrategyFrontLoaded, 30, []float64{3}, []int{30}},
+		{StrategyFrontLoaded, 0, []float64{1, 2, 3}, []int{0, 0, 0}},
+
+		{StrategyBackLoaded, 10, []float64{1, 1, 1}, []int{2, 3, 5}},
+		{StrategyBackLoaded, 2, []float64{1, 1, 1, 1}, []int{0, 0, 1, 1}},
+		{StrategyBackLoaded, 30, []float64{3}, []int{30}},
+		{StrategyBackLoaded, 0, []float64{1, 2, 3}, []int{0, 0, 0}},
+	}
+
+	for _, tt := range tests {
+		scheduler, err := NewScheduler(tt.strategy)
+		if err != nil {
+			t.Fatalf("NewScheduler(%q): %v", tt.strategy, err)
+		}
+		if scheduler.Name() != tt.strategy {
+			t.Errorf("Name() = %q, want %q", scheduler.Name(), tt.strategy)
+		}
+
+		got := scheduler.Allocate(tt.totalDays, tt.weights)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s.Allocate(%d, %v) = %v, want %v", tt.strategy, tt.totalDays, tt.weights, got, tt.want)
+		}
+	}
+}
+
+func TestNewSchedulerUnknown(t *testing.T) {
+	if _, err := NewScheduler("random"); err == nil {
+		t.Error("NewScheduler(\"random\") deveria falhar")
+	}
+}
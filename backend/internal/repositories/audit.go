@@ -0,0 +1,168 @@
+package repositories
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/actor"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/conquista-ai/conquista-ai/internal/requestid"
+)
+
+// auditTables liga cada entidade auditada à sua tabela
+var auditTables = map[string]string{
+	models.AuditEntityCategory:            "categories",
+	models.AuditEntityOKR:                 "okrs",
+	models.AuditEntityKeyResult:           "key_results",
+	models.AuditEntityRoadmapItem:         "roadmap_items",
+	models.AuditEntityEducationalResource: "educational_resources",
+	models.AuditEntityTrailActivity:       "educational_trail_activities",
+}
+
+// auditSnapshot retorna a linha da entidade como JSON, travando-a até o fim da transação.
+// A coluna de busca textual e updated_at ficam de fora para não aparecerem como mudança.
+// Retorna nil quando a linha não existe
+func auditSnapshot(ctx context.Context, tx *sql.Tx, entity string, id int64) (json.RawMessage, error) {
+	query := `SELECT to_jsonb(t) - 'search_vector' - 'updated_at' FROM ` + auditTables[entity] + ` t WHERE id = $1 FOR UPDATE`
+
+	var row []byte
+	if err := tx.QueryRowContext(ctx, query, id).Scan(&row); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao ler %s %d para auditoria: %w", entity, id, err)
+	}
+	return row, nil
+}
+
+// auditedRow é o retrato de uma linha antes de uma mudança em lote
+type auditedRow struct {
+	id     int64
+	before json.RawMessage
+}
+
+// auditSnapshots retorna o retrato de cada linha da entidade cujo ID é retornado por
+// query, para mudanças que atingem várias linhas de uma vez
+func auditSnapshots(ctx context.Context, tx *sql.Tx, entity, query string, args ...any) ([]auditedRow, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]auditedRow, 0, len(ids))
+	for _, id := range ids {
+		before, err := auditSnapshot(ctx, tx, entity, id)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, auditedRow{id: id, before: before})
+	}
+	return snapshots, nil
+}
+
+// recordAudits grava a mesma ação para cada linha retratada com auditSnapshots
+func recordAudits(ctx context.Context, tx *sql.Tx, entity, action string, snapshots []auditedRow) error {
+	for _, row := range snapshots {
+		if err := recordAudit(ctx, tx, entity, row.id, action, row.before); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordAudit grava no log de auditoria, usando a transação da mudança, o que mudou na
+// entidade. before é o retrato da linha antes da mudança (nil na criação), obtido com
+// auditSnapshot; o retrato posterior é lido da própria transação. Autor e ID da
+// requisição vêm do contexto. Atualizações que não alteram nenhum campo não são registradas
+func recordAudit(ctx context.Context, tx *sql.Tx, entity string, id int64, action string, before json.RawMessage) error {
+	var after json.RawMessage
+	if action != models.AuditActionDelete {
+		var err error
+		if after, err = auditSnapshot(ctx, tx, entity, id); err != nil {
+			return err
+		}
+	}
+
+	changes, err := auditDiff(before, after)
+	if err != nil {
+		return fmt.Errorf("erro ao comparar %s %d para auditoria: %w", entity, id, err)
+	}
+	if action == models.AuditActionUpdate && len(changes) == 0 {
+		return nil
+	}
+	payload, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	var requestID sql.NullString
+	if id := requestid.FromContext(ctx); id != "" {
+		requestID = sql.NullString{String: id, Valid: true}
+	}
+
+	query := `INSERT INTO audit_events (occurred_at, actor, request_id, entity, entity_id, action, changes)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	if _, err := tx.ExecContext(ctx, query, time.Now(), actor.FromContext(ctx), requestID, entity, id, action, payload); err != nil {
+		return fmt.Errorf("erro ao gravar auditoria de %s %d: %w", entity, id, err)
+	}
+	return nil
+}
+
+// auditDiff compara os dois retratos e retorna os campos cujo valor mudou. Um retrato
+// nil equivale a um objeto com todos os campos nulos: na criação e na exclusão aparecem
+// apenas os campos preenchidos
+func auditDiff(before, after json.RawMessage) (map[string]models.AuditChange, error) {
+	var beforeFields, afterFields map[string]json.RawMessage
+	if before != nil {
+		if err := json.Unmarshal(before, &beforeFields); err != nil {
+			return nil, err
+		}
+	}
+	if after != nil {
+		if err := json.Unmarshal(after, &afterFields); err != nil {
+			return nil, err
+		}
+	}
+
+	changes := make(map[string]models.AuditChange)
+	compare := func(field string) {
+		if _, done := changes[field]; done {
+			return
+		}
+		b, a := jsonOrNull(beforeFields[field]), jsonOrNull(afterFields[field])
+		if !bytes.Equal(b, a) {
+			changes[field] = models.AuditChange{Before: b, After: a}
+		}
+	}
+	for field := range beforeFields {
+		compare(field)
+	}
+	for field := range afterFields {
+		compare(field)
+	}
+	return changes, nil
+}
+
+func jsonOrNull(value json.RawMessage) json.RawMessage {
+	if value == nil {
+		return json.RawMessage("null")
+	}
+	return value
+}
@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/models"
+)
+
+type AuditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// List retorna uma página de eventos de auditoria, do mais recente para o mais antigo
+func (r *AuditRepository) List(ctx context.Context, filter models.AuditEventFilter) (*models.Page[models.AuditEvent], error) {
+	cursor, err := decodeCursor(filter.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	filter.Limit = pageLimit(filter.Limit)
+
+	b := &sqlBuilder{}
+	if filter.Entity != "" {
+		b.where("entity = " + b.arg(filter.Entity))
+	}
+	if filter.EntityID != nil {
+		b.where("entity_id = " + b.arg(*filter.EntityID))
+	}
+	if filter.Action != "" {
+		b.where("action = " + b.arg(filter.Action))
+	}
+
+	page := &models.Page[models.AuditEvent]{Items: make([]models.AuditEvent, 0), Limit: filter.Limit}
+
+	countBuilder := b.clone()
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM audit_events`+countBuilder.whereClause(), countBuilder.args...).Scan(&page.Total); err != nil {
+		return nil, err
+	}
+
+	if cursor != nil {
+		b.where("id < " + b.arg(cursor.ID))
+	}
+
+	query := `SELECT id, occurred_at, actor, COALESCE(request_id, ''), entity, entity_id, action, changes
+	          FROM audit_events` + b.whereClause() + ` ORDER BY id DESC LIMIT ` + b.arg(filter.Limit+1)
+	rows, err := r.db.QueryContext(ctx, query, b.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e models.AuditEvent
+		var changes []byte
+		if err := rows.Scan(&e.ID, &e.OccurredAt, &e.Actor, &e.RequestID, &e.Entity, &e.EntityID, &e.Action, &changes); err != nil {
+			return nil, err
+		}
+		if len(page.Items) == filter.Limit {
+			page.NextCursor = encodeCursor(pageCursor{ID: page.Items[len(page.Items)-1].ID})
+			break
+		}
+		if err := json.Unmarshal(changes, &e.Changes); err != nil {
+			return nil, err
+		}
+		page.Items = append(page.Items, e)
+	}
+
+	return page, rows.Err()
+}
+
+// DeleteBefore remove os eventos registrados antes de cutoff e retorna quantos foram removidos
+func (r *AuditRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM audit_events WHERE occurred_at < $1`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,558 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/models"
+)
+
+// BackupRepository exporta e importa todos os dados de planejamento (categorias, OKRs,
+// Key Results, roadmaps, roadmaps educacionais e trilhas) no formato models.Backup.
+// Itens na lixeira não são exportados
+type BackupRepository struct {
+	db *sql.DB
+}
+
+func NewBackupRepository(db *sql.DB) *BackupRepository {
+	return &BackupRepository{db: db}
+}
+
+// Export lê todos os dados em uma única transação somente leitura, para que o arquivo
+// reflita um mesmo instante. As tabelas são lidas das folhas para a raiz: cada nível é
+// montado com os filhos já completos
+func (r *BackupRepository) Export(ctx context.Context) (*models.Backup, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	backup := &models.Backup{
+		Format:     models.BackupFormat,
+		Version:    models.BackupVersion,
+		Categories: make([]models.BackupCategory, 0),
+		OKRs:       make([]models.BackupOKR, 0),
+	}
+
+	// Roadmaps educacionais
+	resourceChapters, err := chapters(ctx, tx, `SELECT resource_id, chapter_title FROM educational_resource_chapters ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	resources := make(map[int64][]models.BackupEducationalResource)
+	err = eachRow(ctx, tx, `SELECT id, educational_roadmap_id, resource_type, title, description, url, author, duration, COALESCE(completed, FALSE)
+	                        FROM educational_resources ORDER BY id`, func(rows *sql.Rows) error {
+		var res models.BackupEducationalResource
+		var parentID int64
+		if err := rows.Scan(&res.ID, &parentID, &res.Type, &res.Title, &res.Description, &res.URL, &res.Author, &res.Duration, &res.Completed); err != nil {
+			return err
+		}
+		res.Chapters = resourceChapters[res.ID]
+		resources[parentID] = append(resources[parentID], res)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	educationalRoadmaps := make(map[int64]*models.BackupEducationalRoadmap)
+	err = eachRow(ctx, tx, `SELECT id, roadmap_item_id, topic FROM educational_roadmaps ORDER BY id`, func(rows *sql.Rows) error {
+		var er models.BackupEducationalRoadmap
+		var itemID int64
+		if err := rows.Scan(&er.ID, &itemID, &er.Topic); err != nil {
+			return err
+		}
+		er.Resources = nonNil(resources[er.ID])
+		educationalRoadmaps[itemID] = &er
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Trilhas educacionais
+	activityChapters, err := chapters(ctx, tx, `SELECT activity_id, chapter_title FROM educational_trail_activity_chapters ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	activities := make(map[int64][]models.BackupTrailActivity)
+	err = eachRow(ctx, tx, `SELECT id, step_id, activity_type, resource_id, title, description, duration, url, progress,
+	                               COALESCE(completed, FALSE), completed_at
+	                        FROM educational_trail_activities ORDER BY id`, func(rows *sql.Rows) error {
+		var a models.BackupTrailActivity
+		var stepID int64
+		if err := rows.Scan(&a.ID, &stepID, &a.Type, &a.ResourceID, &a.Title, &a.Description, &a.Duration, &a.URL, &a.Progress,
+			&a.Completed, &a.CompletedAt); err != nil {
+			return err
+		}
+		a.Chapters = activityChapters[a.ID]
+		activities[stepID] = append(activities[stepID], a)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	steps := make(map[int64][]models.BackupTrailStep)
+	err = eachRow(ctx, tx, `SELECT id, trail_id, day, title, description, scheduled_date
+	                        FROM educational_trail_steps ORDER BY day, id`, func(rows *sql.Rows) error {
+		var s models.BackupTrailStep
+		var trailID int64
+		if err := rows.Scan(&s.ID, &trailID, &s.Day, &s.Title, &s.Description, &s.ScheduledDate); err != nil {
+			return err
+		}
+		s.Activities = nonNil(activities[s.ID])
+		steps[trailID] = append(steps[trailID], s)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	trailResourceChapters, err := chapters(ctx, tx, `SELECT resource_id, chapter_title FROM educational_trail_resource_chapters ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	trailResources := make(map[int64][]models.BackupTrailResource)
+	err = eachRow(ctx, tx, `SELECT id, trail_id, resource_id, title, description, author, duration, url
+	                        FROM educational_trail_resources ORDER BY id`, func(rows *sql.Rows) error {
+		var res models.BackupTrailResource
+		var trailID int64
+		if err := rows.Scan(&res.ID, &trailID, &res.ResourceID, &res.Title, &res.Description, &res.Author, &res.Duration, &res.URL); err != nil {
+			return err
+		}
+		res.Chapters = trailResourceChapters[res.ID]
+		trailResources[trailID] = append(trailResources[trailID], res)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	trails := make(map[int64]*models.BackupTrail)
+	err = eachRow(ctx, tx, `SELECT id, roadmap_item_id, topic, total_days, description, start_date
+	                        FROM educational_trails ORDER BY id`, func(rows *sql.Rows) error {
+		var t models.BackupTrail
+		var itemID int64
+		if err := rows.Scan(&t.ID, &itemID, &t.Topic, &t.TotalDays, &t.Description, &t.StartDate); err != nil {
+			return err
+		}
+		t.Resources = nonNil(trailResources[t.ID])
+		t.Steps = nonNil(steps[t.ID])
+		trails[itemID] = &t
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Roadmaps
+	items := make(map[int64][]models.BackupRoadmapItem)
+	err = eachRow(ctx, tx, `SELECT id, category_id, title, COALESCE(completed, FALSE) FROM roadmap_items ORDER BY id`, func(rows *sql.Rows) error {
+		var item models.BackupRoadmapItem
+		var categoryID int64
+		if err := rows.Scan(&item.ID, &categoryID, &item.Title, &item.Completed); err != nil {
+			return err
+		}
+		item.EducationalRoadmap = educationalRoadmaps[item.ID]
+		item.Trail = trails[item.ID]
+		items[categoryID] = append(items[categoryID], item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	roadmapCategories := make(map[int64][]models.BackupRoadmapCategory)
+	err = eachRow(ctx, tx, `SELECT id, roadmap_id, category FROM roadmap_categories ORDER BY id`, func(rows *sql.Rows) error {
+		var rc models.BackupRoadmapCategory
+		var roadmapID int64
+		if err := rows.Scan(&rc.ID, &roadmapID, &rc.Name); err != nil {
+			return err
+		}
+		rc.Items = nonNil(items[rc.ID])
+		roadmapCategories[roadmapID] = append(roadmapCategories[roadmapID], rc)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	roadmaps := make(map[int64]*models.BackupRoadmap)
+	err = eachRow(ctx, tx, `SELECT id, key_result_id, topic FROM roadmaps WHERE deleted_at IS NULL ORDER BY id`, func(rows *sql.Rows) error {
+		var rm models.BackupRoadmap
+		var keyResultID int64
+		if err := rows.Scan(&rm.ID, &keyResultID, &rm.Topic); err != nil {
+			return err
+		}
+		rm.Categories = nonNil(roadmapCategories[rm.ID])
+		roadmaps[keyResultID] = &rm
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// OKRs e Key Results
+	keyResults := make(map[int64][]models.BackupKeyResult)
+	err = eachRow(ctx, tx, `SELECT id, okr_id, title, COALESCE(completed, FALSE), difficulty, expected_completion_date, due_date_computed, created_at
+	                        FROM key_results WHERE deleted_at IS NULL ORDER BY id`, func(rows *sql.Rows) error {
+		var kr models.BackupKeyResult
+		var okrID int64
+		if err := rows.Scan(&kr.ID, &okrID, &kr.Title, &kr.Completed, &kr.Difficulty, &kr.ExpectedCompletionDate,
+			&kr.DueDateComputed, &kr.CreatedAt); err != nil {
+			return err
+		}
+		kr.Roadmap = roadmaps[kr.ID]
+		keyResults[okrID] = append(keyResults[okrID], kr)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = eachRow(ctx, tx, `SELECT id, objective, category_id, completion_date, status, status_reason, archived_at, created_at
+	                        FROM okrs WHERE deleted_at IS NULL ORDER BY id`, func(rows *sql.Rows) error {
+		var o models.BackupOKR
+		if err := rows.Scan(&o.ID, &o.Objective, &o.CategoryID, &o.CompletionDate, &o.Status, &o.StatusReason,
+			&o.ArchivedAt, &o.CreatedAt); err != nil {
+			return err
+		}
+		o.KeyResults = nonNil(keyResults[o.ID])
+		backup.OKRs = append(backup.OKRs, o)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = eachRow(ctx, tx, `SELECT id, name FROM categories ORDER BY id`, func(rows *sql.Rows) error {
+		var c models.BackupCategory
+		if err := rows.Scan(&c.ID, &c.Name); err != nil {
+			return err
+		}
+		backup.Categories = append(backup.Categories, c)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return backup, nil
+}
+
+// Import grava o conteúdo do arquivo em uma única transação, com novos IDs. Categorias
+// são associadas às existentes pelo nome; OKRs que já existem (mesmo objetivo na mesma
+// categoria) seguem a estratégia informada. Os registros criados entram no log de
+// auditoria, mas não geram eventos de webhook. now é o instante gravado nos registros
+// criados. Em dry-run a transação é desfeita ao final
+func (r *BackupRepository) Import(ctx context.Context, backup *models.Backup, strategy string, dryRun bool, now time.Time) (*models.ImportResult, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	imp := &backupImport{
+		tx:  tx,
+		now: now,
+		result: &models.ImportResult{
+			DryRun:   dryRun,
+			Strategy: strategy,
+			Created:  make(map[string]int),
+			IDMap: map[string]map[int64]int64{
+				models.AuditEntityCategory:  {},
+				models.AuditEntityOKR:       {},
+				models.AuditEntityKeyResult: {},
+			},
+		},
+	}
+
+	for _, c := range backup.Categories {
+		if err := imp.category(ctx, c); err != nil {
+			return nil, err
+		}
+	}
+	for _, o := range backup.OKRs {
+		if err := imp.okr(ctx, o, strategy); err != nil {
+			return nil, err
+		}
+	}
+
+	if dryRun {
+		return imp.result, nil
+	}
+	return imp.result, tx.Commit()
+}
+
+// backupImport guarda o estado de uma importação em andamento
+type backupImport struct {
+	tx     *sql.Tx
+	now    time.Time
+	result *models.ImportResult
+}
+
+// insert executa um INSERT ... RETURNING id, contabiliza o registro criado e o registra
+// na auditoria quando a entidade é auditada
+func (imp *backupImport) insert(ctx context.Context, entity, query string, args ...any) (int64, error) {
+	var id int64
+	if err := imp.tx.QueryRowContext(ctx, query, args...).Scan(&id); err != nil {
+		return 0, err
+	}
+	imp.result.Created[entity]++
+	if _, audited := auditTables[entity]; audited {
+		if err := recordAudit(ctx, imp.tx, entity, id, models.AuditActionCreate, nil); err != nil {
+			return 0, err
+		}
+	}
+	return id, nil
+}
+
+func (imp *backupImport) category(ctx context.Context, c models.BackupCategory) error {
+	var id int64
+	err := imp.tx.QueryRowContext(ctx, `SELECT id FROM categories WHERE name = $1`, c.Name).Scan(&id)
+	if err == sql.ErrNoRows {
+		id, err = imp.insert(ctx, models.AuditEntityCategory,
+			`INSERT INTO categories (name, created_at, updated_at) VALUES ($1, $2, $2) RETURNING id`, c.Name, imp.now)
+	}
+	if err != nil {
+		return err
+	}
+	imp.result.IDMap[models.AuditEntityCategory][c.ID] = id
+	return nil
+}
+
+func (imp *backupImport) okr(ctx context.Context, o models.BackupOKR, strategy string) error {
+	categoryID := imp.result.IDMap[models.AuditEntityCategory][o.CategoryID]
+
+	if strategy != models.ImportDuplicate {
+		var existingID int64
+		err := imp.tx.QueryRowContext(ctx, `SELECT id FROM okrs
+		                                     WHERE category_id = $1 AND objective = $2 AND deleted_at IS NULL
+		                                     ORDER BY id LIMIT 1`, categoryID, o.Objective).Scan(&existingID)
+		switch {
+		case err == sql.ErrNoRows:
+			// Não existe: importa normalmente
+		case err != nil:
+			return err
+		case strategy == models.ImportSkip:
+			imp.result.Skipped++
+			imp.result.IDMap[models.AuditEntityOKR][o.ID] = existingID
+			return nil
+		default:
+			before, err := auditSnapshot(ctx, imp.tx, models.AuditEntityOKR, existingID)
+			if err != nil {
+				return err
+			}
+			if err := trashOKR(ctx, imp.tx, existingID, imp.now); err != nil {
+				return err
+			}
+			if err := recordAudit(ctx, imp.tx, models.AuditEntityOKR, existingID, models.AuditActionDelete, before); err != nil {
+				return err
+			}
+			imp.result.Overwritten++
+		}
+	}
+
+	status := o.Status
+	if status == "" {
+		status = models.OKRStatusActive
+	}
+	createdAt := imp.orNow(o.CreatedAt)
+	okrID, err := imp.insert(ctx, models.AuditEntityOKR,
+		`INSERT INTO okrs (objective, category_id, completion_date, status, status_reason, status_changed_at, archived_at, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $6) RETURNING id`,
+		o.Objective, categoryID, o.CompletionDate, status, o.StatusReason, imp.now, o.ArchivedAt, createdAt)
+	if err != nil {
+		return err
+	}
+	if _, err := insertStatusChange(ctx, imp.tx, okrID, nil, status, o.StatusReason, imp.now); err != nil {
+		return err
+	}
+	imp.result.IDMap[models.AuditEntityOKR][o.ID] = okrID
+
+	for _, kr := range o.KeyResults {
+		difficulty := kr.Difficulty
+		if difficulty == 0 {
+			difficulty = models.DefaultKeyResultDifficulty
+		}
+		krID, err := imp.insert(ctx, models.AuditEntityKeyResult,
+			`INSERT INTO key_results (okr_id, title, completed, difficulty, expected_completion_date, due_date_computed, created_at, updated_at)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
+			okrID, kr.Title, kr.Completed, difficulty, kr.ExpectedCompletionDate, kr.DueDateComputed, imp.orNow(kr.CreatedAt), imp.now)
+		if err != nil {
+			return err
+		}
+		imp.result.IDMap[models.AuditEntityKeyResult][kr.ID] = krID
+
+		if kr.Roadmap != nil {
+			if err := imp.roadmap(ctx, krID, kr.Roadmap); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (imp *backupImport) roadmap(ctx context.Context, keyResultID int64, rm *models.BackupRoadmap) error {
+	roadmapID, err := imp.insert(ctx, "roadmap",
+		`INSERT INTO roadmaps (key_result_id, topic, created_at, updated_at) VALUES ($1, $2, $3, $3) RETURNING id`,
+		keyResultID, rm.Topic, imp.now)
+	if err != nil {
+		return err
+	}
+
+	for _, rc := range rm.Categories {
+		var categoryID int64
+		err := imp.tx.QueryRowContext(ctx, `INSERT INTO roadmap_categories (roadmap_id, category, created_at) VALUES ($1, $2, $3) RETURNING id`,
+			roadmapID, rc.Name, imp.now).Scan(&categoryID)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range rc.Items {
+			itemID, err := imp.insert(ctx, models.AuditEntityRoadmapItem,
+				`INSERT INTO roadmap_items (category_id, title, completed, created_at, updated_at) VALUES ($1, $2, $3, $4, $4) RETURNING id`,
+				categoryID, item.Title, item.Completed, imp.now)
+			if err != nil {
+				return err
+			}
+			if item.EducationalRoadmap != nil {
+				if err := imp.educationalRoadmap(ctx, itemID, item.EducationalRoadmap); err != nil {
+					return err
+				}
+			}
+			if item.Trail != nil {
+				if err := imp.trail(ctx, itemID, item.Trail); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (imp *backupImport) educationalRoadmap(ctx context.Context, roadmapItemID int64, er *models.BackupEducationalRoadmap) error {
+	roadmapID, err := imp.insert(ctx, "educational_roadmap",
+		`INSERT INTO educational_roadmaps (roadmap_item_id, topic, created_at, updated_at) VALUES ($1, $2, $3, $3) RETURNING id`,
+		roadmapItemID, er.Topic, imp.now)
+	if err != nil {
+		return err
+	}
+
+	for _, res := range er.Resources {
+		resourceID, err := imp.insert(ctx, models.AuditEntityEducationalResource,
+			`INSERT INTO educational_resources (educational_roadmap_id, resource_type, title, description, url, author, duration, completed, created_at, updated_at)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9) RETURNING id`,
+			roadmapID, res.Type, res.Title, res.Description, res.URL, res.Author, res.Duration, res.Completed, imp.now)
+		if err != nil {
+			return err
+		}
+		if err := imp.chapters(ctx, `INSERT INTO educational_resource_chapters (resource_id, chapter_title, created_at) VALUES ($1, $2, $3)`,
+			resourceID, res.Chapters); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (imp *backupImport) trail(ctx context.Context, roadmapItemID int64, t *models.BackupTrail) error {
+	trailID, err := imp.insert(ctx, "trail",
+		`INSERT INTO educational_trails (roadmap_item_id, topic, total_days, description, start_date, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $6) RETURNING id`,
+		roadmapItemID, t.Topic, t.TotalDays, t.Description, t.StartDate, imp.now)
+	if err != nil {
+		return err
+	}
+
+	for _, res := range t.Resources {
+		var resourceID int64
+		err := imp.tx.QueryRowContext(ctx, `INSERT INTO educational_trail_resources (trail_id, resource_id, title, description, author, duration, url, created_at)
+		                                    VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
+			trailID, res.ResourceID, res.Title, res.Description, res.Author, res.Duration, res.URL, imp.now).Scan(&resourceID)
+		if err != nil {
+			return err
+		}
+		if err := imp.chapters(ctx, `INSERT INTO educational_trail_resource_chapters (resource_id, chapter_title, created_at) VALUES ($1, $2, $3)`,
+			resourceID, res.Chapters); err != nil {
+			return err
+		}
+	}
+
+	for _, step := range t.Steps {
+		stepID, err := imp.insert(ctx, "trail_step",
+			`INSERT INTO educational_trail_steps (trail_id, day, scheduled_date, title, description, created_at)
+			 VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+			trailID, step.Day, step.ScheduledDate, step.Title, step.Description, imp.now)
+		if err != nil {
+			return err
+		}
+
+		for _, a := range step.Activities {
+			activityID, err := imp.insert(ctx, models.AuditEntityTrailActivity,
+				`INSERT INTO educational_trail_activities
+				 (step_id, activity_type, resource_id, title, description, duration, url, progress, completed, completed_at, created_at, updated_at)
+				 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $11) RETURNING id`,
+				stepID, a.Type, a.ResourceID, a.Title, a.Description, a.Duration, a.URL, a.Progress, a.Completed, a.CompletedAt, imp.now)
+			if err != nil {
+				return err
+			}
+			if err := imp.chapters(ctx, `INSERT INTO educational_trail_activity_chapters (activity_id, chapter_title, created_at) VALUES ($1, $2, $3)`,
+				activityID, a.Chapters); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// orNow devolve t ou, quando o arquivo não trouxe a data, o instante da importação
+func (imp *backupImport) orNow(t time.Time) time.Time {
+	if t.IsZero() {
+		return imp.now
+	}
+	return t
+}
+
+func (imp *backupImport) chapters(ctx context.Context, query string, parentID int64, titles []string) error {
+	for _, title := range titles {
+		if _, err := imp.tx.ExecContext(ctx, query, parentID, title, imp.now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// eachRow executa a consulta e chama scan para cada linha
+func eachRow(ctx context.Context, tx *sql.Tx, query string, scan func(*sql.Rows) error) error {
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// chapters lê uma tabela de capítulos (id do pai, título) agrupando os títulos pelo pai
+func chapters(ctx context.Context, tx *sql.Tx, query string) (map[int64][]string, error) {
+	byParent := make(map[int64][]string)
+	err := eachRow(ctx, tx, query, func(rows *sql.Rows) error {
+		var parentID int64
+		var title string
+		if err := rows.Scan(&parentID, &title); err != nil {
+			return err
+		}
+		byParent[parentID] = append(byParent[parentID], title)
+		return nil
+	})
+	return byParent, err
+}
+
+// nonNil troca listas vazias por [] no JSON
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
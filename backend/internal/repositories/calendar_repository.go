@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/conquista-ai/conquista-ai/internal/planning"
+)
+
+type CalendarRepository struct {
+	db    *sql.DB
+	clock planning.Clock
+}
+
+func NewCalendarRepository(db *sql.DB, clock planning.Clock) *CalendarRepository {
+	return &CalendarRepository{db: db, clock: clock}
+}
+
+// calendarQueries lista as datas do planejamento por origem. Todas retornam as colunas
+// id, title, context, date, completed e updated_at. OKRs arquivados, pausados ou
+// encerrados ficam de fora
+var calendarQueries = []struct {
+	kind  string
+	query string
+}{
+	{
+		// Um OKR está concluído quando tem Key Results e todos foram concluídos
+		kind: models.CalendarEntryOKR,
+		query: `SELECT o.id, o.objective, '', o.completion_date,
+		               EXISTS (SELECT 1 FROM key_results kr WHERE kr.okr_id = o.id AND kr.deleted_at IS NULL)
+		               AND NOT EXISTS (SELECT 1 FROM key_results kr WHERE kr.okr_id = o.id AND kr.deleted_at IS NULL AND NOT kr.completed),
+		               o.updated_at
+		        FROM okrs o
+		        WHERE o.completion_date IS NOT NULL AND o.deleted_at IS NULL AND ` + trackedOKR("o") + `
+		        ORDER BY o.completion_date, o.id`,
+	},
+	{
+		kind: models.CalendarEntryKeyResult,
+		query: `SELECT kr.id, kr.title, o.objective, kr.expected_completion_date, COALESCE(kr.completed, FALSE), kr.updated_at
+		        FROM key_results kr
+		        JOIN okrs o ON o.id = kr.okr_id
+		        WHERE kr.expected_completion_date IS NOT NULL AND kr.deleted_at IS NULL AND ` + trackedOKR("o") + `
+		        ORDER BY kr.expected_completion_date, kr.id`,
+	},
+	{
+		// A data de atualização da trilha muda a cada replanejamento
+		kind: models.CalendarEntryTrailStep,
+		query: `SELECT s.id, s.title, t.topic, s.scheduled_date,
+		               NOT EXISTS (SELECT 1 FROM educational_trail_activities a WHERE a.step_id = s.id AND NOT COALESCE(a.completed, FALSE)),
+		               t.updated_at
+		        FROM educational_trail_steps s
+		        JOIN educational_trails t ON t.id = s.trail_id
+		        WHERE s.scheduled_date IS NOT NULL AND ` + activeRoadmapItem("t.roadmap_item_id") + `
+		          AND ` + trackedRoadmapItem("t.roadmap_item_id") + `
+		        ORDER BY s.scheduled_date, s.id`,
+	},
+}
+
+// Entries retorna todas as datas do planejamento que devem aparecer no calendário
+func (r *CalendarRepository) Entries(ctx context.Context) ([]models.CalendarEntry, error) {
+	entries := make([]models.CalendarEntry, 0)
+	for _, source := range calendarQueries {
+		rows, err := r.db.QueryContext(ctx, source.query)
+		if err != nil {
+			return nil, err
+		}
+
+		for rows.Next() {
+			entry := models.CalendarEntry{Kind: source.kind}
+			if err := rows.Scan(&entry.ID, &entry.Title, &entry.Context, &entry.Date,
+				&entry.Completed, &entry.UpdatedAt); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			entries = append(entries, entry)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return entries, nil
+}
+
+// ReplaceFeedToken grava o hash de um novo token do feed, revogando os anteriores
+func (r *CalendarRepository) ReplaceFeedToken(ctx context.Context, tokenHash string) (time.Time, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM calendar_feed_tokens`); err != nil {
+		return time.Time{}, err
+	}
+
+	now := r.clock.Now()
+	if _, err := tx.ExecContext(ctx, `INSERT INTO calendar_feed_tokens (token_hash, created_at) VALUES ($1, $2)`,
+		tokenHash, now); err != nil {
+		return time.Time{}, err
+	}
+
+	return now, tx.Commit()
+}
+
+// UseFeedToken indica se o hash pertence a um token válido e registra o uso
+func (r *CalendarRepository) UseFeedToken(ctx context.Context, tokenHash string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `UPDATE calendar_feed_tokens SET last_used_at = $1 WHERE token_hash = $2`,
+		r.clock.Now(), tokenHash)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
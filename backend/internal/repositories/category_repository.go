@@ -1,9 +1,11 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"time"
 
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
 	"github.com/conquista-ai/conquista-ai/internal/models"
 )
 
@@ -15,7 +17,13 @@ func NewCategoryRepository(db *sql.DB) *CategoryRepository {
 	return &CategoryRepository{db: db}
 }
 
-func (r *CategoryRepository) Create(category *models.Category) error {
+func (r *CategoryRepository) Create(ctx context.Context, category *models.Category) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `INSERT INTO categories (name, created_at, updated_at) 
 	          VALUES ($1, $2, $3) RETURNING id`
 	
@@ -23,18 +31,22 @@ func (r *CategoryRepository) Create(category *models.Category) error {
 	category.CreatedAt = now
 	category.UpdatedAt = now
 	
-	err := r.db.QueryRow(query, category.Name, category.CreatedAt, category.UpdatedAt).Scan(&category.ID)
+	err = tx.QueryRowContext(ctx, query, category.Name, category.CreatedAt, category.UpdatedAt).Scan(&category.ID)
 	if err != nil {
 		return err
 	}
+
+	if err := recordAudit(ctx, tx, models.AuditEntityCategory, category.ID, models.AuditActionCreate, nil); err != nil {
+		return err
+	}
 	
-	return nil
+	return tx.Commit()
 }
 
-func (r *CategoryRepository) GetAll() ([]models.Category, error) {
+func (r *CategoryRepository) GetAll(ctx context.Context) ([]models.Category, error) {
 	query := `SELECT id, name, created_at, updated_at FROM categories ORDER BY name`
 	
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return []models.Category{}, err
 	}
@@ -56,11 +68,11 @@ func (r *CategoryRepository) GetAll() ([]models.Category, error) {
 	return categories, nil
 }
 
-func (r *CategoryRepository) GetByID(id int64) (*models.Category, error) {
+func (r *CategoryRepository) GetByID(ctx context.Context, id int64) (*models.Category, error) {
 	query := `SELECT id, name, created_at, updated_at FROM categories WHERE id = $1`
 	
 	var c models.Category
-	err := r.db.QueryRow(query, id).Scan(&c.ID, &c.Name, &c.CreatedAt, &c.UpdatedAt)
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&c.ID, &c.Name, &c.CreatedAt, &c.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -71,17 +83,58 @@ func (r *CategoryRepository) GetByID(id int64) (*models.Category, error) {
 	return &c, nil
 }
 
-func (r *CategoryRepository) Update(category *models.Category) error {
+func (r *CategoryRepository) Update(ctx context.Context, category *models.Category) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := auditSnapshot(ctx, tx, models.AuditEntityCategory, category.ID)
+	if err != nil {
+		return err
+	}
+	if before == nil {
+		return apperrors.NotFound("categoria não encontrada")
+	}
+
 	query := `UPDATE categories SET name = $1, updated_at = $2 WHERE id = $3`
 	
 	category.UpdatedAt = time.Now()
-	_, err := r.db.Exec(query, category.Name, category.UpdatedAt, category.ID)
-	return err
+	if _, err := tx.ExecContext(ctx, query, category.Name, category.UpdatedAt, category.ID); err != nil {
+		return err
+	}
+
+	if err := recordAudit(ctx, tx, models.AuditEntityCategory, category.ID, models.AuditActionUpdate, before); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func (r *CategoryRepository) Delete(id int64) error {
-	query := `DELETE FROM categories WHERE id = $1`
-	_, err := r.db.Exec(query, id)
-	return err
+func (r *CategoryRepository) Delete(ctx context.Context, id int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := auditSnapshot(ctx, tx, models.AuditEntityCategory, id)
+	if err != nil {
+		return err
+	}
+	if before == nil {
+		return apperrors.NotFound("categoria não encontrada")
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM categories WHERE id = $1`, id); err != nil {
+		return err
+	}
+
+	if err := recordAudit(ctx, tx, models.AuditEntityCategory, id, models.AuditActionDelete, before); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/models"
+)
+
+type DashboardRepository struct {
+	db *sql.DB
+}
+
+func NewDashboardRepository(db *sql.DB) *DashboardRepository {
+	return &DashboardRepository{db: db}
+}
+
+// dashboardQuery monta todo o dashboard em um único documento JSON, em uma única ida
+// ao banco. Parâmetros: $1 hoje, $2 fim da janela de prazos, $3 e $4 limites de prazos
+// e de atividades recentes. As datas levam cast explícito em todas as ocorrências para
+// que o tipo deduzido de cada parâmetro seja o mesmo. Timestamps (gravados em UTC) são
+// convertidos para timestamptz para que o JSON traga o fuso. Prazos e atrasos só
+// consideram OKRs acompanhados (veja trackedOKR); os totais incluem todos os OKRs
+var dashboardQuery = `
+WITH okr_progress AS (
+    SELECT o.id, o.objective, o.category_id, o.completion_date,
+           COUNT(k.id) AS key_results,
+           COUNT(k.id) FILTER (WHERE k.completed) AS completed_key_results,
+           CASE WHEN COUNT(k.id) = 0 THEN 0
+                ELSE ROUND(COUNT(k.id) FILTER (WHERE k.completed) * 100.0 / COUNT(k.id))::int END AS progress,
+           ` + trackedOKR("o") + ` AS tracked
+    FROM okrs o
+    LEFT JOIN key_results k ON k.okr_id = o.id AND k.deleted_at IS NULL
+    WHERE o.deleted_at IS NULL
+    GROUP BY o.id
+),
+activity_okr AS (
+    SELECT a.id, a.title, a.completed, a.completed_at, kr.okr_id
+    FROM educational_trail_activities a
+    JOIN educational_trail_steps s ON s.id = a.step_id
+    JOIN educational_trails t ON t.id = s.trail_id
+    JOIN roadmap_items ri ON ri.id = t.roadmap_item_id
+    JOIN roadmap_categories rc ON rc.id = ri.category_id
+    JOIN roadmaps r ON r.id = rc.roadmap_id
+    JOIN key_results kr ON kr.id = r.key_result_id
+    WHERE r.deleted_at IS NULL
+)
+SELECT json_build_object(
+    'totals', (
+        SELECT json_build_object(
+            'okrs', COUNT(*),
+            'completed_okrs', COUNT(*) FILTER (WHERE key_results > 0 AND completed_key_results = key_results),
+            'in_progress_okrs', COUNT(*) FILTER (WHERE completed_key_results > 0 AND completed_key_results < key_results),
+            'not_started_okrs', COUNT(*) FILTER (WHERE completed_key_results = 0),
+            'key_results', COALESCE(SUM(key_results), 0),
+            'completed_key_results', COALESCE(SUM(completed_key_results), 0),
+            'overdue_key_results', (SELECT COUNT(*) FROM key_results k
+                                    JOIN okrs o ON o.id = k.okr_id
+                                    WHERE NOT COALESCE(k.completed, FALSE) AND k.expected_completion_date < $1::date
+                                      AND k.deleted_at IS NULL AND ` + trackedOKR("o") + `),
+            'average_progress', COALESCE(ROUND(AVG(progress)), 0)
+        )
+        FROM okr_progress
+    ),
+    'categories', (
+        SELECT COALESCE(json_agg(c ORDER BY c.name), '[]')
+        FROM (
+            SELECT c.id AS category_id, c.name, COUNT(p.id) AS okrs,
+                   COALESCE(SUM(p.key_results), 0) AS key_results,
+                   COALESCE(SUM(p.completed_key_results), 0) AS completed_key_results
+            FROM categories c
+            LEFT JOIN okr_progress p ON p.category_id = c.id
+            GROUP BY c.id
+        ) c
+    ),
+    'okrs', (
+        SELECT COALESCE(json_agg(json_build_object(
+            'okr_id', id, 'key_results', key_results,
+            'completed_key_results', completed_key_results, 'progress', progress) ORDER BY id), '[]')
+        FROM okr_progress
+    ),
+    'upcoming_deadlines', (
+        SELECT COALESCE(json_agg(json_build_object(
+            'kind', kind, 'id', id, 'okr_id', okr_id, 'title', title,
+            'due_date', to_char(due_date, 'YYYY-MM-DD'), 'days_remaining', due_date - $1::date)
+            ORDER BY due_date, kind, id), '[]')
+        FROM (
+            SELECT * FROM (
+                SELECT 'key_result' AS kind, k.id, k.okr_id, k.title, k.expected_completion_date::date AS due_date
+                FROM key_results k
+                JOIN okrs o ON o.id = k.okr_id
+                WHERE NOT COALESCE(k.completed, FALSE) AND k.expected_completion_date::date BETWEEN $1::date AND $2::date
+                  AND k.deleted_at IS NULL AND ` + trackedOKR("o") + `
+                UNION ALL
+                SELECT 'okr', p.id, p.id, p.objective, p.completion_date::date
+                FROM okr_progress p
+                WHERE p.completion_date::date BETWEEN $1::date AND $2::date AND p.tracked
+                  AND NOT (p.key_results > 0 AND p.completed_key_results = p.key_results)
+            ) d
+            ORDER BY due_date, kind, id
+            LIMIT $3
+        ) u
+    ),
+    'recent_activity', (
+        SELECT COALESCE(json_agg(json_build_object(
+            'kind', kind, 'okr_id', okr_id, 'title', title, 'minutes', minutes,
+            'at', at AT TIME ZONE 'UTC') ORDER BY at DESC), '[]')
+        FROM (
+            SELECT * FROM (
+                SELECT 'okr_created' AS kind, o.id AS okr_id, o.objective AS title, NULL::int AS minutes, o.created_at AS at
+                FROM okrs o WHERE o.deleted_at IS NULL
+                UNION ALL
+                SELECT 'key_result_completed', k.okr_id, k.title, NULL, k.updated_at
+                FROM key_results k WHERE k.completed AND k.deleted_at IS NULL
+                UNION ALL
+                SELECT 'trail_activity_completed', a.okr_id, a.title, NULL, a.completed_at
+                FROM activity_okr a WHERE a.completed_at IS NOT NULL
+                UNION ALL
+                SELECT 'study_session', a.okr_id, a.title, ss.minutes, ss.ended_at
+                FROM study_sessions ss
+                JOIN activity_okr a ON a.id = ss.activity_id
+                WHERE ss.ended_at IS NOT NULL AND ss.minutes > 0
+            ) e
+            WHERE at IS NOT NULL
+            ORDER BY at DESC
+            LIMIT $4
+        ) r
+    ),
+    'roadmaps', (
+        SELECT json_build_object(
+            'roadmaps', (SELECT COUNT(*) FROM roadmaps WHERE deleted_at IS NULL),
+            'items', COUNT(*),
+            'completed_items', COUNT(*) FILTER (WHERE ri.completed))
+        FROM roadmap_items ri
+        JOIN roadmap_categories rc ON rc.id = ri.category_id
+        JOIN roadmaps r ON r.id = rc.roadmap_id
+        WHERE r.deleted_at IS NULL
+    ),
+    'trails', (
+        SELECT json_build_object(
+            'trails', (SELECT COUNT(*) FROM educational_trails t WHERE ` + activeRoadmapItem("t.roadmap_item_id") + `),
+            'activities', COUNT(*),
+            'completed_activities', COUNT(*) FILTER (WHERE a.completed),
+            'overdue_steps', (SELECT COUNT(*) FROM educational_trail_steps s
+                              JOIN educational_trails t ON t.id = s.trail_id
+                              WHERE s.scheduled_date < $1::date
+                                AND ` + activeRoadmapItem("t.roadmap_item_id") + `
+                                AND EXISTS (SELECT 1 FROM educational_trail_activities pa
+                                            WHERE pa.step_id = s.id AND NOT COALESCE(pa.completed, FALSE))),
+            'studied_minutes', (SELECT COALESCE(SUM(ss.minutes), 0) FROM study_sessions ss
+                                JOIN activity_okr a ON a.id = ss.activity_id))
+        FROM activity_okr a
+    )
+)`
+
+// Get calcula o dashboard. today é a data de referência dos prazos; são listados os
+// prazos até today + upcomingDays
+func (r *DashboardRepository) Get(ctx context.Context, today time.Time, upcomingDays, upcomingLimit, activityLimit int) (*models.Dashboard, error) {
+	day := today.Format("2006-01-02")
+	horizon := today.AddDate(0, 0, upcomingDays).Format("2006-01-02")
+
+	var payload []byte
+	if err := r.db.QueryRowContext(ctx, dashboardQuery, day, horizon, upcomingLimit, activityLimit).Scan(&payload); err != nil {
+		return nil, err
+	}
+
+	var dashboard models.Dashboard
+	if err := json.Unmarshal(payload, &dashboard); err != nil {
+		return nil, err
+	}
+	return &dashboard, nil
+}
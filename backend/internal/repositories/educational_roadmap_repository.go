@@ -1,9 +1,11 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"time"
 
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
 	"github.com/conquista-ai/conquista-ai/internal/models"
 )
 
@@ -15,8 +17,8 @@ func NewEducationalRoadmapRepository(db *sql.DB) *EducationalRoadmapRepository {
 	return &EducationalRoadmapRepository{db: db}
 }
 
-func (r *EducationalRoadmapRepository) Create(roadmap *models.EducationalRoadmap) error {
-	tx, err := r.db.Begin()
+func (r *EducationalRoadmapRepository) Create(ctx context.Context, roadmap *models.EducationalRoadmap) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
@@ -29,7 +31,7 @@ func (r *EducationalRoadmapRepository) Create(roadmap *models.EducationalRoadmap
 	// Criar educational roadmap
 	query := `INSERT INTO educational_roadmaps (roadmap_item_id, topic, created_at, updated_at) 
 	          VALUES ($1, $2, $3, $4) RETURNING id`
-	err = tx.QueryRow(query, roadmap.RoadmapItemID, roadmap.Topic, roadmap.CreatedAt, roadmap.UpdatedAt).Scan(&roadmap.ID)
+	err = tx.QueryRowContext(ctx, query, roadmap.RoadmapItemID, roadmap.Topic, roadmap.CreatedAt, roadmap.UpdatedAt).Scan(&roadmap.ID)
 	if err != nil {
 		return err
 	}
@@ -54,18 +56,21 @@ func (r *EducationalRoadmapRepository) Create(roadmap *models.EducationalRoadmap
 			resourceQuery := `INSERT INTO educational_resources 
 			                  (educational_roadmap_id, resource_type, title, description, url, author, duration, completed, created_at, updated_at) 
 			                  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`
-			err = tx.QueryRow(resourceQuery, res.RoadmapID, res.Type, res.Title, res.Description,
+			err = tx.QueryRowContext(ctx, resourceQuery, res.RoadmapID, res.Type, res.Title, res.Description,
 				res.URL, res.Author, res.Duration, res.Completed, res.CreatedAt, res.UpdatedAt).Scan(&res.ID)
 			if err != nil {
 				return err
 			}
+			if err := recordAudit(ctx, tx, models.AuditEntityEducationalResource, res.ID, models.AuditActionCreate, nil); err != nil {
+				return err
+			}
 
 			// Criar capítulos se for livro
 			if resourceType == "book" && len(res.Chapters) > 0 {
 				for _, chapterTitle := range res.Chapters {
 					chapterQuery := `INSERT INTO educational_resource_chapters (resource_id, chapter_title, created_at) 
 					                 VALUES ($1, $2, $3)`
-					_, err = tx.Exec(chapterQuery, res.ID, chapterTitle, now)
+					_, err = tx.ExecContext(ctx, chapterQuery, res.ID, chapterTitle, now)
 					if err != nil {
 						return err
 					}
@@ -81,16 +86,20 @@ func (r *EducationalRoadmapRepository) Create(roadmap *models.EducationalRoadmap
 	roadmap.Articles = resourceTypes["article"]
 	roadmap.Projects = resourceTypes["project"]
 
+	if err := enqueueEvent(ctx, tx, models.EventEducationalRoadmapGenerated, roadmap); err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
 
-func (r *EducationalRoadmapRepository) GetByRoadmapItemID(roadmapItemID int64) (*models.EducationalRoadmap, error) {
+func (r *EducationalRoadmapRepository) GetByRoadmapItemID(ctx context.Context, roadmapItemID int64) (*models.EducationalRoadmap, error) {
 	// Buscar educational roadmap
 	query := `SELECT id, roadmap_item_id, topic, created_at, updated_at 
 	          FROM educational_roadmaps WHERE roadmap_item_id = $1`
 
 	var roadmap models.EducationalRoadmap
-	err := r.db.QueryRow(query, roadmapItemID).Scan(&roadmap.ID, &roadmap.RoadmapItemID,
+	err := r.db.QueryRowContext(ctx, query, roadmapItemID).Scan(&roadmap.ID, &roadmap.RoadmapItemID,
 		&roadmap.Topic, &roadmap.CreatedAt, &roadmap.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -102,7 +111,7 @@ func (r *EducationalRoadmapRepository) GetByRoadmapItemID(roadmapItemID int64) (
 	// Buscar recursos educacionais
 	resourcesQuery := `SELECT id, educational_roadmap_id, resource_type, title, description, url, author, duration, completed, created_at, updated_at 
 	                   FROM educational_resources WHERE educational_roadmap_id = $1 ORDER BY resource_type, id`
-	resourceRows, err := r.db.Query(resourcesQuery, roadmap.ID)
+	resourceRows, err := r.db.QueryContext(ctx, resourcesQuery, roadmap.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -126,7 +135,7 @@ func (r *EducationalRoadmapRepository) GetByRoadmapItemID(roadmapItemID int64) (
 		// Buscar capítulos se for livro
 		if res.Type == "book" {
 			chaptersQuery := `SELECT chapter_title FROM educational_resource_chapters WHERE resource_id = $1 ORDER BY id`
-			chapterRows, err := r.db.Query(chaptersQuery, res.ID)
+			chapterRows, err := r.db.QueryContext(ctx, chaptersQuery, res.ID)
 			if err != nil {
 				resourceRows.Close()
 				return nil, err
@@ -163,9 +172,29 @@ func (r *EducationalRoadmapRepository) GetByRoadmapItemID(roadmapItemID int64) (
 	return &roadmap, nil
 }
 
-func (r *EducationalRoadmapRepository) UpdateResourceCompleted(resourceID int64, completed bool) error {
+func (r *EducationalRoadmapRepository) UpdateResourceCompleted(ctx context.Context, resourceID int64, completed bool) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := auditSnapshot(ctx, tx, models.AuditEntityEducationalResource, resourceID)
+	if err != nil {
+		return err
+	}
+	if before == nil {
+		return apperrors.NotFound("recurso educacional não encontrado")
+	}
+
 	query := `UPDATE educational_resources SET completed = $1, updated_at = $2 WHERE id = $3`
-	_, err := r.db.Exec(query, completed, time.Now(), resourceID)
-	return err
-}
+	if _, err := tx.ExecContext(ctx, query, completed, time.Now(), resourceID); err != nil {
+		return err
+	}
 
+	if err := recordAudit(ctx, tx, models.AuditEntityEducationalResource, resourceID, models.AuditActionUpdate, before); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -1,37 +1,40 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
-	"fmt"
 	"time"
 
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
 	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/conquista-ai/conquista-ai/internal/planning"
 )
 
 type EducationalTrailRepository struct {
-	db *sql.DB
+	db    *sql.DB
+	clock planning.Clock
 }
 
-func NewEducationalTrailRepository(db *sql.DB) *EducationalTrailRepository {
-	return &EducationalTrailRepository{db: db}
+func NewEducationalTrailRepository(db *sql.DB, clock planning.Clock) *EducationalTrailRepository {
+	return &EducationalTrailRepository{db: db, clock: clock}
 }
 
-func (r *EducationalTrailRepository) Create(trail *models.EducationalTrail) error {
-	tx, err := r.db.Begin()
+func (r *EducationalTrailRepository) Create(ctx context.Context, trail *models.EducationalTrail) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	now := time.Now()
+	now := r.clock.Now()
 	trail.CreatedAt = now
 	trail.UpdatedAt = now
 
 	// Criar trilha
-	query := `INSERT INTO educational_trails (roadmap_item_id, topic, total_days, description, created_at, updated_at) 
-	          VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
-	err = tx.QueryRow(query, trail.RoadmapItemID, trail.Topic, trail.TotalDays, trail.Description, trail.CreatedAt, trail.UpdatedAt).Scan(&trail.ID)
+	query := `INSERT INTO educational_trails (roadmap_item_id, topic, total_days, description, start_date, created_at, updated_at) 
+	          VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+	err = tx.QueryRowContext(ctx, query, trail.RoadmapItemID, trail.Topic, trail.TotalDays, trail.Description, trail.StartDate, trail.CreatedAt, trail.UpdatedAt).Scan(&trail.ID)
 	if err != nil {
 		return err
 	}
@@ -42,7 +45,7 @@ func (r *EducationalTrailRepository) Create(trail *models.EducationalTrail) erro
 		                  (trail_id, resource_id, title, description, author, duration, url, created_at) 
 		                  VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
 		var resourceDBID int64
-		err = tx.QueryRow(resourceQuery, trail.ID, resourceID, resource.Title, resource.Description,
+		err = tx.QueryRowContext(ctx, resourceQuery, trail.ID, resourceID, resource.Title, resource.Description,
 			resource.Author, resource.Duration, resource.URL, now).Scan(&resourceDBID)
 		if err != nil {
 			return err
@@ -53,7 +56,7 @@ func (r *EducationalTrailRepository) Create(trail *models.EducationalTrail) erro
 			for _, chapter := range resource.Chapters {
 				chapterQuery := `INSERT INTO educational_trail_resource_chapters (resource_id, chapter_title, created_at) 
 				                 VALUES ($1, $2, $3)`
-				_, err = tx.Exec(chapterQuery, resourceDBID, chapter, now)
+				_, err = tx.ExecContext(ctx, chapterQuery, resourceDBID, chapter, now)
 				if err != nil {
 					return err
 				}
@@ -62,10 +65,11 @@ func (r *EducationalTrailRepository) Create(trail *models.EducationalTrail) erro
 	}
 
 	// Salvar steps e atividades
-	for _, step := range trail.Steps {
-		stepQuery := `INSERT INTO educational_trail_steps (trail_id, day, title, description, created_at) 
-		              VALUES ($1, $2, $3, $4, $5) RETURNING id`
-		err = tx.QueryRow(stepQuery, trail.ID, step.Day, step.Title, step.Description, now).Scan(&step.ID)
+	for i := range trail.Steps {
+		step := &trail.Steps[i]
+		stepQuery := `INSERT INTO educational_trail_steps (trail_id, day, scheduled_date, title, description, created_at) 
+		              VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+		err = tx.QueryRowContext(ctx, stepQuery, trail.ID, step.Day, step.ScheduledDate, step.Title, step.Description, now).Scan(&step.ID)
 		if err != nil {
 			return err
 		}
@@ -77,19 +81,23 @@ func (r *EducationalTrailRepository) Create(trail *models.EducationalTrail) erro
 			activityQuery := `INSERT INTO educational_trail_activities 
 			                  (step_id, activity_type, resource_id, title, description, duration, url, progress, completed, created_at, updated_at) 
 			                  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`
-			err = tx.QueryRow(activityQuery, step.ID, activity.Type, activity.ResourceID, activity.Title,
+			err = tx.QueryRowContext(ctx, activityQuery, step.ID, activity.Type, activity.ResourceID, activity.Title,
 				activity.Description, activity.Duration, activity.URL, activity.Progress, activity.Completed, now, now).Scan(&activity.ID)
 			if err != nil {
 				return err
 			}
 			activity.StepID = step.ID
 
+			if err := recordAudit(ctx, tx, models.AuditEntityTrailActivity, activity.ID, models.AuditActionCreate, nil); err != nil {
+				return err
+			}
+
 			// Salvar capítulos da atividade
 			if len(activity.Chapters) > 0 {
 				for _, chapter := range activity.Chapters {
 					chapterQuery := `INSERT INTO educational_trail_activity_chapters (activity_id, chapter_title, created_at) 
 					                 VALUES ($1, $2, $3)`
-					_, err = tx.Exec(chapterQuery, activity.ID, chapter, now)
+					_, err = tx.ExecContext(ctx, chapterQuery, activity.ID, chapter, now)
 					if err != nil {
 						return err
 					}
@@ -98,17 +106,21 @@ func (r *EducationalTrailRepository) Create(trail *models.EducationalTrail) erro
 		}
 	}
 
+	if err := enqueueEvent(ctx, tx, models.EventEducationalTrailGenerated, trail); err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
 
-func (r *EducationalTrailRepository) GetByRoadmapItemID(roadmapItemID int64) (*models.EducationalTrail, error) {
+func (r *EducationalTrailRepository) GetByRoadmapItemID(ctx context.Context, roadmapItemID int64) (*models.EducationalTrail, error) {
 	// Buscar trilha
-	query := `SELECT id, roadmap_item_id, topic, total_days, description, created_at, updated_at 
+	query := `SELECT id, roadmap_item_id, topic, total_days, description, start_date, created_at, updated_at 
 	          FROM educational_trails WHERE roadmap_item_id = $1`
 
 	var trail models.EducationalTrail
-	err := r.db.QueryRow(query, roadmapItemID).Scan(&trail.ID, &trail.RoadmapItemID, &trail.Topic,
-		&trail.TotalDays, &trail.Description, &trail.CreatedAt, &trail.UpdatedAt)
+	err := r.db.QueryRowContext(ctx, query, roadmapItemID).Scan(&trail.ID, &trail.RoadmapItemID, &trail.Topic,
+		&trail.TotalDays, &trail.Description, &trail.StartDate, &trail.CreatedAt, &trail.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -119,7 +131,7 @@ func (r *EducationalTrailRepository) GetByRoadmapItemID(roadmapItemID int64) (*m
 	// Buscar recursos
 	resourcesQuery := `SELECT id, resource_id, title, description, author, duration, url, created_at 
 	                   FROM educational_trail_resources WHERE trail_id = $1`
-	resourceRows, err := r.db.Query(resourcesQuery, trail.ID)
+	resourceRows, err := r.db.QueryContext(ctx, resourcesQuery, trail.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -140,7 +152,7 @@ func (r *EducationalTrailRepository) GetByRoadmapItemID(roadmapItemID int64) (*m
 
 		// Buscar capítulos do recurso
 		chaptersQuery := `SELECT chapter_title FROM educational_trail_resource_chapters WHERE resource_id = $1 ORDER BY id`
-		chapterRows, err := r.db.Query(chaptersQuery, res.ID)
+		chapterRows, err := r.db.QueryContext(ctx, chaptersQuery, res.ID)
 		if err != nil {
 			resourceRows.Close()
 			return nil, err
@@ -162,9 +174,9 @@ func (r *EducationalTrailRepository) GetByRoadmapItemID(roadmapItemID int64) (*m
 	}
 
 	// Buscar steps
-	stepsQuery := `SELECT id, trail_id, day, title, description, created_at 
-	               FROM educational_trail_steps WHERE trail_id = $1 ORDER BY day`
-	stepRows, err := r.db.Query(stepsQuery, trail.ID)
+	stepsQuery := `SELECT id, trail_id, day, scheduled_date, title, description, created_at 
+	               FROM educational_trail_steps WHERE trail_id = $1 ORDER BY day, id`
+	stepRows, err := r.db.QueryContext(ctx, stepsQuery, trail.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -173,16 +185,22 @@ func (r *EducationalTrailRepository) GetByRoadmapItemID(roadmapItemID int64) (*m
 	trail.Steps = make([]models.EducationalTrailStep, 0)
 	for stepRows.Next() {
 		var step models.EducationalTrailStep
-		err := stepRows.Scan(&step.ID, &step.TrailID, &step.Day, &step.Title, &step.Description, &step.CreatedAt)
+		err := stepRows.Scan(&step.ID, &step.TrailID, &step.Day, &step.ScheduledDate, &step.Title, &step.Description, &step.CreatedAt)
 		if err != nil {
 			stepRows.Close()
 			return nil, err
 		}
 
 		// Buscar atividades
-		activitiesQuery := `SELECT id, step_id, activity_type, resource_id, title, description, duration, url, progress, completed, created_at, updated_at 
-		                   FROM educational_trail_activities WHERE step_id = $1 ORDER BY id`
-		activityRows, err := r.db.Query(activitiesQuery, step.ID)
+		activitiesQuery := `SELECT a.id, a.step_id, a.activity_type, a.resource_id, a.title, a.description, a.duration, a.url, a.progress, a.completed,
+		                          a.completed_at, COALESCE(SUM(ss.minutes), 0), MAX(ss.started_at) FILTER (WHERE ss.ended_at IS NULL),
+		                          a.created_at, a.updated_at 
+		                   FROM educational_trail_activities a
+		                   LEFT JOIN study_sessions ss ON ss.activity_id = a.id
+		                   WHERE a.step_id = $1
+		                   GROUP BY a.id
+		                   ORDER BY a.id`
+		activityRows, err := r.db.QueryContext(ctx, activitiesQuery, step.ID)
 		if err != nil {
 			stepRows.Close()
 			return nil, err
@@ -193,7 +211,8 @@ func (r *EducationalTrailRepository) GetByRoadmapItemID(roadmapItemID int64) (*m
 			var activity models.TrailActivity
 			err := activityRows.Scan(&activity.ID, &activity.StepID, &activity.Type, &activity.ResourceID,
 				&activity.Title, &activity.Description, &activity.Duration, &activity.URL, &activity.Progress,
-				&activity.Completed, &activity.CreatedAt, &activity.UpdatedAt)
+				&activity.Completed, &activity.CompletedAt, &activity.SpentMinutes, &activity.TimerStartedAt,
+				&activity.CreatedAt, &activity.UpdatedAt)
 			if err != nil {
 				activityRows.Close()
 				stepRows.Close()
@@ -202,7 +221,7 @@ func (r *EducationalTrailRepository) GetByRoadmapItemID(roadmapItemID int64) (*m
 
 			// Buscar capítulos da atividade
 			chaptersQuery := `SELECT chapter_title FROM educational_trail_activity_chapters WHERE activity_id = $1 ORDER BY id`
-			chapterRows, err := r.db.Query(chaptersQuery, activity.ID)
+			chapterRows, err := r.db.QueryContext(ctx, chaptersQuery, activity.ID)
 			if err != nil {
 				activityRows.Close()
 				stepRows.Close()
@@ -232,17 +251,110 @@ func (r *EducationalTrailRepository) GetByRoadmapItemID(roadmapItemID int64) (*m
 	return &trail, nil
 }
 
-func (r *EducationalTrailRepository) UpdateActivityCompleted(activityID int64, completed bool) error {
-	query := `UPDATE educational_trail_activities SET completed = $1, updated_at = $2 WHERE id = $3`
-	_, err := r.db.Exec(query, completed, time.Now(), activityID)
-	return err
+// UpdateActivityCompleted marca ou desmarca a atividade. Quando ela passa a concluída, o
+// momento da conclusão é gravado, um cronômetro em andamento nela é parado e o evento
+// trail_activity.checked é registrado na mesma transação
+func (r *EducationalTrailRepository) UpdateActivityCompleted(ctx context.Context, activityID int64, completed bool) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var wasCompleted bool
+	event := models.TrailActivityCheckedData{ActivityID: activityID}
+	err = tx.QueryRowContext(ctx, `SELECT COALESCE(a.completed, FALSE), a.title, s.id, t.id, t.roadmap_item_id
+	          FROM educational_trail_activities a
+	          JOIN educational_trail_steps s ON s.id = a.step_id
+	          JOIN educational_trails t ON t.id = s.trail_id
+	          WHERE a.id = $1
+	          FOR UPDATE OF a`, activityID).Scan(&wasCompleted, &event.Title, &event.StepID, &event.TrailID, &event.RoadmapItemID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return apperrors.NotFound("atividade não encontrada")
+		}
+		return err
+	}
+	before, err := auditSnapshot(ctx, tx, models.AuditEntityTrailActivity, activityID)
+	if err != nil {
+		return err
+	}
+
+	now := r.clock.Now()
+	query := `UPDATE educational_trail_activities
+	          SET completed = $1, updated_at = $2,
+	              completed_at = CASE WHEN NOT $1 THEN NULL ELSE COALESCE(completed_at, $2) END
+	          WHERE id = $3`
+	if _, err := tx.ExecContext(ctx, query, completed, now, activityID); err != nil {
+		return err
+	}
+	if err := recordAudit(ctx, tx, models.AuditEntityTrailActivity, activityID, models.AuditActionUpdate, before); err != nil {
+		return err
+	}
+
+	if completed && !wasCompleted {
+		if err := stopStudyTimer(ctx, tx, activityID, now); err != nil {
+			return err
+		}
+		if err := enqueueEvent(ctx, tx, models.EventTrailActivityChecked, event); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UpdateSchedule grava, em uma única transação, a data de início da trilha e as datas
+// agendadas das etapas informadas
+func (r *EducationalTrailRepository) UpdateSchedule(ctx context.Context, trailID int64, startDate time.Time, steps []models.EducationalTrailStep) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `UPDATE educational_trails SET start_date = $1, updated_at = $2 WHERE id = $3`,
+		startDate, r.clock.Now(), trailID)
+	if err != nil {
+		return err
+	}
+	if err := requireRowsAffected(result, apperrors.NotFound("trilha educacional não encontrada")); err != nil {
+		return err
+	}
+
+	query := `UPDATE educational_trail_steps SET scheduled_date = $1 WHERE id = $2 AND trail_id = $3`
+	for _, step := range steps {
+		if _, err := tx.ExecContext(ctx, query, step.ScheduledDate, step.ID, trailID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
 // DeleteByRoadmapItemID deleta uma trilha educacional e todos os dados relacionados
-// O CASCADE no banco de dados garante que steps, activities, resources e chapters sejam deletados automaticamente
-func (r *EducationalTrailRepository) DeleteByRoadmapItemID(roadmapItemID int64) error {
+// O CASCADE no banco de dados garante que steps, activities, resources e chapters sejam deletados automaticamente.
+// A exclusão de cada atividade é registrada na auditoria
+func (r *EducationalTrailRepository) DeleteByRoadmapItemID(ctx context.Context, roadmapItemID int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	activities, err := auditSnapshots(ctx, tx, models.AuditEntityTrailActivity,
+		`SELECT a.id
+		 FROM educational_trail_activities a
+		 JOIN educational_trail_steps s ON s.id = a.step_id
+		 JOIN educational_trails t ON t.id = s.trail_id
+		 WHERE t.roadmap_item_id = $1
+		 ORDER BY a.id`, roadmapItemID)
+	if err != nil {
+		return err
+	}
+
 	query := `DELETE FROM educational_trails WHERE roadmap_item_id = $1`
-	result, err := r.db.Exec(query, roadmapItemID)
+	result, err := tx.ExecContext(ctx, query, roadmapItemID)
 	if err != nil {
 		return err
 	}
@@ -254,10 +366,14 @@ func (r *EducationalTrailRepository) DeleteByRoadmapItemID(roadmapItemID int64)
 	}
 	
 	if rowsAffected == 0 {
-		return fmt.Errorf("trilha educacional não encontrada para roadmap_item_id %d", roadmapItemID)
+		return apperrors.NotFound("trilha educacional não encontrada para roadmap_item_id %d", roadmapItemID)
+	}
+
+	if err := recordAudits(ctx, tx, models.AuditEntityTrailActivity, models.AuditActionDelete, activities); err != nil {
+		return err
 	}
 	
-	return nil
+	return tx.Commit()
 }
 
 // Helper para converter de spellbook.EducationalTrailResponse para models.EducationalTrail
@@ -1,9 +1,11 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"time"
 
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
 	"github.com/conquista-ai/conquista-ai/internal/models"
 )
 
@@ -15,11 +17,27 @@ func NewKeyResultRepository(db *sql.DB) *KeyResultRepository {
 	return &KeyResultRepository{db: db}
 }
 
-func (r *KeyResultRepository) Create(kr *models.KeyResult) error {
-	query := `INSERT INTO key_results (okr_id, title, completed, expected_completion_date, created_at, updated_at) 
-	          VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+func (r *KeyResultRepository) Create(ctx context.Context, kr *models.KeyResult) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 
-	now := time.Now()
+	if err := insertKeyResult(ctx, tx, kr, time.Now()); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// insertKeyResult grava o Key Result (com a dificuldade padrão quando não informada) e
+// registra a auditoria da criação
+func insertKeyResult(ctx context.Context, tx *sql.Tx, kr *models.KeyResult, now time.Time) error {
+	query := `INSERT INTO key_results (okr_id, title, completed, difficulty, expected_completion_date, due_date_computed, created_at, updated_at) 
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
+
+	kr.Difficulty = kr.EffectiveDifficulty()
 	kr.CreatedAt = now
 	kr.UpdatedAt = now
 
@@ -28,19 +46,19 @@ func (r *KeyResultRepository) Create(kr *models.KeyResult) error {
 		expectedCompletionDateSQL = sql.NullTime{Time: *kr.ExpectedCompletionDate, Valid: true}
 	}
 
-	err := r.db.QueryRow(query, kr.OKRID, kr.Title, kr.Completed, expectedCompletionDateSQL, kr.CreatedAt, kr.UpdatedAt).Scan(&kr.ID)
+	err := tx.QueryRowContext(ctx, query, kr.OKRID, kr.Title, kr.Completed, kr.Difficulty, expectedCompletionDateSQL, kr.DueDateComputed, kr.CreatedAt, kr.UpdatedAt).Scan(&kr.ID)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return recordAudit(ctx, tx, models.AuditEntityKeyResult, kr.ID, models.AuditActionCreate, nil)
 }
 
-func (r *KeyResultRepository) GetByOKRID(okrID int64) ([]models.KeyResult, error) {
-	query := `SELECT id, okr_id, title, completed, expected_completion_date, created_at, updated_at 
-	          FROM key_results WHERE okr_id = $1 ORDER BY created_at ASC`
+func (r *KeyResultRepository) GetByOKRID(ctx context.Context, okrID int64) ([]models.KeyResult, error) {
+	query := `SELECT id, okr_id, title, completed, difficulty, expected_completion_date, due_date_computed, created_at, updated_at 
+	          FROM key_results WHERE okr_id = $1 AND deleted_at IS NULL ORDER BY created_at ASC`
 
-	rows, err := r.db.Query(query, okrID)
+	rows, err := r.db.QueryContext(ctx, query, okrID)
 	if err != nil {
 		return []models.KeyResult{}, err
 	}
@@ -50,7 +68,7 @@ func (r *KeyResultRepository) GetByOKRID(okrID int64) ([]models.KeyResult, error
 	for rows.Next() {
 		var kr models.KeyResult
 		var expectedCompletionDate sql.NullTime
-		if err := rows.Scan(&kr.ID, &kr.OKRID, &kr.Title, &kr.Completed, &expectedCompletionDate, &kr.CreatedAt, &kr.UpdatedAt); err != nil {
+		if err := rows.Scan(&kr.ID, &kr.OKRID, &kr.Title, &kr.Completed, &kr.Difficulty, &expectedCompletionDate, &kr.DueDateComputed, &kr.CreatedAt, &kr.UpdatedAt); err != nil {
 			return []models.KeyResult{}, err
 		}
 		if expectedCompletionDate.Valid {
@@ -66,13 +84,13 @@ func (r *KeyResultRepository) GetByOKRID(okrID int64) ([]models.KeyResult, error
 	return keyResults, nil
 }
 
-func (r *KeyResultRepository) GetByID(id int64) (*models.KeyResult, error) {
-	query := `SELECT id, okr_id, title, completed, expected_completion_date, created_at, updated_at 
-	          FROM key_results WHERE id = $1`
+func (r *KeyResultRepository) GetByID(ctx context.Context, id int64) (*models.KeyResult, error) {
+	query := `SELECT id, okr_id, title, completed, difficulty, expected_completion_date, due_date_computed, created_at, updated_at 
+	          FROM key_results WHERE id = $1 AND deleted_at IS NULL`
 
 	var kr models.KeyResult
 	var expectedCompletionDate sql.NullTime
-	err := r.db.QueryRow(query, id).Scan(&kr.ID, &kr.OKRID, &kr.Title, &kr.Completed, &expectedCompletionDate, &kr.CreatedAt, &kr.UpdatedAt)
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&kr.ID, &kr.OKRID, &kr.Title, &kr.Completed, &kr.Difficulty, &expectedCompletionDate, &kr.DueDateComputed, &kr.CreatedAt, &kr.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -87,38 +105,186 @@ func (r *KeyResultRepository) GetByID(id int64) (*models.KeyResult, error) {
 	return &kr, nil
 }
 
-func (r *KeyResultRepository) Update(kr *models.KeyResult) error {
-	query := `UPDATE key_results SET title = $1, completed = $2, updated_at = $3 WHERE id = $4`
+// Update grava o Key Result. Quando ele passa a concluído, o evento key_result.completed
+// é registrado na mesma transação
+func (r *KeyResultRepository) Update(ctx context.Context, kr *models.KeyResult) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var wasCompleted bool
+	err = tx.QueryRowContext(ctx, `SELECT completed FROM key_results WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, kr.ID).Scan(&wasCompleted)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return apperrors.NotFound("Key Result não encontrado")
+		}
+		return err
+	}
+	before, err := auditSnapshot(ctx, tx, models.AuditEntityKeyResult, kr.ID)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE key_results SET title = $1, completed = $2, difficulty = $3, updated_at = $4 WHERE id = $5`
 
 	kr.UpdatedAt = time.Now()
-	_, err := r.db.Exec(query, kr.Title, kr.Completed, kr.UpdatedAt, kr.ID)
-	return err
+	kr.Difficulty = kr.EffectiveDifficulty()
+	if _, err := tx.ExecContext(ctx, query, kr.Title, kr.Completed, kr.Difficulty, kr.UpdatedAt, kr.ID); err != nil {
+		return err
+	}
+
+	if err := recordAudit(ctx, tx, models.AuditEntityKeyResult, kr.ID, models.AuditActionUpdate, before); err != nil {
+		return err
+	}
+	if kr.Completed && !wasCompleted {
+		if err := enqueueEvent(ctx, tx, models.EventKeyResultCompleted, kr); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
-func (r *KeyResultRepository) Delete(id int64) error {
-	query := `DELETE FROM key_results WHERE id = $1`
-	_, err := r.db.Exec(query, id)
-	return err
+// UpdateDueDates grava, em uma única transação, as datas esperadas calculadas pelo
+// planejamento para os Key Results informados
+func (r *KeyResultRepository) UpdateDueDates(ctx context.Context, keyResults []models.KeyResult) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE key_results SET expected_completion_date = $1, due_date_computed = TRUE, updated_at = $2 WHERE id = $3`
+	now := time.Now()
+	for i := range keyResults {
+		keyResults[i].DueDateComputed = true
+		keyResults[i].UpdatedAt = now
+		before, err := auditSnapshot(ctx, tx, models.AuditEntityKeyResult, keyResults[i].ID)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, query, keyResults[i].ExpectedCompletionDate, now, keyResults[i].ID); err != nil {
+			return err
+		}
+		if before != nil {
+			if err := recordAudit(ctx, tx, models.AuditEntityKeyResult, keyResults[i].ID, models.AuditActionUpdate, before); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
 }
 
-func (r *KeyResultRepository) CreateBatch(keyResults []models.KeyResult) error {
-	query := `INSERT INTO key_results (okr_id, title, completed, created_at, updated_at) 
-	          VALUES ($1, $2, $3, $4, $5) RETURNING id`
+// Delete move o Key Result para a lixeira junto com seu roadmap ativo, que recebe o mesmo
+// deleted_at
+func (r *KeyResultRepository) Delete(ctx context.Context, id int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := auditSnapshot(ctx, tx, models.AuditEntityKeyResult, id)
+	if err != nil {
+		return err
+	}
+	if before == nil || inTrash(before) {
+		return apperrors.NotFound("Key Result não encontrado")
+	}
+
+	now := time.Now()
+	if _, err := tx.ExecContext(ctx, `UPDATE key_results SET deleted_at = $1 WHERE id = $2`, now, id); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE roadmaps SET deleted_at = $1 WHERE key_result_id = $2 AND deleted_at IS NULL`, now, id); err != nil {
+		return err
+	}
+
+	if err := recordAudit(ctx, tx, models.AuditEntityKeyResult, id, models.AuditActionDelete, before); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Restore tira o Key Result da lixeira junto com o roadmap excluído com ele. Um Key
+// Result cujo OKR está na lixeira só volta com a restauração do OKR
+func (r *KeyResultRepository) Restore(ctx context.Context, id int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := auditSnapshot(ctx, tx, models.AuditEntityKeyResult, id)
+	if err != nil {
+		return err
+	}
+	if before == nil || !inTrash(before) {
+		return apperrors.NotFound("Key Result não encontrado na lixeira")
+	}
+
+	var okrInTrash bool
+	err = tx.QueryRowContext(ctx, `SELECT o.deleted_at IS NOT NULL FROM key_results kr JOIN okrs o ON o.id = kr.okr_id WHERE kr.id = $1`, id).Scan(&okrInTrash)
+	if err != nil {
+		return err
+	}
+	if okrInTrash {
+		return apperrors.Conflict("o OKR deste Key Result está na lixeira; restaure o OKR")
+	}
+
+	queries := []string{
+		`UPDATE roadmaps r SET deleted_at = NULL
+		 FROM key_results kr
+		 WHERE kr.id = r.key_result_id AND kr.id = $1 AND r.deleted_at = kr.deleted_at`,
+		`UPDATE key_results SET deleted_at = NULL WHERE id = $1`,
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query, id); err != nil {
+			return err
+		}
+	}
+
+	if err := recordAudit(ctx, tx, models.AuditEntityKeyResult, id, models.AuditActionRestore, before); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// CreateBatch grava, em uma única transação, os Key Results gerados para um OKR
+func (r *KeyResultRepository) CreateBatch(ctx context.Context, keyResults []models.KeyResult) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO key_results (okr_id, title, completed, difficulty, created_at, updated_at) 
+	          VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
 
 	now := time.Now()
 	for i := range keyResults {
 		keyResults[i].CreatedAt = now
 		keyResults[i].UpdatedAt = now
+		keyResults[i].Difficulty = keyResults[i].EffectiveDifficulty()
 
-		err := r.db.QueryRow(query, keyResults[i].OKRID, keyResults[i].Title,
-			keyResults[i].Completed, keyResults[i].CreatedAt, keyResults[i].UpdatedAt).
+		err := tx.QueryRowContext(ctx, query, keyResults[i].OKRID, keyResults[i].Title,
+			keyResults[i].Completed, keyResults[i].Difficulty, keyResults[i].CreatedAt, keyResults[i].UpdatedAt).
 			Scan(&keyResults[i].ID)
 		if err != nil {
 			return err
 		}
+
+		if err := recordAudit(ctx, tx, models.AuditEntityKeyResult, keyResults[i].ID, models.AuditActionCreate, nil); err != nil {
+			return err
+		}
 	}
 
-	return nil
+	return tx.Commit()
 }
 
 // KeyResultWithOKR representa um Key Result com informações do OKR
@@ -128,46 +294,133 @@ type KeyResultWithOKR struct {
 	OKRCompletionDate  *time.Time
 }
 
-func (r *KeyResultRepository) GetAllWithOKR() ([]KeyResultWithOKR, error) {
+var keyResultSortFields = map[string]sortField{
+	"expected_completion_date": {expr: "COALESCE(kr.expected_completion_date, DATE '9999-12-31')", cast: "date"},
+	"created_at":               {expr: "kr.created_at", cast: "timestamp"},
+	"title":                    {expr: "kr.title", cast: "text"},
+}
+
+// keyResultConditions monta os filtros de Key Result sobre kr (key_results) e
+// o (okrs), compartilhados pela listagem e pela exportação
+func keyResultConditions(filter models.KeyResultFilter) *sqlBuilder {
+	b := &sqlBuilder{}
+	b.where("kr.deleted_at IS NULL")
+	if filter.OKRID != nil {
+		b.where("kr.okr_id = " + b.arg(*filter.OKRID))
+	}
+	if filter.CategoryID != nil {
+		b.where("o.category_id = " + b.arg(*filter.CategoryID))
+	}
+	if filter.CycleStart != nil {
+		b.where("o.completion_date >= " + b.arg(*filter.CycleStart))
+	}
+	if filter.CycleEnd != nil {
+		b.where("o.completion_date < " + b.arg(*filter.CycleEnd))
+	}
+	if filter.DueBefore != nil {
+		b.where("kr.expected_completion_date <= " + b.arg(*filter.DueBefore))
+	}
+	if filter.DueAfter != nil {
+		b.where("kr.expected_completion_date >= " + b.arg(*filter.DueAfter))
+	}
+	if filter.Completed != nil {
+		b.where("kr.completed = " + b.arg(*filter.Completed))
+	}
+	if filter.Archived != nil {
+		if *filter.Archived {
+			b.where("o.archived_at IS NOT NULL")
+		} else {
+			b.where("o.archived_at IS NULL")
+		}
+	}
+	if filter.Status != "" {
+		b.where("o.status = " + b.arg(filter.Status))
+	}
+	if filter.Search != "" {
+		b.where("kr.title ILIKE " + b.arg(escapeLike(filter.Search)))
+	}
+	return b
+}
+
+// ListWithOKR retorna uma página de Key Results com informações do OKR,
+// aplicando filtros, ordenação e paginação por cursor
+func (r *KeyResultRepository) ListWithOKR(ctx context.Context, filter models.KeyResultFilter) (*models.Page[KeyResultWithOKR], error) {
+	sort, err := resolveSort(filter.Sort, keyResultSortFields, "expected_completion_date")
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := sort.decodeCursor(filter.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	filter.Limit = pageLimit(filter.Limit)
+
+	b := keyResultConditions(filter)
+
+	page := &models.Page[KeyResultWithOKR]{Items: make([]KeyResultWithOKR, 0), Limit: filter.Limit}
+
+	countBuilder := b.clone()
+	countQuery := `SELECT COUNT(*) FROM key_results kr INNER JOIN okrs o ON kr.okr_id = o.id` + countBuilder.whereClause()
+	if err := r.db.QueryRowContext(ctx, countQuery, countBuilder.args...).Scan(&page.Total); err != nil {
+		return nil, err
+	}
+
+	if cursor != nil {
+		sort.applyCursor(b, "kr.id", cursor)
+	}
+
 	query := `SELECT 
 		kr.id, 
 		kr.okr_id, 
 		kr.title, 
 		kr.completed, 
+		kr.difficulty, 
 		kr.expected_completion_date, 
+		kr.due_date_computed, 
 		kr.created_at, 
 		kr.updated_at,
 		o.objective as okr_title,
-		o.completion_date as okr_completion_date
+		o.completion_date as okr_completion_date,
+		` + sort.field.expr + `::text
 	FROM key_results kr
-	INNER JOIN okrs o ON kr.okr_id = o.id
-	ORDER BY kr.expected_completion_date ASC NULLS LAST, kr.created_at ASC`
+	INNER JOIN okrs o ON kr.okr_id = o.id` +
+		b.whereClause() + sort.orderBy("kr.id") + " LIMIT " + b.arg(filter.Limit+1)
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query, b.args...)
 	if err != nil {
-		return []KeyResultWithOKR{}, err
+		return nil, err
 	}
 	defer rows.Close()
 
-	keyResults := make([]KeyResultWithOKR, 0)
+	var lastSortValue string
 	for rows.Next() {
 		var krw KeyResultWithOKR
 		var expectedCompletionDate sql.NullTime
 		var okrCompletionDate sql.NullTime
+		var sortValue string
 
 		err := rows.Scan(
 			&krw.KeyResult.ID,
 			&krw.KeyResult.OKRID,
 			&krw.KeyResult.Title,
 			&krw.KeyResult.Completed,
+			&krw.KeyResult.Difficulty,
 			&expectedCompletionDate,
+			&krw.KeyResult.DueDateComputed,
 			&krw.KeyResult.CreatedAt,
 			&krw.KeyResult.UpdatedAt,
 			&krw.OKRTitle,
 			&okrCompletionDate,
+			&sortValue,
 		)
 		if err != nil {
-			return []KeyResultWithOKR{}, err
+			return nil, err
+		}
+
+		if len(page.Items) == filter.Limit {
+			page.NextCursor = sort.cursor(lastSortValue, page.Items[len(page.Items)-1].KeyResult.ID)
+			break
 		}
 
 		if expectedCompletionDate.Valid {
@@ -178,12 +431,110 @@ func (r *KeyResultRepository) GetAllWithOKR() ([]KeyResultWithOKR, error) {
 			krw.OKRCompletionDate = &okrCompletionDate.Time
 		}
 
-		keyResults = append(keyResults, krw)
+		page.Items = append(page.Items, krw)
+		lastSortValue = sortValue
 	}
 
 	if err := rows.Err(); err != nil {
-		return []KeyResultWithOKR{}, err
+		return nil, err
 	}
 
-	return keyResults, nil
+	return page, nil
+}
+
+// KeyResultExportRow é uma linha da exportação de Key Results em planilha. Os
+// percentuais ficam nulos quando o Key Result não tem roadmap ou trilhas
+type KeyResultExportRow struct {
+	Objective              string
+	Category               string
+	OKRStatus              string
+	Title                  string
+	Completed              bool
+	ExpectedCompletionDate *time.Time
+	RoadmapProgress        *int
+	TrailProgress          *int
+}
+
+// EachExportRow percorre os Key Results que atendem ao filtro, na ordem pedida e sem
+// paginação, chamando fn para cada linha à medida que é lida do banco
+func (r *KeyResultRepository) EachExportRow(ctx context.Context, filter models.KeyResultFilter, fn func(KeyResultExportRow) error) error {
+	sort, err := resolveSort(filter.Sort, keyResultSortFields, "expected_completion_date")
+	if err != nil {
+		return err
+	}
+
+	b := keyResultConditions(filter)
+	query := `SELECT
+		o.objective,
+		c.name,
+		o.status,
+		kr.title,
+		kr.completed,
+		kr.expected_completion_date,
+		rm.items,
+		rm.completed_items,
+		tr.activities,
+		tr.completed_activities
+	FROM key_results kr
+	INNER JOIN okrs o ON kr.okr_id = o.id
+	INNER JOIN categories c ON o.category_id = c.id
+	CROSS JOIN LATERAL (
+		SELECT COUNT(ri.id), COUNT(ri.id) FILTER (WHERE ri.completed)
+		FROM roadmaps r
+		JOIN roadmap_categories rc ON rc.roadmap_id = r.id
+		JOIN roadmap_items ri ON ri.category_id = rc.id
+		WHERE r.key_result_id = kr.id AND r.deleted_at IS NULL
+	) rm(items, completed_items)
+	CROSS JOIN LATERAL (
+		SELECT COUNT(a.id), COUNT(a.id) FILTER (WHERE a.completed)
+		FROM roadmaps r
+		JOIN roadmap_categories rc ON rc.roadmap_id = r.id
+		JOIN roadmap_items ri ON ri.category_id = rc.id
+		JOIN educational_trails t ON t.roadmap_item_id = ri.id
+		JOIN educational_trail_steps s ON s.trail_id = t.id
+		JOIN educational_trail_activities a ON a.step_id = s.id
+		WHERE r.key_result_id = kr.id AND r.deleted_at IS NULL
+	) tr(activities, completed_activities)` +
+		b.whereClause() + sort.orderBy("kr.id")
+
+	rows, err := r.db.QueryContext(ctx, query, b.args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row KeyResultExportRow
+		var items, completedItems, activities, completedActivities int
+		if err := rows.Scan(
+			&row.Objective,
+			&row.Category,
+			&row.OKRStatus,
+			&row.Title,
+			&row.Completed,
+			&row.ExpectedCompletionDate,
+			&items,
+			&completedItems,
+			&activities,
+			&completedActivities,
+		); err != nil {
+			return err
+		}
+		row.RoadmapProgress = percentage(completedItems, items)
+		row.TrailProgress = percentage(completedActivities, activities)
+
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// percentage arredonda done/total para um percentual inteiro; nulo quando não há total
+func percentage(done, total int) *int {
+	if total == 0 {
+		return nil
+	}
+	p := (done*100 + total/2) / total
+	return &p
 }
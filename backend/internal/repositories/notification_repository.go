@@ -0,0 +1,284 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/lib/pq"
+)
+
+type NotificationRepository struct {
+	db *sql.DB
+}
+
+func NewNotificationRepository(db *sql.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+func (r *NotificationRepository) GetPreferences(ctx context.Context) (*models.NotificationPreferences, error) {
+	query := `SELECT inbox_enabled, email_enabled, email_address, webhook_enabled, webhook_url, kinds,
+	                 quiet_hours_start, quiet_hours_end, weekly_checkin_weekday, timezone, updated_at
+	          FROM notification_preferences WHERE id = 1`
+
+	var prefs models.NotificationPreferences
+	var kinds pq.StringArray
+	var quietStart, quietEnd sql.NullString
+	err := r.db.QueryRowContext(ctx, query).Scan(&prefs.InboxEnabled, &prefs.EmailEnabled, &prefs.EmailAddress,
+		&prefs.WebhookEnabled, &prefs.WebhookURL, &kinds, &quietStart, &quietEnd,
+		&prefs.WeeklyCheckInWeekday, &prefs.Timezone, &prefs.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	prefs.Kinds = []string(kinds)
+	if quietStart.Valid {
+		prefs.QuietHoursStart = &quietStart.String
+	}
+	if quietEnd.Valid {
+		prefs.QuietHoursEnd = &quietEnd.String
+	}
+
+	return &prefs, nil
+}
+
+func (r *NotificationRepository) UpdatePreferences(ctx context.Context, prefs *models.NotificationPreferences) error {
+	prefs.UpdatedAt = time.Now()
+
+	query := `INSERT INTO notification_preferences (id, inbox_enabled, email_enabled, email_address, webhook_enabled,
+	                 webhook_url, kinds, quiet_hours_start, quiet_hours_end, weekly_checkin_weekday, timezone, updated_at)
+	          VALUES (1, $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	          ON CONFLICT (id) DO UPDATE SET inbox_enabled = EXCLUDED.inbox_enabled,
+	                 email_enabled = EXCLUDED.email_enabled, email_address = EXCLUDED.email_address,
+	                 webhook_enabled = EXCLUDED.webhook_enabled, webhook_url = EXCLUDED.webhook_url,
+	                 kinds = EXCLUDED.kinds, quiet_hours_start = EXCLUDED.quiet_hours_start,
+	                 quiet_hours_end = EXCLUDED.quiet_hours_end,
+	                 weekly_checkin_weekday = EXCLUDED.weekly_checkin_weekday, timezone = EXCLUDED.timezone,
+	                 updated_at = EXCLUDED.updated_at`
+	_, err := r.db.ExecContext(ctx, query, prefs.InboxEnabled, prefs.EmailEnabled, prefs.EmailAddress,
+		prefs.WebhookEnabled, prefs.WebhookURL, pq.StringArray(prefs.Kinds), prefs.QuietHoursStart,
+		prefs.QuietHoursEnd, prefs.WeeklyCheckInWeekday, prefs.Timezone, prefs.UpdatedAt)
+	return err
+}
+
+// Enqueue agenda a entrega do lembrete em cada canal. Lembretes já enfileirados (mesma
+// chave de deduplicação e canal) são ignorados. Retorna quantas entregas foram criadas
+func (r *NotificationRepository) Enqueue(ctx context.Context, n models.Notification, channels []string, at time.Time) (int, error) {
+	query := `INSERT INTO notification_deliveries (dedup_key, channel, kind, title, body, next_attempt_at, created_at)
+	          VALUES ($1, $2, $3, $4, $5, $6, $6)
+	          ON CONFLICT (dedup_key, channel) DO NOTHING`
+
+	created := 0
+	for _, channel := range channels {
+		result, err := r.db.ExecContext(ctx, query, n.DedupKey, channel, n.Kind, n.Title, n.Body, at)
+		if err != nil {
+			return created, err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return created, err
+		}
+		created += int(rowsAffected)
+	}
+
+	return created, nil
+}
+
+// DueDeliveries retorna as entregas pendentes cuja próxima tentativa já chegou
+func (r *NotificationRepository) DueDeliveries(ctx context.Context, now time.Time, limit int) ([]models.NotificationDelivery, error) {
+	query := `SELECT id, channel, status, attempts, next_attempt_at, COALESCE(last_error, ''),
+	                 dedup_key, kind, title, body, created_at
+	          FROM notification_deliveries
+	          WHERE status = 'pending' AND next_attempt_at <= $1
+	          ORDER BY next_attempt_at, id
+	          LIMIT $2`
+	rows, err := r.db.QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]models.NotificationDelivery, 0)
+	for rows.Next() {
+		var d models.NotificationDelivery
+		if err := rows.Scan(&d.ID, &d.Channel, &d.Status, &d.Attempts, &d.NextAttemptAt, &d.LastError,
+			&d.Notification.DedupKey, &d.Notification.Kind, &d.Notification.Title, &d.Notification.Body,
+			&d.Notification.CreatedAt); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, rows.Err()
+}
+
+func (r *NotificationRepository) MarkSent(ctx context.Context, id int64) error {
+	query := `UPDATE notification_deliveries SET status = 'sent', attempts = attempts + 1, sent_at = $1, last_error = NULL
+	          WHERE id = $2`
+	_, err := r.db.ExecContext(ctx, query, time.Now(), id)
+	return err
+}
+
+// MarkFailed registra uma tentativa com erro. Com nextAttempt nulo a entrega é
+// abandonada (status failed); caso contrário continua pendente até nextAttempt
+func (r *NotificationRepository) MarkFailed(ctx context.Context, id int64, reason string, nextAttempt *time.Time) error {
+	if nextAttempt == nil {
+		query := `UPDATE notification_deliveries SET status = 'failed', attempts = attempts + 1, last_error = $1 WHERE id = $2`
+		_, err := r.db.ExecContext(ctx, query, reason, id)
+		return err
+	}
+
+	query := `UPDATE notification_deliveries SET attempts = attempts + 1, last_error = $1, next_attempt_at = $2 WHERE id = $3`
+	_, err := r.db.ExecContext(ctx, query, reason, *nextAttempt, id)
+	return err
+}
+
+// CreateInboxItem grava o lembrete na caixa de entrada do aplicativo
+func (r *NotificationRepository) CreateInboxItem(ctx context.Context, n *models.Notification) error {
+	n.CreatedAt = time.Now()
+	query := `INSERT INTO notifications (kind, title, body, created_at) VALUES ($1, $2, $3, $4) RETURNING id`
+	return r.db.QueryRowContext(ctx, query, n.Kind, n.Title, n.Body, n.CreatedAt).Scan(&n.ID)
+}
+
+// Inbox retorna os lembretes mais recentes da caixa de entrada e o total de não lidos
+func (r *NotificationRepository) Inbox(ctx context.Context, unreadOnly bool, limit int) (*models.NotificationInbox, error) {
+	query := `SELECT id, kind, title, body, created_at, read_at FROM notifications
+	          WHERE ($1 = FALSE OR read_at IS NULL)
+	          ORDER BY created_at DESC, id DESC
+	          LIMIT $2`
+	rows, err := r.db.QueryContext(ctx, query, unreadOnly, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	inbox := &models.NotificationInbox{Items: make([]models.Notification, 0)}
+	for rows.Next() {
+		var n models.Notification
+		if err := rows.Scan(&n.ID, &n.Kind, &n.Title, &n.Body, &n.CreatedAt, &n.ReadAt); err != nil {
+			return nil, err
+		}
+		inbox.Items = append(inbox.Items, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	err = r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM notifications WHERE read_at IS NULL`).Scan(&inbox.UnreadCount)
+	if err != nil {
+		return nil, err
+	}
+
+	return inbox, nil
+}
+
+func (r *NotificationRepository) MarkRead(ctx context.Context, id int64) error {
+	query := `UPDATE notifications SET read_at = COALESCE(read_at, $1) WHERE id = $2`
+	result, err := r.db.ExecContext(ctx, query, time.Now(), id)
+	if err != nil {
+		return err
+	}
+	return requireRowsAffected(result, apperrors.NotFound("notificação não encontrada"))
+}
+
+func (r *NotificationRepository) MarkAllRead(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE notifications SET read_at = $1 WHERE read_at IS NULL`, time.Now())
+	return err
+}
+
+// ReminderTrail resume as etapas pendentes de uma trilha em uma data (ou antes dela)
+type ReminderTrail struct {
+	TrailID           int64
+	RoadmapItemID     int64
+	Topic             string
+	Steps             []string
+	PendingActivities int
+	// Data mais antiga entre as etapas consideradas
+	FirstDate time.Time
+}
+
+// ReminderKeyResult é um Key Result pendente com data esperada
+type ReminderKeyResult struct {
+	ID        int64
+	Title     string
+	Objective string
+	DueDate   time.Time
+}
+
+// TrailsScheduledOn retorna as trilhas com etapas pendentes agendadas para date
+func (r *NotificationRepository) TrailsScheduledOn(ctx context.Context, date time.Time) ([]ReminderTrail, error) {
+	return r.reminderTrails(ctx, `s.scheduled_date = $1::date`, date)
+}
+
+// OverdueTrails retorna as trilhas com etapas pendentes agendadas antes de today
+func (r *NotificationRepository) OverdueTrails(ctx context.Context, today time.Time) ([]ReminderTrail, error) {
+	return r.reminderTrails(ctx, `s.scheduled_date < $1::date`, today)
+}
+
+func (r *NotificationRepository) reminderTrails(ctx context.Context, condition string, date time.Time) ([]ReminderTrail, error) {
+	// Uma etapa está pendente enquanto tiver atividades não concluídas; trilhas de OKRs
+	// arquivados, pausados ou encerrados não geram lembretes
+	query := `SELECT t.id, t.roadmap_item_id, t.topic, ARRAY_AGG(DISTINCT s.title), COUNT(a.id), MIN(s.scheduled_date)
+	          FROM educational_trail_steps s
+	          JOIN educational_trails t ON t.id = s.trail_id
+	          JOIN educational_trail_activities a ON a.step_id = s.id AND NOT COALESCE(a.completed, FALSE)
+	          WHERE ` + condition + ` AND ` + activeRoadmapItem("t.roadmap_item_id") + `
+	            AND ` + trackedRoadmapItem("t.roadmap_item_id") + `
+	          GROUP BY t.id
+	          ORDER BY t.id`
+	rows, err := r.db.QueryContext(ctx, query, date.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	trails := make([]ReminderTrail, 0)
+	for rows.Next() {
+		var trail ReminderTrail
+		var steps pq.StringArray
+		if err := rows.Scan(&trail.TrailID, &trail.RoadmapItemID, &trail.Topic, &steps,
+			&trail.PendingActivities, &trail.FirstDate); err != nil {
+			return nil, err
+		}
+		trail.Steps = []string(steps)
+		trails = append(trails, trail)
+	}
+
+	return trails, rows.Err()
+}
+
+// KeyResultsDueOn retorna os Key Results pendentes com data esperada em date
+func (r *NotificationRepository) KeyResultsDueOn(ctx context.Context, date time.Time) ([]ReminderKeyResult, error) {
+	return r.reminderKeyResults(ctx, `kr.expected_completion_date = $1::date`, date)
+}
+
+// OverdueKeyResults retorna os Key Results pendentes com data esperada antes de today
+func (r *NotificationRepository) OverdueKeyResults(ctx context.Context, today time.Time) ([]ReminderKeyResult, error) {
+	return r.reminderKeyResults(ctx, `kr.expected_completion_date < $1::date`, today)
+}
+
+func (r *NotificationRepository) reminderKeyResults(ctx context.Context, condition string, date time.Time) ([]ReminderKeyResult, error) {
+	query := `SELECT kr.id, kr.title, o.objective, kr.expected_completion_date
+	          FROM key_results kr
+	          JOIN okrs o ON o.id = kr.okr_id
+	          WHERE NOT COALESCE(kr.completed, FALSE) AND kr.deleted_at IS NULL AND ` + trackedOKR("o") + ` AND ` + condition + `
+	          ORDER BY kr.expected_completion_date, kr.id`
+	rows, err := r.db.QueryContext(ctx, query, date.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keyResults := make([]ReminderKeyResult, 0)
+	for rows.Next() {
+		var kr ReminderKeyResult
+		if err := rows.Scan(&kr.ID, &kr.Title, &kr.Objective, &kr.DueDate); err != nil {
+			return nil, err
+		}
+		keyResults = append(keyResults, kr)
+	}
+
+	return keyResults, rows.Err()
+}
@@ -1,9 +1,13 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"strings"
 	"time"
 
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
 	"github.com/conquista-ai/conquista-ai/internal/models"
 )
 
@@ -15,30 +19,81 @@ func NewOKRRepository(db *sql.DB) *OKRRepository {
 	return &OKRRepository{db: db}
 }
 
-func (r *OKRRepository) Create(okr *models.OKR) error {
-	query := `INSERT INTO okrs (objective, category_id, completion_date, created_at, updated_at) 
-	          VALUES ($1, $2, $3, $4, $5) RETURNING id`
+func (r *OKRRepository) Create(ctx context.Context, okr *models.OKR) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertOKR(ctx, tx, okr, time.Now()); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Import grava os OKRs da planilha e seus Key Results em uma única transação, preenchendo
+// os IDs. Com dryRun a transação é desfeita ao final
+func (r *OKRRepository) Import(ctx context.Context, okrs []models.OKRImport, dryRun bool) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 
 	now := time.Now()
+	for i := range okrs {
+		if err := insertOKR(ctx, tx, &okrs[i].OKR, now); err != nil {
+			return err
+		}
+		for j := range okrs[i].KeyResults {
+			okrs[i].KeyResults[j].OKRID = okrs[i].OKR.ID
+			if err := insertKeyResult(ctx, tx, &okrs[i].KeyResults[j], now); err != nil {
+				return err
+			}
+		}
+	}
+
+	if dryRun {
+		return nil
+	}
+	return tx.Commit()
+}
+
+// insertOKR grava o OKR com o status inicial no histórico, auditoria e evento de criação
+func insertOKR(ctx context.Context, tx *sql.Tx, okr *models.OKR, now time.Time) error {
+	query := `INSERT INTO okrs (objective, category_id, completion_date, status, status_changed_at, created_at, updated_at) 
+	          VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+
 	okr.CreatedAt = now
 	okr.UpdatedAt = now
+	okr.StatusChangedAt = &now
 
-	err := r.db.QueryRow(query, okr.Objective, okr.CategoryID, okr.CompletionDate, okr.CreatedAt, okr.UpdatedAt).Scan(&okr.ID)
+	err := tx.QueryRowContext(ctx, query, okr.Objective, okr.CategoryID, okr.CompletionDate, okr.Status, okr.StatusChangedAt, okr.CreatedAt, okr.UpdatedAt).Scan(&okr.ID)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	if _, err := insertStatusChange(ctx, tx, okr.ID, nil, okr.Status, nil, now); err != nil {
+		return err
+	}
+
+	if err := recordAudit(ctx, tx, models.AuditEntityOKR, okr.ID, models.AuditActionCreate, nil); err != nil {
+		return err
+	}
+	return enqueueEvent(ctx, tx, models.EventOKRCreated, okr)
 }
 
-func (r *OKRRepository) GetAll() ([]models.OKR, error) {
-	query := `SELECT o.id, o.objective, o.category_id, o.completion_date, o.created_at, o.updated_at,
+func (r *OKRRepository) GetAll(ctx context.Context) ([]models.OKR, error) {
+	query := `SELECT o.id, o.objective, o.category_id, o.completion_date, o.archived_at, o.status, o.status_reason, o.status_changed_at, o.created_at, o.updated_at,
 	                 c.id, c.name, c.created_at, c.updated_at
 	          FROM okrs o
 	          LEFT JOIN categories c ON o.category_id = c.id
+	          WHERE o.deleted_at IS NULL
 	          ORDER BY o.created_at DESC`
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return []models.OKR{}, err
 	}
@@ -49,7 +104,7 @@ func (r *OKRRepository) GetAll() ([]models.OKR, error) {
 		var o models.OKR
 		var c models.Category
 		var completionDate sql.NullTime
-		if err := rows.Scan(&o.ID, &o.Objective, &o.CategoryID, &completionDate, &o.CreatedAt, &o.UpdatedAt,
+		if err := rows.Scan(&o.ID, &o.Objective, &o.CategoryID, &completionDate, &o.ArchivedAt, &o.Status, &o.StatusReason, &o.StatusChangedAt, &o.CreatedAt, &o.UpdatedAt,
 			&c.ID, &c.Name, &c.CreatedAt, &c.UpdatedAt); err != nil {
 			return []models.OKR{}, err
 		}
@@ -67,17 +122,17 @@ func (r *OKRRepository) GetAll() ([]models.OKR, error) {
 	return okrs, nil
 }
 
-func (r *OKRRepository) GetByID(id int64) (*models.OKR, error) {
-	query := `SELECT o.id, o.objective, o.category_id, o.completion_date, o.created_at, o.updated_at,
+func (r *OKRRepository) GetByID(ctx context.Context, id int64) (*models.OKR, error) {
+	query := `SELECT o.id, o.objective, o.category_id, o.completion_date, o.archived_at, o.status, o.status_reason, o.status_changed_at, o.created_at, o.updated_at,
 	                 c.id, c.name, c.created_at, c.updated_at
 	          FROM okrs o
 	          LEFT JOIN categories c ON o.category_id = c.id
-	          WHERE o.id = $1`
+	          WHERE o.id = $1 AND o.deleted_at IS NULL`
 
 	var o models.OKR
 	var c models.Category
 	var completionDate sql.NullTime
-	err := r.db.QueryRow(query, id).Scan(&o.ID, &o.Objective, &o.CategoryID, &completionDate, &o.CreatedAt, &o.UpdatedAt,
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&o.ID, &o.Objective, &o.CategoryID, &completionDate, &o.ArchivedAt, &o.Status, &o.StatusReason, &o.StatusChangedAt, &o.CreatedAt, &o.UpdatedAt,
 		&c.ID, &c.Name, &c.CreatedAt, &c.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -92,53 +147,397 @@ func (r *OKRRepository) GetByID(id int64) (*models.OKR, error) {
 	return &o, nil
 }
 
-func (r *OKRRepository) GetByCategoryID(categoryID int64) ([]models.OKR, error) {
-	query := `SELECT o.id, o.objective, o.category_id, o.completion_date, o.created_at, o.updated_at,
-	                 c.id, c.name, c.created_at, c.updated_at
+var okrSortFields = map[string]sortField{
+	"created_at":      {expr: "o.created_at", cast: "timestamp"},
+	"completion_date": {expr: "COALESCE(o.completion_date, DATE '9999-12-31')", cast: "date"},
+	"objective":       {expr: "o.objective", cast: "text"},
+}
+
+// okrCompletedCondition considera um OKR concluído quando possui Key Results e todos estão concluídos
+const okrCompletedCondition = `(EXISTS (SELECT 1 FROM key_results k WHERE k.okr_id = o.id AND k.deleted_at IS NULL)
+	AND NOT EXISTS (SELECT 1 FROM key_results k WHERE k.okr_id = o.id AND k.deleted_at IS NULL AND NOT k.completed))`
+
+// List retorna uma página de OKRs aplicando filtros, ordenação e paginação por cursor
+func (r *OKRRepository) List(ctx context.Context, filter models.OKRFilter) (*models.Page[models.OKR], error) {
+	sort, err := resolveSort(filter.Sort, okrSortFields, "-created_at")
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := sort.decodeCursor(filter.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	filter.Limit = pageLimit(filter.Limit)
+
+	b := &sqlBuilder{}
+	b.where("o.deleted_at IS NULL")
+	if filter.CategoryID != nil {
+		b.where("o.category_id = " + b.arg(*filter.CategoryID))
+	}
+	if filter.CycleStart != nil {
+		b.where("o.completion_date >= " + b.arg(*filter.CycleStart))
+	}
+	if filter.CycleEnd != nil {
+		b.where("o.completion_date < " + b.arg(*filter.CycleEnd))
+	}
+	if filter.DueBefore != nil {
+		b.where("o.completion_date <= " + b.arg(*filter.DueBefore))
+	}
+	if filter.DueAfter != nil {
+		b.where("o.completion_date >= " + b.arg(*filter.DueAfter))
+	}
+	if filter.Completed != nil {
+		if *filter.Completed {
+			b.where(okrCompletedCondition)
+		} else {
+			b.where("NOT " + okrCompletedCondition)
+		}
+	}
+	if filter.Archived != nil {
+		if *filter.Archived {
+			b.where("o.archived_at IS NOT NULL")
+		} else {
+			b.where("o.archived_at IS NULL")
+		}
+	}
+	if filter.Status != "" {
+		b.where("o.status = " + b.arg(filter.Status))
+	}
+	if filter.Search != "" {
+		b.where("o.objective ILIKE " + b.arg(escapeLike(filter.Search)))
+	}
+
+	page := &models.Page[models.OKR]{Items: make([]models.OKR, 0), Limit: filter.Limit}
+
+	countBuilder := b.clone()
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM okrs o`+countBuilder.whereClause(), countBuilder.args...).Scan(&page.Total); err != nil {
+		return nil, err
+	}
+
+	if cursor != nil {
+		sort.applyCursor(b, "o.id", cursor)
+	}
+
+	query := `SELECT o.id, o.objective, o.category_id, o.completion_date, o.archived_at, o.status, o.status_reason, o.status_changed_at, o.created_at, o.updated_at,
+	                 c.id, c.name, c.created_at, c.updated_at, ` + sort.field.expr + `::text
 	          FROM okrs o
-	          LEFT JOIN categories c ON o.category_id = c.id
-	          WHERE o.category_id = $1
-	          ORDER BY o.created_at DESC`
+	          LEFT JOIN categories c ON o.category_id = c.id` +
+		b.whereClause() + sort.orderBy("o.id") + " LIMIT " + b.arg(filter.Limit+1)
 
-	rows, err := r.db.Query(query, categoryID)
+	rows, err := r.db.QueryContext(ctx, query, b.args...)
 	if err != nil {
-		return []models.OKR{}, err
+		return nil, err
 	}
 	defer rows.Close()
 
-	okrs := make([]models.OKR, 0)
+	var lastSortValue string
 	for rows.Next() {
 		var o models.OKR
 		var c models.Category
 		var completionDate sql.NullTime
-		if err := rows.Scan(&o.ID, &o.Objective, &o.CategoryID, &completionDate, &o.CreatedAt, &o.UpdatedAt,
-			&c.ID, &c.Name, &c.CreatedAt, &c.UpdatedAt); err != nil {
-			return []models.OKR{}, err
+		var sortValue string
+		if err := rows.Scan(&o.ID, &o.Objective, &o.CategoryID, &completionDate, &o.ArchivedAt, &o.Status, &o.StatusReason, &o.StatusChangedAt, &o.CreatedAt, &o.UpdatedAt,
+			&c.ID, &c.Name, &c.CreatedAt, &c.UpdatedAt, &sortValue); err != nil {
+			return nil, err
+		}
+		if len(page.Items) == filter.Limit {
+			page.NextCursor = sort.cursor(lastSortValue, page.Items[len(page.Items)-1].ID)
+			break
 		}
 		if completionDate.Valid {
 			o.CompletionDate = &completionDate.Time
 		}
 		o.Category = &c
-		okrs = append(okrs, o)
+		page.Items = append(page.Items, o)
+		lastSortValue = sortValue
 	}
 
 	if err := rows.Err(); err != nil {
-		return []models.OKR{}, err
+		return nil, err
 	}
 
-	return okrs, nil
+	return page, nil
 }
 
-func (r *OKRRepository) Update(okr *models.OKR) error {
+func (r *OKRRepository) Update(ctx context.Context, okr *models.OKR) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := auditSnapshot(ctx, tx, models.AuditEntityOKR, okr.ID)
+	if err != nil {
+		return err
+	}
+	if before == nil || inTrash(before) {
+		return apperrors.NotFound("OKR não encontrado")
+	}
+
 	query := `UPDATE okrs SET objective = $1, category_id = $2, completion_date = $3, updated_at = $4 WHERE id = $5`
 
 	okr.UpdatedAt = time.Now()
-	_, err := r.db.Exec(query, okr.Objective, okr.CategoryID, okr.CompletionDate, okr.UpdatedAt, okr.ID)
-	return err
+	if _, err := tx.ExecContext(ctx, query, okr.Objective, okr.CategoryID, okr.CompletionDate, okr.UpdatedAt, okr.ID); err != nil {
+		return err
+	}
+
+	if err := recordAudit(ctx, tx, models.AuditEntityOKR, okr.ID, models.AuditActionUpdate, before); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete move o OKR para a lixeira junto com seus Key Results e roadmaps ainda ativos,
+// que recebem o mesmo deleted_at. Apenas a exclusão do OKR é registrada na auditoria
+func (r *OKRRepository) Delete(ctx context.Context, id int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := auditSnapshot(ctx, tx, models.AuditEntityOKR, id)
+	if err != nil {
+		return err
+	}
+	if before == nil || inTrash(before) {
+		return apperrors.NotFound("OKR não encontrado")
+	}
+
+	if err := trashOKR(ctx, tx, id, time.Now()); err != nil {
+		return err
+	}
+
+	if err := recordAudit(ctx, tx, models.AuditEntityOKR, id, models.AuditActionDelete, before); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func (r *OKRRepository) Delete(id int64) error {
-	query := `DELETE FROM okrs WHERE id = $1`
-	_, err := r.db.Exec(query, id)
-	return err
+// SetArchived arquiva (archived = true) ou desarquiva o OKR. Key Results e roadmaps
+// acompanham o OKR e não guardam estado próprio de arquivamento
+func (r *OKRRepository) SetArchived(ctx context.Context, id int64, archived bool) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := auditSnapshot(ctx, tx, models.AuditEntityOKR, id)
+	if err != nil {
+		return err
+	}
+	if before == nil || inTrash(before) {
+		return apperrors.NotFound("OKR não encontrado")
+	}
+	if isArchived(before) == archived {
+		if archived {
+			return apperrors.Conflict("OKR já está arquivado")
+		}
+		return apperrors.Conflict("OKR não está arquivado")
+	}
+
+	now := time.Now()
+	var archivedAt *time.Time
+	if archived {
+		archivedAt = &now
+	}
+	query := `UPDATE okrs SET archived_at = $1, updated_at = $2 WHERE id = $3`
+	if _, err := tx.ExecContext(ctx, query, archivedAt, now, id); err != nil {
+		return err
+	}
+
+	if err := recordAudit(ctx, tx, models.AuditEntityOKR, id, models.AuditActionUpdate, before); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// isArchived informa se o retrato de um OKR (auditSnapshot) está arquivado
+func isArchived(snapshot json.RawMessage) bool {
+	var row struct {
+		ArchivedAt *string `json:"archived_at"`
+	}
+	return json.Unmarshal(snapshot, &row) == nil && row.ArchivedAt != nil
+}
+
+// ChangeStatus move o OKR do status from para to. A transição já foi validada pelo
+// serviço; se o status mudou desde a leitura, a mudança é recusada com conflito
+func (r *OKRRepository) ChangeStatus(ctx context.Context, id int64, from, to string, reason *string) (*models.OKRStatusChange, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	before, err := auditSnapshot(ctx, tx, models.AuditEntityOKR, id)
+	if err != nil {
+		return nil, err
+	}
+	if before == nil || inTrash(before) {
+		return nil, apperrors.NotFound("OKR não encontrado")
+	}
+
+	now := time.Now()
+	query := `UPDATE okrs SET status = $1, status_reason = $2, status_changed_at = $3, updated_at = $3
+	          WHERE id = $4 AND status = $5`
+	result, err := tx.ExecContext(ctx, query, to, reason, now, id, from)
+	if err != nil {
+		return nil, err
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return nil, err
+	} else if rows == 0 {
+		return nil, apperrors.Conflict("o status do OKR foi alterado por outra requisição; tente novamente")
+	}
+
+	change, err := insertStatusChange(ctx, tx, id, &from, to, reason, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := recordAudit(ctx, tx, models.AuditEntityOKR, id, models.AuditActionUpdate, before); err != nil {
+		return nil, err
+	}
+	if err := enqueueEvent(ctx, tx, models.EventOKRStatusChanged, change); err != nil {
+		return nil, err
+	}
+
+	return change, tx.Commit()
+}
+
+func insertStatusChange(ctx context.Context, tx *sql.Tx, okrID int64, from *string, to string, reason *string, at time.Time) (*models.OKRStatusChange, error) {
+	change := &models.OKRStatusChange{OKRID: okrID, From: from, To: to, Reason: reason, ChangedAt: at}
+	query := `INSERT INTO okr_status_changes (okr_id, from_status, to_status, reason, changed_at)
+	          VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	if err := tx.QueryRowContext(ctx, query, okrID, from, to, reason, at).Scan(&change.ID); err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+// StatusHistory retorna as mudanças de status do OKR, da mais antiga para a mais recente
+func (r *OKRRepository) StatusHistory(ctx context.Context, okrID int64) ([]models.OKRStatusChange, error) {
+	query := `SELECT id, okr_id, from_status, to_status, reason, changed_at
+	          FROM okr_status_changes
+	          WHERE okr_id = $1
+	          ORDER BY changed_at, id`
+	rows, err := r.db.QueryContext(ctx, query, okrID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := make([]models.OKRStatusChange, 0)
+	for rows.Next() {
+		var change models.OKRStatusChange
+		if err := rows.Scan(&change.ID, &change.OKRID, &change.From, &change.To, &change.Reason, &change.ChangedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
+
+// trackedOKR retorna a condição SQL verdadeira quando o OKR de alias não está arquivado
+// e tem um status com prazos acompanhados (rascunho ou ativo)
+func trackedOKR(alias string) string {
+	return alias + `.archived_at IS NULL AND ` + alias + `.status IN ('` +
+		strings.Join(models.OKRTrackedStatuses, `', '`) + `')`
+}
+
+// trackedRoadmapItem retorna a condição SQL verdadeira quando o item de roadmap
+// indicado pela coluna pertence a um OKR acompanhado (veja trackedOKR)
+func trackedRoadmapItem(column string) string {
+	return `EXISTS (SELECT 1 FROM roadmap_items uri
+	        JOIN roadmap_categories urc ON urc.id = uri.category_id
+	        JOIN roadmaps ur ON ur.id = urc.roadmap_id
+	        JOIN key_results ukr ON ukr.id = ur.key_result_id
+	        JOIN okrs uo ON uo.id = ukr.okr_id
+	        WHERE uri.id = ` + column + ` AND ` + trackedOKR("uo") + `)`
+}
+
+// trashOKR marca o OKR e seus Key Results e roadmaps ainda ativos com o mesmo deleted_at
+func trashOKR(ctx context.Context, tx *sql.Tx, id int64, at time.Time) error {
+	queries := []string{
+		`UPDATE okrs SET deleted_at = $1 WHERE id = $2`,
+		`UPDATE key_results SET deleted_at = $1 WHERE okr_id = $2 AND deleted_at IS NULL`,
+		`UPDATE roadmaps SET deleted_at = $1
+		 WHERE deleted_at IS NULL AND key_result_id IN (SELECT id FROM key_results WHERE okr_id = $2 AND deleted_at = $1)`,
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query, at, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Restore tira o OKR da lixeira junto com os Key Results e roadmaps excluídos com ele
+func (r *OKRRepository) Restore(ctx context.Context, id int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := auditSnapshot(ctx, tx, models.AuditEntityOKR, id)
+	if err != nil {
+		return err
+	}
+	if before == nil || !inTrash(before) {
+		return apperrors.NotFound("OKR não encontrado na lixeira")
+	}
+
+	queries := []string{
+		`UPDATE roadmaps r SET deleted_at = NULL
+		 FROM key_results kr, okrs o
+		 WHERE kr.id = r.key_result_id AND o.id = kr.okr_id AND o.id = $1
+		   AND kr.deleted_at = o.deleted_at AND r.deleted_at = o.deleted_at`,
+		`UPDATE key_results kr SET deleted_at = NULL
+		 FROM okrs o
+		 WHERE o.id = kr.okr_id AND o.id = $1 AND kr.deleted_at = o.deleted_at`,
+		`UPDATE okrs SET deleted_at = NULL WHERE id = $1`,
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query, id); err != nil {
+			return err
+		}
+	}
+
+	if err := recordAudit(ctx, tx, models.AuditEntityOKR, id, models.AuditActionRestore, before); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Stats calcula em uma única consulta os totais de OKRs ativos (status active e não
+// arquivados) e de Key Results concluídos
+func (r *OKRRepository) Stats(ctx context.Context) (*models.OKRStats, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM okrs WHERE deleted_at IS NULL),
+			(SELECT COUNT(*) FROM okrs WHERE deleted_at IS NULL AND archived_at IS NULL AND status = '` + models.OKRStatusActive + `'),
+			(SELECT COUNT(*) FROM key_results WHERE deleted_at IS NULL),
+			(SELECT COUNT(*) FROM key_results WHERE deleted_at IS NULL AND completed)
+	`
+
+	var stats models.OKRStats
+	err := r.db.QueryRowContext(ctx, query).Scan(
+		&stats.TotalOKRs,
+		&stats.ActiveOKRs,
+		&stats.TotalKeyResults,
+		&stats.CompletedKeyResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
 }
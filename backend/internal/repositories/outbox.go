@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+)
+
+// enqueueEvent grava um evento de domínio na outbox de webhooks usando a transação da
+// mudança que o originou: o evento só existe se a mudança for confirmada
+func enqueueEvent(ctx context.Context, tx *sql.Tx, eventType string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar evento %s: %w", eventType, err)
+	}
+
+	query := `INSERT INTO webhook_outbox (event_type, payload) VALUES ($1, $2)`
+	if _, err := tx.ExecContext(ctx, query, eventType, payload); err != nil {
+		return fmt.Errorf("erro ao gravar evento %s na outbox: %w", eventType, err)
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+)
+
+// sqlBuilder acumula condições WHERE e seus argumentos, gerando placeholders
+// posicionais ($1, $2, ...) para que nenhum valor do usuário seja interpolado na query
+type sqlBuilder struct {
+	conditions []string
+	args       []interface{}
+}
+
+// arg registra um argumento e retorna o placeholder correspondente
+func (b *sqlBuilder) arg(value interface{}) string {
+	b.args = append(b.args, value)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+func (b *sqlBuilder) where(condition string) {
+	b.conditions = append(b.conditions, condition)
+}
+
+func (b *sqlBuilder) whereClause() string {
+	if len(b.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(b.conditions, " AND ")
+}
+
+// clone copia o builder para que a query de contagem não herde as condições de cursor
+func (b *sqlBuilder) clone() *sqlBuilder {
+	return &sqlBuilder{
+		conditions: append([]string(nil), b.conditions...),
+		args:       append([]interface{}(nil), b.args...),
+	}
+}
+
+// sortField descreve uma coluna ordenável. A expressão não pode produzir NULL,
+// pois é comparada com o valor do cursor na paginação por keyset
+type sortField struct {
+	expr string
+	cast string
+}
+
+type sortSpec struct {
+	// key é a ordenação pedida (ex.: -created_at), gravada no cursor
+	key   string
+	field sortField
+	desc  bool
+}
+
+func resolveSort(sort string, fields map[string]sortField, defaultSort string) (sortSpec, error) {
+	if sort == "" {
+		sort = defaultSort
+	}
+
+	desc := strings.HasPrefix(sort, "-")
+	field, ok := fields[strings.TrimPrefix(sort, "-")]
+	if !ok {
+		return sortSpec{}, apperrors.InvalidField("sort", "campo de ordenação inválido: "+strings.TrimPrefix(sort, "-"))
+	}
+
+	return sortSpec{key: sort, field: field, desc: desc}, nil
+}
+
+func (s sortSpec) orderBy(idColumn string) string {
+	direction := "ASC"
+	if s.desc {
+		direction = "DESC"
+	}
+	return fmt.Sprintf(" ORDER BY %s %s, %s %s", s.field.expr, direction, idColumn, direction)
+}
+
+// applyCursor adiciona a condição de keyset a partir do último registro da página anterior
+func (s sortSpec) applyCursor(b *sqlBuilder, idColumn string, c *pageCursor) {
+	operator := ">"
+	if s.desc {
+		operator = "<"
+	}
+	b.where(fmt.Sprintf("(%s, %s) %s (%s::%s, %s)",
+		s.field.expr, idColumn, operator, b.arg(c.Value), s.field.cast, b.arg(c.ID)))
+}
+
+// cursor monta o cursor da próxima página a partir do último registro da página atual
+func (s sortSpec) cursor(value string, id int64) string {
+	return encodeCursor(pageCursor{Sort: s.key, Value: value, ID: id})
+}
+
+// decodeCursor decodifica o cursor e confere se ele foi gerado por esta mesma
+// ordenação e se o valor é compatível com o tipo da coluna, para que um cursor de
+// outra ordenação ou editado à mão resulte em 400 e não em erro no banco
+func (s sortSpec) decodeCursor(encoded string) (*pageCursor, error) {
+	c, err := decodeCursor(encoded)
+	if err != nil || c == nil {
+		return c, err
+	}
+	if c.Sort != s.key {
+		return nil, apperrors.InvalidField("cursor", "cursor gerado para outra ordenação; recomece a paginação sem cursor")
+	}
+	if !validSortValue(s.field.cast, c.Value) {
+		return nil, apperrors.InvalidField("cursor", "cursor inválido")
+	}
+	return c, nil
+}
+
+// validSortValue indica se value, no formato de texto do PostgreSQL, pode ser
+// convertido para o tipo cast
+func validSortValue(cast, value string) bool {
+	var err error
+	switch cast {
+	case "text":
+	case "date":
+		_, err = time.Parse("2006-01-02", value)
+	case "timestamp":
+		_, err = time.Parse("2006-01-02 15:04:05.999999", value)
+	default:
+		return false
+	}
+	return err == nil
+}
+
+// pageLimit normaliza o tamanho da página para o intervalo aceito pela API
+func pageLimit(limit int) int {
+	if limit <= 0 {
+		return models.DefaultPageLimit
+	}
+	if limit > models.MaxPageLimit {
+		return models.MaxPageLimit
+	}
+	return limit
+}
+
+type pageCursor struct {
+	Sort  string `json:"s,omitempty"`
+	Value string `json:"v"`
+	ID    int64  `json:"id"`
+}
+
+func encodeCursor(c pageCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(encoded string) (*pageCursor, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, apperrors.InvalidField("cursor", "cursor inválido")
+	}
+
+	var c pageCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, apperrors.InvalidField("cursor", "cursor inválido")
+	}
+
+	return &c, nil
+}
+
+// requireRowsAffected retorna notFound quando o comando não alterou nenhuma linha
+func requireRowsAffected(result sql.Result, notFound error) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return notFound
+	}
+	return nil
+}
+
+// escapeLike escapa os curingas do LIKE para que a busca textual seja literal
+func escapeLike(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + replacer.Replace(s) + "%"
+}
@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+)
+
+func TestSortCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		sort  string
+		value string
+	}{
+		{"-created_at", "2026-03-02 15:04:05.123456"},
+		{"created_at", "2026-03-02 15:04:05"},
+		{"completion_date", "9999-12-31"},
+		{"objective", "Aprender Go, Rust; e \"Zig\""},
+		{"", "2026-03-02 15:04:05.5"}, // ordenação padrão (-created_at)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			sort, err := resolveSort(tt.sort, okrSortFields, "-created_at")
+			if err != nil {
+				t.Fatal(err)
+			}
+			c, err := sort.decodeCursor(sort.cursor(tt.value, 42))
+			if err != nil {
+				t.Fatalf("decodeCursor: %v", err)
+			}
+			if c.Value != tt.value || c.ID != 42 {
+				t.Errorf("cursor = %+v, want valor %q e id 42", c, tt.value)
+			}
+		})
+	}
+
+	sort, _ := resolveSort("", okrSortFields, "-created_at")
+	if c, err := sort.decodeCursor(""); c != nil || err != nil {
+		t.Errorf("cursor vazio = (%+v, %v), want (nil, nil)", c, err)
+	}
+}
+
+func TestSortCursorRejected(t *testing.T) {
+	encoded := func(json string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(json))
+	}
+	cursorFor := func(sort string, value string) string {
+		spec, err := resolveSort(sort, okrSortFields, "-created_at")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return spec.cursor(value, 7)
+	}
+
+	tests := []struct {
+		name   string
+		sort   string
+		cursor string
+	}{
+		{"cursor de outro campo", "created_at", cursorFor("objective", "Aprender Go")},
+		{"cursor de outro campo com data", "completion_date", cursorFor("objective", "Aprender Go")},
+		{"cursor da direção oposta", "created_at", cursorFor("-created_at", "2026-03-02 15:04:05")},
+		{"cursor sem ordenação", "-created_at", encoded(`{"v":"2026-03-02 15:04:05","id":7}`)},
+		{"timestamp editado", "-created_at", encoded(`{"s":"-created_at","v":"Aprender Go","id":7}`)},
+		{"data editada", "completion_date", encoded(`{"s":"completion_date","v":"2026-02-30","id":7}`)},
+		{"base64 inválido", "-created_at", "não é base64"},
+		{"JSON inválido", "-created_at", encoded(`{"s":`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sort, err := resolveSort(tt.sort, okrSortFields, "-created_at")
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = sort.decodeCursor(tt.cursor)
+			if !errors.Is(err, apperrors.ErrValidation) {
+				t.Errorf("err = %v, want erro de validação", err)
+			}
+		})
+	}
+}
+
+func TestValidSortValueCoversSortFields(t *testing.T) {
+	for _, fields := range []map[string]sortField{okrSortFields, keyResultSortFields} {
+		for name, field := range fields {
+			if field.cast != "text" && validSortValue(field.cast, "") {
+				t.Errorf("%s: valor vazio aceito para %s", name, field.cast)
+			}
+			switch field.cast {
+			case "text", "date", "timestamp":
+			default:
+				t.Errorf("%s: tipo %s sem validação do cursor", name, field.cast)
+			}
+		}
+	}
+}
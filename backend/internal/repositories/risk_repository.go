@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type RiskRepository struct {
+	db *sql.DB
+}
+
+func NewRiskRepository(db *sql.DB) *RiskRepository {
+	return &RiskRepository{db: db}
+}
+
+// RiskKeyResult reúne os dados usados para avaliar o risco de um Key Result
+type RiskKeyResult struct {
+	ID                     int64
+	OKRID                  int64
+	Title                  string
+	Completed              bool
+	ExpectedCompletionDate *time.Time
+	CreatedAt              time.Time
+	// Quantidade de itens do roadmap e o progresso médio deles: item concluído conta
+	// 1 e os demais contam a fração de atividades concluídas da sua trilha
+	RoadmapItems    int
+	RoadmapProgress float64
+}
+
+// RiskTrail reúne os dados usados para avaliar o risco de uma trilha educacional
+type RiskTrail struct {
+	ID            int64
+	RoadmapItemID int64
+	KeyResultID   int64
+	Topic         string
+	ItemCompleted bool
+	StartDate     time.Time
+	// Data da última etapa agendada ou, sem agendamento, início + total_days - 1
+	DueDate             time.Time
+	TotalActivities     int
+	CompletedActivities int
+}
+
+// KeyResults retorna os dados de risco dos Key Results dos OKRs informados
+func (r *RiskRepository) KeyResults(ctx context.Context, okrIDs []int64) ([]RiskKeyResult, error) {
+	query := `WITH items AS (
+	              SELECT r.key_result_id, ri.id,
+	                     CASE WHEN COALESCE(ri.completed, FALSE) THEN 1.0
+	                          WHEN COUNT(a.id) > 0 THEN COUNT(a.id) FILTER (WHERE a.completed)::float / COUNT(a.id)
+	                          ELSE 0 END AS progress
+	              FROM roadmaps r
+	              JOIN key_results kr ON kr.id = r.key_result_id
+	              JOIN roadmap_categories rc ON rc.roadmap_id = r.id
+	              JOIN roadmap_items ri ON ri.category_id = rc.id
+	              LEFT JOIN educational_trails t ON t.roadmap_item_id = ri.id
+	              LEFT JOIN educational_trail_steps s ON s.trail_id = t.id
+	              LEFT JOIN educational_trail_activities a ON a.step_id = s.id
+	              WHERE kr.okr_id = ANY($1) AND r.deleted_at IS NULL
+	              GROUP BY r.key_result_id, ri.id, ri.completed
+	          )
+	          SELECT kr.id, kr.okr_id, kr.title, COALESCE(kr.completed, FALSE), kr.expected_completion_date, kr.created_at,
+	                 COUNT(items.id), COALESCE(AVG(items.progress), 0)
+	          FROM key_results kr
+	          LEFT JOIN items ON items.key_result_id = kr.id
+	          WHERE kr.okr_id = ANY($1) AND kr.deleted_at IS NULL
+	          GROUP BY kr.id
+	          ORDER BY kr.okr_id, kr.id`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(okrIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keyResults := make([]RiskKeyResult, 0)
+	for rows.Next() {
+		var kr RiskKeyResult
+		if err := rows.Scan(&kr.ID, &kr.OKRID, &kr.Title, &kr.Completed, &kr.ExpectedCompletionDate,
+			&kr.CreatedAt, &kr.RoadmapItems, &kr.RoadmapProgress); err != nil {
+			return nil, err
+		}
+		keyResults = append(keyResults, kr)
+	}
+
+	return keyResults, rows.Err()
+}
+
+// Trails retorna os dados de risco das trilhas educacionais dos OKRs informados
+func (r *RiskRepository) Trails(ctx context.Context, okrIDs []int64) ([]RiskTrail, error) {
+	query := `SELECT t.id, t.roadmap_item_id, r.key_result_id, t.topic, COALESCE(ri.completed, FALSE),
+	                 COALESCE(t.start_date, t.created_at::date),
+	                 COALESCE(MAX(s.scheduled_date), COALESCE(t.start_date, t.created_at::date) + GREATEST(t.total_days, 1) - 1),
+	                 COUNT(a.id), COUNT(a.id) FILTER (WHERE a.completed)
+	          FROM educational_trails t
+	          JOIN roadmap_items ri ON ri.id = t.roadmap_item_id
+	          JOIN roadmap_categories rc ON rc.id = ri.category_id
+	          JOIN roadmaps r ON r.id = rc.roadmap_id
+	          JOIN key_results kr ON kr.id = r.key_result_id
+	          LEFT JOIN educational_trail_steps s ON s.trail_id = t.id
+	          LEFT JOIN educational_trail_activities a ON a.step_id = s.id
+	          WHERE kr.okr_id = ANY($1) AND r.deleted_at IS NULL
+	          GROUP BY t.id, r.key_result_id, ri.completed
+	          ORDER BY r.key_result_id, t.id`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(okrIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	trails := make([]RiskTrail, 0)
+	for rows.Next() {
+		var trail RiskTrail
+		if err := rows.Scan(&trail.ID, &trail.RoadmapItemID, &trail.KeyResultID, &trail.Topic, &trail.ItemCompleted,
+			&trail.StartDate, &trail.DueDate, &trail.TotalActivities, &trail.CompletedActivities); err != nil {
+			return nil, err
+		}
+		trails = append(trails, trail)
+	}
+
+	return trails, rows.Err()
+}
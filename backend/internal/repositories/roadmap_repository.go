@@ -1,9 +1,11 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"time"
 
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
 	"github.com/conquista-ai/conquista-ai/internal/models"
 )
 
@@ -15,8 +17,8 @@ func NewRoadmapRepository(db *sql.DB) *RoadmapRepository {
 	return &RoadmapRepository{db: db}
 }
 
-func (r *RoadmapRepository) Create(roadmap *models.Roadmap) error {
-	tx, err := r.db.Begin()
+func (r *RoadmapRepository) Create(ctx context.Context, roadmap *models.Roadmap) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
@@ -29,40 +31,55 @@ func (r *RoadmapRepository) Create(roadmap *models.Roadmap) error {
 	// Criar roadmap
 	query := `INSERT INTO roadmaps (key_result_id, topic, created_at, updated_at) 
 	          VALUES ($1, $2, $3, $4) RETURNING id`
-	err = tx.QueryRow(query, roadmap.KeyResultID, roadmap.Topic, roadmap.CreatedAt, roadmap.UpdatedAt).Scan(&roadmap.ID)
+	err = tx.QueryRowContext(ctx, query, roadmap.KeyResultID, roadmap.Topic, roadmap.CreatedAt, roadmap.UpdatedAt).Scan(&roadmap.ID)
 	if err != nil {
 		return err
 	}
 
 	// Criar categorias e itens
-	for _, category := range roadmap.Categories {
+	for i := range roadmap.Categories {
+		category := &roadmap.Categories[i]
 		catQuery := `INSERT INTO roadmap_categories (roadmap_id, category, created_at) 
 		             VALUES ($1, $2, $3) RETURNING id`
-		err = tx.QueryRow(catQuery, roadmap.ID, category.Category, now).Scan(&category.ID)
+		err = tx.QueryRowContext(ctx, catQuery, roadmap.ID, category.Category, now).Scan(&category.ID)
 		if err != nil {
 			return err
 		}
+		category.RoadmapID = roadmap.ID
+		category.CreatedAt = now
 
-		for _, item := range category.Items {
+		for j := range category.Items {
+			item := &category.Items[j]
 			itemQuery := `INSERT INTO roadmap_items (category_id, title, completed, created_at, updated_at) 
 			              VALUES ($1, $2, $3, $4, $5) RETURNING id`
-			err = tx.QueryRow(itemQuery, category.ID, item.Title, item.Completed, now, now).Scan(&item.ID)
+			err = tx.QueryRowContext(ctx, itemQuery, category.ID, item.Title, item.Completed, now, now).Scan(&item.ID)
 			if err != nil {
 				return err
 			}
+			item.CategoryID = category.ID
+			item.CreatedAt = now
+			item.UpdatedAt = now
+
+			if err := recordAudit(ctx, tx, models.AuditEntityRoadmapItem, item.ID, models.AuditActionCreate, nil); err != nil {
+				return err
+			}
 		}
 	}
 
+	if err := enqueueEvent(ctx, tx, models.EventRoadmapGenerated, roadmap); err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
 
-func (r *RoadmapRepository) GetByKeyResultID(keyResultID int64) (*models.Roadmap, error) {
+func (r *RoadmapRepository) GetByKeyResultID(ctx context.Context, keyResultID int64) (*models.Roadmap, error) {
 	// Buscar roadmap
 	query := `SELECT id, key_result_id, topic, created_at, updated_at 
-	          FROM roadmaps WHERE key_result_id = $1`
+	          FROM roadmaps WHERE key_result_id = $1 AND deleted_at IS NULL`
 
 	var roadmap models.Roadmap
-	err := r.db.QueryRow(query, keyResultID).Scan(&roadmap.ID, &roadmap.KeyResultID,
+	err := r.db.QueryRowContext(ctx, query, keyResultID).Scan(&roadmap.ID, &roadmap.KeyResultID,
 		&roadmap.Topic, &roadmap.CreatedAt, &roadmap.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -74,7 +91,7 @@ func (r *RoadmapRepository) GetByKeyResultID(keyResultID int64) (*models.Roadmap
 	// Buscar categorias
 	catQuery := `SELECT id, roadmap_id, category, created_at 
 	             FROM roadmap_categories WHERE roadmap_id = $1 ORDER BY id`
-	catRows, err := r.db.Query(catQuery, roadmap.ID)
+	catRows, err := r.db.QueryContext(ctx, catQuery, roadmap.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -90,7 +107,7 @@ func (r *RoadmapRepository) GetByKeyResultID(keyResultID int64) (*models.Roadmap
 		// Buscar itens da categoria
 		itemQuery := `SELECT id, category_id, title, completed, created_at, updated_at 
 		              FROM roadmap_items WHERE category_id = $1 ORDER BY id`
-		itemRows, err := r.db.Query(itemQuery, cat.ID)
+		itemRows, err := r.db.QueryContext(ctx, itemQuery, cat.ID)
 		if err != nil {
 			return nil, err
 		}
@@ -113,96 +130,214 @@ func (r *RoadmapRepository) GetByKeyResultID(keyResultID int64) (*models.Roadmap
 	return &roadmap, nil
 }
 
-func (r *RoadmapRepository) UpdateItem(itemID int64, completed bool) error {
+func (r *RoadmapRepository) UpdateItem(ctx context.Context, itemID int64, completed bool) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := auditSnapshot(ctx, tx, models.AuditEntityRoadmapItem, itemID)
+	if err != nil {
+		return err
+	}
+	if before == nil {
+		return apperrors.NotFound("item do roadmap não encontrado")
+	}
+
+	var active bool
+	if err := tx.QueryRowContext(ctx, `SELECT `+activeRoadmapItem("$1::bigint"), itemID).Scan(&active); err != nil {
+		return err
+	}
+	if !active {
+		return apperrors.NotFound("item do roadmap não encontrado")
+	}
+
 	query := `UPDATE roadmap_items SET completed = $1, updated_at = $2 WHERE id = $3`
-	_, err := r.db.Exec(query, completed, time.Now(), itemID)
-	return err
+	if _, err := tx.ExecContext(ctx, query, completed, time.Now(), itemID); err != nil {
+		return err
+	}
+
+	if err := recordAudit(ctx, tx, models.AuditEntityRoadmapItem, itemID, models.AuditActionUpdate, before); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-// DeleteByKeyResultID deleta um roadmap e todos os dados relacionados (categorias, itens, trilhas)
-// através de cascata do banco de dados
-func (r *RoadmapRepository) DeleteByKeyResultID(keyResultID int64) error {
-	query := `DELETE FROM roadmaps WHERE key_result_id = $1`
-	result, err := r.db.Exec(query, keyResultID)
+// DeleteByKeyResultID move o roadmap do Key Result para a lixeira. Categorias, itens e
+// trilhas ficam intactos e voltam com a restauração. A exclusão de cada item é
+// registrada na auditoria
+func (r *RoadmapRepository) DeleteByKeyResultID(ctx context.Context, keyResultID int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
-	
-	rowsAffected, err := result.RowsAffected()
+	defer tx.Rollback()
+
+	var id int64
+	err = tx.QueryRowContext(ctx, `SELECT id FROM roadmaps WHERE key_result_id = $1 AND deleted_at IS NULL FOR UPDATE`,
+		keyResultID).Scan(&id)
 	if err != nil {
 		return err
 	}
-	
-	if rowsAffected == 0 {
-		return sql.ErrNoRows
+
+	items, err := roadmapItemSnapshots(ctx, tx, id)
+	if err != nil {
+		return err
 	}
-	
-	return nil
+
+	if _, err := tx.ExecContext(ctx, `UPDATE roadmaps SET deleted_at = $1 WHERE id = $2`, time.Now(), id); err != nil {
+		return err
+	}
+
+	if err := recordAudits(ctx, tx, models.AuditEntityRoadmapItem, models.AuditActionDelete, items); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// roadmapItemSnapshots retorna o retrato de auditoria de cada item do roadmap
+func roadmapItemSnapshots(ctx context.Context, tx *sql.Tx, roadmapID int64) ([]auditedRow, error) {
+	return auditSnapshots(ctx, tx, models.AuditEntityRoadmapItem,
+		`SELECT ri.id
+		 FROM roadmap_items ri
+		 JOIN roadmap_categories rc ON rc.id = ri.category_id
+		 WHERE rc.roadmap_id = $1
+		 ORDER BY ri.id`, roadmapID)
 }
 
-// GetOKRByRoadmapItemID busca o OKR relacionado a um roadmap item e retorna também
-// o número total de Key Results do OKR, o número total de itens do roadmap,
-// e o Key Result relacionado com sua expected_completion_date
-func (r *RoadmapRepository) GetOKRByRoadmapItemID(roadmapItemID int64) (*models.OKR, *models.KeyResult, int, int, error) {
+// Restore tira o roadmap da lixeira e retorna o Key Result a que ele pertence. Não é
+// possível restaurar o roadmap de um Key Result na lixeira nem de um Key Result que já
+// tem outro roadmap. A restauração de cada item é registrada na auditoria
+func (r *RoadmapRepository) Restore(ctx context.Context, id int64) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var keyResultID int64
+	var inTrash, keyResultInTrash bool
+	err = tx.QueryRowContext(ctx, `SELECT r.key_result_id, r.deleted_at IS NOT NULL, kr.deleted_at IS NOT NULL
+	          FROM roadmaps r
+	          JOIN key_results kr ON kr.id = r.key_result_id
+	          WHERE r.id = $1
+	          FOR UPDATE OF r`, id).Scan(&keyResultID, &inTrash, &keyResultInTrash)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, err
+	}
+	if err == sql.ErrNoRows || !inTrash {
+		return 0, apperrors.NotFound("roadmap não encontrado na lixeira")
+	}
+	if keyResultInTrash {
+		return 0, apperrors.Conflict("o Key Result deste roadmap está na lixeira; restaure o Key Result")
+	}
+
+	var hasActive bool
+	err = tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM roadmaps WHERE key_result_id = $1 AND deleted_at IS NULL)`, keyResultID).Scan(&hasActive)
+	if err != nil {
+		return 0, err
+	}
+	if hasActive {
+		return 0, apperrors.Conflict("o Key Result já possui outro roadmap")
+	}
+
+	items, err := roadmapItemSnapshots(ctx, tx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE roadmaps SET deleted_at = NULL WHERE id = $1`, id); err != nil {
+		return 0, err
+	}
+
+	if err := recordAudits(ctx, tx, models.AuditEntityRoadmapItem, models.AuditActionRestore, items); err != nil {
+		return 0, err
+	}
+
+	return keyResultID, tx.Commit()
+}
+
+// RoadmapItemPlanning reúne o que é preciso para calcular o prazo da trilha de um item
+type RoadmapItemPlanning struct {
+	OKR               *models.OKR
+	KeyResult         *models.KeyResult
+	TotalKeyResults   int
+	TotalRoadmapItems int
+	// Posição do item no roadmap (a partir de 0), na ordem de criação
+	ItemPosition int
+}
+
+// GetPlanningByRoadmapItemID busca o OKR e o Key Result relacionados a um roadmap item,
+// o número total de Key Results do OKR, o número total de itens do roadmap e a posição
+// do item. Retorna nil quando o item não existe
+func (r *RoadmapRepository) GetPlanningByRoadmapItemID(ctx context.Context, roadmapItemID int64) (*RoadmapItemPlanning, error) {
 	query := `
 		SELECT 
 			o.id, 
 			o.objective, 
 			o.category_id, 
 			o.completion_date, 
+			o.status,
 			o.created_at, 
 			o.updated_at,
 			kr.id as key_result_id,
 			kr.okr_id,
 			kr.title as key_result_title,
 			kr.completed as key_result_completed,
+			kr.difficulty as key_result_difficulty,
 			kr.expected_completion_date,
 			kr.created_at as key_result_created_at,
 			kr.updated_at as key_result_updated_at,
 			COUNT(DISTINCT kr_all.id) as total_key_results,
-			COUNT(DISTINCT ri_all.id) as total_roadmap_items
+			COUNT(DISTINCT ri_all.id) as total_roadmap_items,
+			COUNT(DISTINCT ri_all.id) FILTER (WHERE ri_all.id < ri.id) as item_position
 		FROM roadmap_items ri
 		INNER JOIN roadmap_categories rc ON ri.category_id = rc.id
 		INNER JOIN roadmaps r ON rc.roadmap_id = r.id
 		INNER JOIN key_results kr ON r.key_result_id = kr.id
 		INNER JOIN okrs o ON kr.okr_id = o.id
-		LEFT JOIN key_results kr_all ON kr_all.okr_id = o.id
+		LEFT JOIN key_results kr_all ON kr_all.okr_id = o.id AND kr_all.deleted_at IS NULL
 		LEFT JOIN roadmap_categories rc_all ON rc_all.roadmap_id = r.id
 		LEFT JOIN roadmap_items ri_all ON ri_all.category_id = rc_all.id
-		WHERE ri.id = $1
-		GROUP BY o.id, o.objective, o.category_id, o.completion_date, o.created_at, o.updated_at,
-		         kr.id, kr.okr_id, kr.title, kr.completed, kr.expected_completion_date, kr.created_at, kr.updated_at
+		WHERE ri.id = $1 AND r.deleted_at IS NULL
+		GROUP BY ri.id, o.id, o.objective, o.category_id, o.completion_date, o.status, o.created_at, o.updated_at,
+		         kr.id, kr.okr_id, kr.title, kr.completed, kr.difficulty, kr.expected_completion_date, kr.created_at, kr.updated_at
 	`
 
 	var okr models.OKR
 	var keyResult models.KeyResult
-	var totalKeyResults int
-	var totalRoadmapItems int
+	planning := RoadmapItemPlanning{OKR: &okr, KeyResult: &keyResult}
 	var completionDate sql.NullTime
 	var keyResultExpectedDate sql.NullTime
 
-	err := r.db.QueryRow(query, roadmapItemID).Scan(
+	err := r.db.QueryRowContext(ctx, query, roadmapItemID).Scan(
 		&okr.ID,
 		&okr.Objective,
 		&okr.CategoryID,
 		&completionDate,
+		&okr.Status,
 		&okr.CreatedAt,
 		&okr.UpdatedAt,
 		&keyResult.ID,
 		&keyResult.OKRID,
 		&keyResult.Title,
 		&keyResult.Completed,
+		&keyResult.Difficulty,
 		&keyResultExpectedDate,
 		&keyResult.CreatedAt,
 		&keyResult.UpdatedAt,
-		&totalKeyResults,
-		&totalRoadmapItems,
+		&planning.TotalKeyResults,
+		&planning.TotalRoadmapItems,
+		&planning.ItemPosition,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, nil, 0, 0, nil
+			return nil, nil
 		}
-		return nil, nil, 0, 0, err
+		return nil, err
 	}
 
 	if completionDate.Valid {
@@ -213,5 +348,5 @@ func (r *RoadmapRepository) GetOKRByRoadmapItemID(roadmapItemID int64) (*models.
 		keyResult.ExpectedCompletionDate = &keyResultExpectedDate.Time
 	}
 
-	return &okr, &keyResult, totalKeyResults, totalRoadmapItems, nil
+	return &planning, nil
 }
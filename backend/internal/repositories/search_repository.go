@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/conquista-ai/conquista-ai/internal/models"
+)
+
+// searchConfig é a configuração de text search criada na migration 008 (português sem acentos)
+const searchConfig = "public.portuguese_unaccent"
+
+// searchSources mapeia cada tipo pesquisável para uma subquery com colunas padronizadas:
+// type, id, title, body (texto usado no destaque), rank, okr_id, key_result_id, roadmap_item_id
+var searchSources = map[string]string{
+	models.SearchTypeOKR: `SELECT 'okr' AS type, o.id, o.objective AS title, o.objective AS body,
+		ts_rank(o.search_vector, q.query) AS rank,
+		o.id AS okr_id, NULL::integer AS key_result_id, NULL::integer AS roadmap_item_id
+		FROM okrs o, q WHERE o.search_vector @@ q.query AND o.deleted_at IS NULL`,
+
+	models.SearchTypeKeyResult: `SELECT 'key_result', kr.id, kr.title, kr.title,
+		ts_rank(kr.search_vector, q.query),
+		kr.okr_id, kr.id, NULL::integer
+		FROM key_results kr, q WHERE kr.search_vector @@ q.query AND kr.deleted_at IS NULL`,
+
+	models.SearchTypeRoadmapItem: `SELECT 'roadmap_item', ri.id, ri.title, ri.title,
+		ts_rank(ri.search_vector, q.query),
+		kr.okr_id, r.key_result_id, ri.id
+		FROM roadmap_items ri
+		INNER JOIN roadmap_categories rc ON ri.category_id = rc.id
+		INNER JOIN roadmaps r ON rc.roadmap_id = r.id
+		INNER JOIN key_results kr ON r.key_result_id = kr.id, q
+		WHERE ri.search_vector @@ q.query AND r.deleted_at IS NULL`,
+
+	models.SearchTypeTrailStep: `SELECT 'trail_step', s.id, s.title, s.title || ' ' || coalesce(s.description, ''),
+		ts_rank(s.search_vector, q.query),
+		NULL::integer, NULL::integer, t.roadmap_item_id
+		FROM educational_trail_steps s
+		INNER JOIN educational_trails t ON s.trail_id = t.id, q
+		WHERE s.search_vector @@ q.query AND ` + activeRoadmapItem("t.roadmap_item_id"),
+
+	models.SearchTypeTrailResource: `SELECT 'trail_resource', tr.id, tr.title, tr.title,
+		ts_rank(tr.search_vector, q.query),
+		NULL::integer, NULL::integer, t.roadmap_item_id
+		FROM educational_trail_resources tr
+		INNER JOIN educational_trails t ON tr.trail_id = t.id, q
+		WHERE tr.search_vector @@ q.query AND ` + activeRoadmapItem("t.roadmap_item_id"),
+
+	models.SearchTypeEducationalResource: `SELECT 'educational_resource', er.id, er.title, er.title,
+		ts_rank(er.search_vector, q.query),
+		NULL::integer, NULL::integer, edr.roadmap_item_id
+		FROM educational_resources er
+		INNER JOIN educational_roadmaps edr ON er.educational_roadmap_id = edr.id, q
+		WHERE er.search_vector @@ q.query AND ` + activeRoadmapItem("edr.roadmap_item_id"),
+}
+
+// htmlEscapedBody escapa o HTML do texto antes do ts_headline, para que o destaque só
+// contenha as marcações <mark> da busca e possa ser exibido como HTML. O & é trocado
+// primeiro para não escapar de novo as entidades geradas
+const htmlEscapedBody = `replace(replace(replace(replace(replace(results.body,
+	'&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;')`
+
+// SearchTypes lista os tipos pesquisáveis na ordem em que as subqueries são combinadas
+var SearchTypes = []string{
+	models.SearchTypeOKR,
+	models.SearchTypeKeyResult,
+	models.SearchTypeRoadmapItem,
+	models.SearchTypeTrailStep,
+	models.SearchTypeTrailResource,
+	models.SearchTypeEducationalResource,
+}
+
+type SearchRepository struct {
+	db *sql.DB
+}
+
+func NewSearchRepository(db *sql.DB) *SearchRepository {
+	return &SearchRepository{db: db}
+}
+
+// Search executa a busca textual nos tipos informados, ordenando os resultados por relevância
+func (r *SearchRepository) Search(ctx context.Context, term string, types []string, limit int) ([]models.SearchResult, error) {
+	if len(types) == 0 {
+		types = SearchTypes
+	}
+
+	subqueries := make([]string, 0, len(types))
+	for _, t := range types {
+		source, ok := searchSources[t]
+		if !ok {
+			return nil, fmt.Errorf("tipo de busca inválido: %s", t)
+		}
+		subqueries = append(subqueries, source)
+	}
+
+	query := `WITH q AS (SELECT websearch_to_tsquery('` + searchConfig + `', $1) AS query)
+	SELECT results.type, results.id, results.title,
+	       ts_headline('` + searchConfig + `', ` + htmlEscapedBody + `, q.query, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2'),
+	       results.rank, results.okr_id, results.key_result_id, results.roadmap_item_id
+	FROM (
+		SELECT * FROM (` + strings.Join(subqueries, "\n\t\tUNION ALL\n\t\t") + `) matches
+		ORDER BY rank DESC, type, id
+		LIMIT $2
+	) results, q
+	ORDER BY results.rank DESC, results.type, results.id`
+
+	rows, err := r.db.QueryContext(ctx, query, term, pageLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]models.SearchResult, 0)
+	for rows.Next() {
+		var res models.SearchResult
+		var okrID, keyResultID, roadmapItemID sql.NullInt64
+		if err := rows.Scan(&res.Type, &res.ID, &res.Title, &res.Highlight, &res.Rank,
+			&okrID, &keyResultID, &roadmapItemID); err != nil {
+			return nil, err
+		}
+		if okrID.Valid {
+			res.OKRID = &okrID.Int64
+		}
+		if keyResultID.Valid {
+			res.KeyResultID = &keyResultID.Int64
+		}
+		if roadmapItemID.Valid {
+			res.RoadmapItemID = &roadmapItemID.Int64
+		}
+		results = append(results, res)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
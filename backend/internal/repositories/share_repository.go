@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/models"
+)
+
+type ShareRepository struct {
+	db *sql.DB
+}
+
+func NewShareRepository(db *sql.DB) *ShareRepository {
+	return &ShareRepository{db: db}
+}
+
+const shareLinkColumns = `id, key_result_id, expires_at, revoked_at, view_count, last_viewed_at, created_at`
+
+func scanShareLink(row interface{ Scan(...any) error }) (*models.ShareLink, error) {
+	var link models.ShareLink
+	if err := row.Scan(&link.ID, &link.KeyResultID, &link.ExpiresAt, &link.RevokedAt,
+		&link.ViewCount, &link.LastViewedAt, &link.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// Create grava o link com o hash do token, preenchendo ID e CreatedAt
+func (r *ShareRepository) Create(ctx context.Context, link *models.ShareLink, tokenHash string, now time.Time) error {
+	link.CreatedAt = now
+	return r.db.QueryRowContext(ctx,
+		`INSERT INTO share_links (key_result_id, token_hash, expires_at, created_at) VALUES ($1, $2, $3, $4) RETURNING id`,
+		link.KeyResultID, tokenHash, link.ExpiresAt, link.CreatedAt).Scan(&link.ID)
+}
+
+// ListByKeyResultID retorna os links do roadmap do Key Result, dos mais recentes aos
+// mais antigos, incluindo os revogados e expirados
+func (r *ShareRepository) ListByKeyResultID(ctx context.Context, keyResultID int64) ([]models.ShareLink, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+shareLinkColumns+` FROM share_links WHERE key_result_id = $1 ORDER BY created_at DESC, id DESC`, keyResultID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := make([]models.ShareLink, 0)
+	for rows.Next() {
+		link, err := scanShareLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, *link)
+	}
+	return links, rows.Err()
+}
+
+// Revoke revoga o link. Retorna nil quando ele não existe; um link já revogado é
+// retornado sem alteração
+func (r *ShareRepository) Revoke(ctx context.Context, id int64, now time.Time) (*models.ShareLink, error) {
+	link, err := scanShareLink(r.db.QueryRowContext(ctx,
+		`UPDATE share_links SET revoked_at = COALESCE(revoked_at, $1) WHERE id = $2 RETURNING `+shareLinkColumns, now, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return link, err
+}
+
+// View valida o token de um link ativo (não revogado nem expirado) e registra a
+// visualização. Retorna nil quando o token não é válido
+func (r *ShareRepository) View(ctx context.Context, tokenHash string, now time.Time) (*models.ShareLink, error) {
+	link, err := scanShareLink(r.db.QueryRowContext(ctx,
+		`UPDATE share_links SET view_count = view_count + 1, last_viewed_at = $1
+		 WHERE token_hash = $2 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > $1)
+		 RETURNING `+shareLinkColumns, now, tokenHash))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return link, err
+}
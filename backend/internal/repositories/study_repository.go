@@ -0,0 +1,258 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+)
+
+// maxTimerMinutes limita a duração de uma sessão de cronômetro esquecido em andamento
+const maxTimerMinutes = 12 * 60
+
+type StudyRepository struct {
+	db *sql.DB
+}
+
+func NewStudyRepository(db *sql.DB) *StudyRepository {
+	return &StudyRepository{db: db}
+}
+
+const studySessionColumns = `id, activity_id, source, started_at, ended_at, minutes, note, created_at`
+
+// StartTimer inicia o cronômetro da atividade. Só pode haver um cronômetro em andamento
+func (r *StudyRepository) StartTimer(ctx context.Context, activityID int64, now time.Time) (*models.StudySession, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := requireActivity(ctx, tx, activityID); err != nil {
+		return nil, err
+	}
+
+	var runningActivityID int64
+	err = tx.QueryRowContext(ctx, `SELECT activity_id FROM study_sessions WHERE ended_at IS NULL`).Scan(&runningActivityID)
+	switch {
+	case err == nil:
+		return nil, apperrors.Conflict("já existe um cronômetro em andamento (atividade %d)", runningActivityID)
+	case err != sql.ErrNoRows:
+		return nil, err
+	}
+
+	query := `INSERT INTO study_sessions (activity_id, source, started_at, created_at)
+	          VALUES ($1, $2, $3, $3) RETURNING ` + studySessionColumns
+	session, err := scanStudySession(tx.QueryRowContext(ctx, query, activityID, models.StudySessionTimer, now))
+	if err != nil {
+		return nil, err
+	}
+
+	return session, tx.Commit()
+}
+
+// StopTimer encerra o cronômetro em andamento da atividade e grava os minutos
+// decorridos (arredondados para cima)
+func (r *StudyRepository) StopTimer(ctx context.Context, activityID int64, now time.Time) (*models.StudySession, error) {
+	session, err := scanStudySession(r.db.QueryRowContext(ctx, stopTimerQuery+` RETURNING `+studySessionColumns,
+		activityID, now, maxTimerMinutes))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperrors.NotFound("nenhum cronômetro em andamento para esta atividade")
+		}
+		return nil, err
+	}
+	return session, nil
+}
+
+const stopTimerQuery = `UPDATE study_sessions
+	          SET ended_at = $2,
+	              minutes = LEAST(GREATEST(CEIL(EXTRACT(EPOCH FROM ($2 - started_at)) / 60), 0), $3)::int
+	          WHERE activity_id = $1 AND ended_at IS NULL`
+
+// stopStudyTimer encerra, dentro da transação tx, um cronômetro em andamento na atividade
+func stopStudyTimer(ctx context.Context, tx *sql.Tx, activityID int64, now time.Time) error {
+	_, err := tx.ExecContext(ctx, stopTimerQuery, activityID, now, maxTimerMinutes)
+	return err
+}
+
+// CreateSession lança manualmente um tempo de estudo
+func (r *StudyRepository) CreateSession(ctx context.Context, session *models.StudySession) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := requireActivity(ctx, tx, session.ActivityID); err != nil {
+		return err
+	}
+
+	session.Source = models.StudySessionManual
+	session.CreatedAt = time.Now()
+	endedAt := session.StartedAt.Add(time.Duration(session.Minutes) * time.Minute)
+	session.EndedAt = &endedAt
+
+	query := `INSERT INTO study_sessions (activity_id, source, started_at, ended_at, minutes, note, created_at)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+	if err := tx.QueryRowContext(ctx, query, session.ActivityID, session.Source, session.StartedAt, endedAt,
+		session.Minutes, session.Note, session.CreatedAt).Scan(&session.ID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *StudyRepository) ListSessions(ctx context.Context, activityID int64) ([]models.StudySession, error) {
+	query := `SELECT ` + studySessionColumns + ` FROM study_sessions WHERE activity_id = $1 ORDER BY started_at DESC, id DESC`
+	rows, err := r.db.QueryContext(ctx, query, activityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := make([]models.StudySession, 0)
+	for rows.Next() {
+		session, err := scanStudySession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *session)
+	}
+
+	return sessions, rows.Err()
+}
+
+func (r *StudyRepository) DeleteSession(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM study_sessions WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	return requireRowsAffected(result, apperrors.NotFound("sessão de estudo não encontrada"))
+}
+
+// StudyDates retorna, em ordem crescente, os dias com estudo: sessões com tempo
+// registrado (ou em andamento) e conclusões de atividades
+func (r *StudyRepository) StudyDates(ctx context.Context) ([]time.Time, error) {
+	query := `SELECT DISTINCT day FROM (
+	              SELECT started_at::date AS day FROM study_sessions WHERE minutes > 0 OR ended_at IS NULL
+	              UNION
+	              SELECT completed_at::date FROM educational_trail_activities WHERE completed_at IS NOT NULL
+	          ) days
+	          ORDER BY day`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	dates := make([]time.Time, 0)
+	for rows.Next() {
+		var day time.Time
+		if err := rows.Scan(&day); err != nil {
+			return nil, err
+		}
+		dates = append(dates, day)
+	}
+
+	return dates, rows.Err()
+}
+
+// WeeklyCategoryMinutes é o tempo estudado em uma semana nas trilhas de uma categoria de OKR
+type WeeklyCategoryMinutes struct {
+	WeekStart  time.Time
+	CategoryID int64
+	Name       string
+	Minutes    int
+}
+
+// WeeklyMinutes soma os minutos das sessões iniciadas a partir de since, por semana
+// (segunda a domingo) e categoria do OKR da trilha
+func (r *StudyRepository) WeeklyMinutes(ctx context.Context, since time.Time) ([]WeeklyCategoryMinutes, error) {
+	query := `SELECT date_trunc('week', ss.started_at)::date, c.id, c.name, SUM(ss.minutes)
+	          FROM study_sessions ss
+	          JOIN educational_trail_activities a ON a.id = ss.activity_id
+	          JOIN educational_trail_steps s ON s.id = a.step_id
+	          JOIN educational_trails t ON t.id = s.trail_id
+	          JOIN roadmap_items ri ON ri.id = t.roadmap_item_id
+	          JOIN roadmap_categories rc ON rc.id = ri.category_id
+	          JOIN roadmaps r ON r.id = rc.roadmap_id
+	          JOIN key_results kr ON kr.id = r.key_result_id
+	          JOIN okrs o ON o.id = kr.okr_id
+	          JOIN categories c ON c.id = o.category_id
+	          WHERE ss.started_at >= $1 AND ss.minutes > 0 AND r.deleted_at IS NULL
+	          GROUP BY 1, c.id, c.name
+	          ORDER BY 1, c.name`
+	rows, err := r.db.QueryContext(ctx, query, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	weekly := make([]WeeklyCategoryMinutes, 0)
+	for rows.Next() {
+		var w WeeklyCategoryMinutes
+		if err := rows.Scan(&w.WeekStart, &w.CategoryID, &w.Name, &w.Minutes); err != nil {
+			return nil, err
+		}
+		weekly = append(weekly, w)
+	}
+
+	return weekly, rows.Err()
+}
+
+// ActivityTime é a duração estimada (texto livre) e o tempo registrado de uma atividade concluída
+type ActivityTime struct {
+	Type          string
+	Duration      string
+	ActualMinutes int
+}
+
+// CompletedActivityTimes retorna as atividades concluídas que têm tempo registrado
+func (r *StudyRepository) CompletedActivityTimes(ctx context.Context) ([]ActivityTime, error) {
+	query := `SELECT a.activity_type, COALESCE(a.duration, ''), SUM(ss.minutes)
+	          FROM educational_trail_activities a
+	          JOIN study_sessions ss ON ss.activity_id = a.id
+	          WHERE a.completed
+	          GROUP BY a.id
+	          HAVING SUM(ss.minutes) > 0`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	times := make([]ActivityTime, 0)
+	for rows.Next() {
+		var t ActivityTime
+		if err := rows.Scan(&t.Type, &t.Duration, &t.ActualMinutes); err != nil {
+			return nil, err
+		}
+		times = append(times, t)
+	}
+
+	return times, rows.Err()
+}
+
+func requireActivity(ctx context.Context, tx *sql.Tx, activityID int64) error {
+	var exists bool
+	err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM educational_trail_activities WHERE id = $1)`, activityID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return apperrors.NotFound("atividade não encontrada")
+	}
+	return nil
+}
+
+func scanStudySession(row rowScanner) (*models.StudySession, error) {
+	var session models.StudySession
+	if err := row.Scan(&session.ID, &session.ActivityID, &session.Source, &session.StartedAt, &session.EndedAt,
+		&session.Minutes, &session.Note, &session.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
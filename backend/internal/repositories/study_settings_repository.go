@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/lib/pq"
+)
+
+type StudySettingsRepository struct {
+	db *sql.DB
+}
+
+func NewStudySettingsRepository(db *sql.DB) *StudySettingsRepository {
+	return &StudySettingsRepository{db: db}
+}
+
+// Get retorna a disponibilidade de estudo com os feriados e bloqueios cadastrados
+func (r *StudySettingsRepository) Get(ctx context.Context) (*models.StudySettings, error) {
+	query := `SELECT study_weekdays, hours_per_day, updated_at FROM study_settings WHERE id = 1`
+
+	var settings models.StudySettings
+	var weekdays pq.Int64Array
+	err := r.db.QueryRowContext(ctx, query).Scan(&weekdays, &settings.HoursPerDay, &settings.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	settings.StudyWeekdays = make([]int, len(weekdays))
+	for i, weekday := range weekdays {
+		settings.StudyWeekdays[i] = int(weekday)
+	}
+
+	settings.DaysOff, err = r.ListDaysOff(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+func (r *StudySettingsRepository) Update(ctx context.Context, weekdays []int, hoursPerDay float64) error {
+	values := make(pq.Int64Array, len(weekdays))
+	for i, weekday := range weekdays {
+		values[i] = int64(weekday)
+	}
+
+	query := `INSERT INTO study_settings (id, study_weekdays, hours_per_day, updated_at) VALUES (1, $1, $2, $3)
+	          ON CONFLICT (id) DO UPDATE SET study_weekdays = EXCLUDED.study_weekdays,
+	          hours_per_day = EXCLUDED.hours_per_day, updated_at = EXCLUDED.updated_at`
+	_, err := r.db.ExecContext(ctx, query, values, hoursPerDay, time.Now())
+	return err
+}
+
+func (r *StudySettingsRepository) ListDaysOff(ctx context.Context) ([]models.StudyDayOff, error) {
+	query := `SELECT id, kind, start_date, end_date, COALESCE(description, ''), created_at
+	          FROM study_days_off ORDER BY start_date, id`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	daysOff := make([]models.StudyDayOff, 0)
+	for rows.Next() {
+		var dayOff models.StudyDayOff
+		if err := rows.Scan(&dayOff.ID, &dayOff.Kind, &dayOff.StartDate, &dayOff.EndDate,
+			&dayOff.Description, &dayOff.CreatedAt); err != nil {
+			return nil, err
+		}
+		daysOff = append(daysOff, dayOff)
+	}
+
+	return daysOff, rows.Err()
+}
+
+func (r *StudySettingsRepository) CreateDayOff(ctx context.Context, dayOff *models.StudyDayOff) error {
+	dayOff.CreatedAt = time.Now()
+
+	query := `INSERT INTO study_days_off (kind, start_date, end_date, description, created_at)
+	          VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	return r.db.QueryRowContext(ctx, query, dayOff.Kind, dayOff.StartDate, dayOff.EndDate,
+		dayOff.Description, dayOff.CreatedAt).Scan(&dayOff.ID)
+}
+
+func (r *StudySettingsRepository) DeleteDayOff(ctx context.Context, id int64) error {
+	query := `DELETE FROM study_days_off WHERE id = $1`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	return requireRowsAffected(result, apperrors.NotFound("dia sem estudo não encontrado"))
+}
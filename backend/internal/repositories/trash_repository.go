@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/models"
+)
+
+// TrashRepository lista e expurga OKRs, Key Results e roadmaps excluídos. A exclusão e
+// a restauração ficam nos repositórios de cada entidade
+type TrashRepository struct {
+	db *sql.DB
+}
+
+func NewTrashRepository(db *sql.DB) *TrashRepository {
+	return &TrashRepository{db: db}
+}
+
+// List retorna os itens da lixeira, dos excluídos mais recentemente para os mais antigos.
+// Descendentes excluídos junto com o item (mesmo deleted_at) não aparecem separadamente
+func (r *TrashRepository) List(ctx context.Context) ([]models.TrashItem, error) {
+	query := `SELECT * FROM (
+	              SELECT 'okr' AS type, o.id, o.objective AS title, '' AS context,
+	                     o.id AS okr_id, NULL::integer AS key_result_id, o.deleted_at
+	              FROM okrs o
+	              WHERE o.deleted_at IS NOT NULL
+	              UNION ALL
+	              SELECT 'key_result', kr.id, kr.title, o.objective, kr.okr_id, kr.id, kr.deleted_at
+	              FROM key_results kr
+	              JOIN okrs o ON o.id = kr.okr_id
+	              WHERE kr.deleted_at IS NOT NULL AND o.deleted_at IS DISTINCT FROM kr.deleted_at
+	              UNION ALL
+	              SELECT 'roadmap', rm.id, rm.topic, kr.title, kr.okr_id, kr.id, rm.deleted_at
+	              FROM roadmaps rm
+	              JOIN key_results kr ON kr.id = rm.key_result_id
+	              WHERE rm.deleted_at IS NOT NULL AND kr.deleted_at IS DISTINCT FROM rm.deleted_at
+	          ) trash
+	          ORDER BY deleted_at DESC, type, id`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.TrashItem, 0)
+	for rows.Next() {
+		var item models.TrashItem
+		var keyResultID sql.NullInt64
+		if err := rows.Scan(&item.Type, &item.ID, &item.Title, &item.Context, &item.OKRID, &keyResultID, &item.DeletedAt); err != nil {
+			return nil, err
+		}
+		if keyResultID.Valid {
+			item.KeyResultID = &keyResultID.Int64
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// Purge remove definitivamente os itens excluídos antes de cutoff (com seus descendentes,
+// por cascata) e retorna quantos itens de cada tipo foram removidos
+func (r *TrashRepository) Purge(ctx context.Context, cutoff time.Time) (map[string]int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	tables := []struct {
+		kind  string
+		table string
+	}{
+		{models.TrashTypeOKR, "okrs"},
+		{models.TrashTypeKeyResult, "key_results"},
+		{models.TrashTypeRoadmap, "roadmaps"},
+	}
+
+	removed := make(map[string]int64, len(tables))
+	for _, t := range tables {
+		result, err := tx.ExecContext(ctx, `DELETE FROM `+t.table+` WHERE deleted_at < $1`, cutoff)
+		if err != nil {
+			return nil, err
+		}
+		if removed[t.kind], err = result.RowsAffected(); err != nil {
+			return nil, err
+		}
+	}
+
+	return removed, tx.Commit()
+}
+
+// inTrash informa se o retrato de uma linha (auditSnapshot) está na lixeira
+func inTrash(snapshot json.RawMessage) bool {
+	var row struct {
+		DeletedAt *string `json:"deleted_at"`
+	}
+	return json.Unmarshal(snapshot, &row) == nil && row.DeletedAt != nil
+}
+
+// activeRoadmapItem retorna a condição SQL verdadeira quando o item de roadmap indicado
+// pela coluna pertence a um roadmap fora da lixeira
+func activeRoadmapItem(column string) string {
+	return `EXISTS (SELECT 1 FROM roadmap_items ari
+	        JOIN roadmap_categories arc ON arc.id = ari.category_id
+	        JOIN roadmaps ar ON ar.id = arc.roadmap_id
+	        WHERE ari.id = ` + column + ` AND ar.deleted_at IS NULL)`
+}
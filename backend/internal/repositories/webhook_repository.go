@@ -0,0 +1,324 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/lib/pq"
+)
+
+type WebhookRepository struct {
+	db *sql.DB
+}
+
+func NewWebhookRepository(db *sql.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	now := time.Now()
+	sub.CreatedAt = now
+	sub.UpdatedAt = now
+
+	query := `INSERT INTO webhook_subscriptions (url, events, secret, active, created_at, updated_at)
+	          VALUES ($1, $2, $3, $4, $5, $5) RETURNING id`
+	return r.db.QueryRowContext(ctx, query, sub.URL, pq.StringArray(sub.Events), sub.Secret, sub.Active, now).Scan(&sub.ID)
+}
+
+func (r *WebhookRepository) ListSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	query := `SELECT id, url, events, active, created_at, updated_at FROM webhook_subscriptions ORDER BY id`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subs := make([]models.WebhookSubscription, 0)
+	for rows.Next() {
+		var sub models.WebhookSubscription
+		var events pq.StringArray
+		if err := rows.Scan(&sub.ID, &sub.URL, &events, &sub.Active, &sub.CreatedAt, &sub.UpdatedAt); err != nil {
+			return nil, err
+		}
+		sub.Events = []string(events)
+		subs = append(subs, sub)
+	}
+
+	return subs, rows.Err()
+}
+
+// GetSubscription retorna a assinatura sem o segredo
+func (r *WebhookRepository) GetSubscription(ctx context.Context, id int64) (*models.WebhookSubscription, error) {
+	query := `SELECT id, url, events, active, created_at, updated_at FROM webhook_subscriptions WHERE id = $1`
+
+	var sub models.WebhookSubscription
+	var events pq.StringArray
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&sub.ID, &sub.URL, &events, &sub.Active, &sub.CreatedAt, &sub.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	sub.Events = []string(events)
+
+	return &sub, nil
+}
+
+func (r *WebhookRepository) UpdateSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	sub.UpdatedAt = time.Now()
+
+	query := `UPDATE webhook_subscriptions SET url = $1, events = $2, active = $3, updated_at = $4 WHERE id = $5`
+	result, err := r.db.ExecContext(ctx, query, sub.URL, pq.StringArray(sub.Events), sub.Active, sub.UpdatedAt, sub.ID)
+	if err != nil {
+		return err
+	}
+	return requireRowsAffected(result, apperrors.NotFound("assinatura de webhook não encontrada"))
+}
+
+func (r *WebhookRepository) UpdateSecret(ctx context.Context, id int64, secret string) error {
+	query := `UPDATE webhook_subscriptions SET secret = $1, updated_at = $2 WHERE id = $3`
+	result, err := r.db.ExecContext(ctx, query, secret, time.Now(), id)
+	if err != nil {
+		return err
+	}
+	return requireRowsAffected(result, apperrors.NotFound("assinatura de webhook não encontrada"))
+}
+
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, id int64) error {
+	query := `DELETE FROM webhook_subscriptions WHERE id = $1`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	return requireRowsAffected(result, apperrors.NotFound("assinatura de webhook não encontrada"))
+}
+
+// DispatchOutbox distribui os eventos ainda não processados da outbox, criando uma
+// entrega para cada assinatura ativa interessada. Eventos sem assinantes são apenas
+// marcados como processados. Retorna quantas entregas foram criadas
+func (r *WebhookRepository) DispatchOutbox(ctx context.Context, now time.Time, limit int) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `SELECT id FROM webhook_outbox
+	          WHERE dispatched_at IS NULL
+	          ORDER BY id
+	          LIMIT $1
+	          FOR UPDATE SKIP LOCKED`, limit)
+	if err != nil {
+		return 0, err
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	result, err := tx.ExecContext(ctx, `INSERT INTO webhook_deliveries (subscription_id, outbox_id, event_type, next_attempt_at, created_at)
+	          SELECT s.id, o.id, o.event_type, $2, $2
+	          FROM webhook_outbox o
+	          JOIN webhook_subscriptions s ON s.active AND o.event_type = ANY(s.events)
+	          WHERE o.id = ANY($1)
+	          ON CONFLICT (outbox_id, subscription_id) DO NOTHING`, pq.Int64Array(ids), now)
+	if err != nil {
+		return 0, err
+	}
+	created, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE webhook_outbox SET dispatched_at = $2 WHERE id = ANY($1)`,
+		pq.Int64Array(ids), now); err != nil {
+		return 0, err
+	}
+
+	return int(created), tx.Commit()
+}
+
+// WebhookJob é uma entrega pendente com o destino e o evento a enviar
+type WebhookJob struct {
+	DeliveryID int64
+	Attempts   int
+	URL        string
+	Secret     string
+	Event      models.WebhookEvent
+}
+
+// DueDeliveries retorna as entregas pendentes cuja próxima tentativa já chegou.
+// Assinaturas desativadas depois da criação da entrega são ignoradas
+func (r *WebhookRepository) DueDeliveries(ctx context.Context, now time.Time, limit int) ([]WebhookJob, error) {
+	query := `SELECT d.id, d.attempts, s.url, s.secret, o.id, o.event_type, o.created_at, o.payload
+	          FROM webhook_deliveries d
+	          JOIN webhook_subscriptions s ON s.id = d.subscription_id
+	          JOIN webhook_outbox o ON o.id = d.outbox_id
+	          WHERE d.status = 'pending' AND d.next_attempt_at <= $1 AND s.active
+	          ORDER BY d.next_attempt_at, d.id
+	          LIMIT $2`
+	rows, err := r.db.QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := make([]WebhookJob, 0)
+	for rows.Next() {
+		var job WebhookJob
+		var eventID int64
+		var payload []byte
+		if err := rows.Scan(&job.DeliveryID, &job.Attempts, &job.URL, &job.Secret, &eventID,
+			&job.Event.Type, &job.Event.OccurredAt, &payload); err != nil {
+			return nil, err
+		}
+		job.Event.ID = webhookEventID(eventID)
+		job.Event.Data = payload
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
+
+// WebhookAttempt é o resultado de uma tentativa de entrega
+type WebhookAttempt struct {
+	At             time.Time
+	ResponseStatus *int
+	ResponseBody   string
+	Error          string
+	Duration       time.Duration
+	// Succeeded indica resposta 2xx; com NextAttempt nulo uma falha é definitiva
+	Succeeded   bool
+	NextAttempt *time.Time
+}
+
+// RecordAttempt registra a tentativa e atualiza a situação da entrega
+func (r *WebhookRepository) RecordAttempt(ctx context.Context, deliveryID int64, attempt WebhookAttempt) error {
+	status := models.WebhookDeliveryPending
+	var deliveredAt *time.Time
+	switch {
+	case attempt.Succeeded:
+		status = models.WebhookDeliverySucceeded
+		deliveredAt = &attempt.At
+	case attempt.NextAttempt == nil:
+		status = models.WebhookDeliveryFailed
+	}
+
+	var lastError sql.NullString
+	if attempt.Error != "" {
+		lastError = sql.NullString{String: attempt.Error, Valid: true}
+	}
+
+	query := `UPDATE webhook_deliveries
+	          SET status = $1, attempts = attempts + 1, next_attempt_at = COALESCE($2, next_attempt_at),
+	              response_status = $3, response_body = $4, last_error = $5, duration_ms = $6,
+	              last_attempt_at = $7, delivered_at = $8
+	          WHERE id = $9`
+	_, err := r.db.ExecContext(ctx, query, status, attempt.NextAttempt, attempt.ResponseStatus, attempt.ResponseBody,
+		lastError, attempt.Duration.Milliseconds(), attempt.At, deliveredAt, deliveryID)
+	return err
+}
+
+// ListDeliveries retorna o log de entregas de uma assinatura, das mais recentes para as mais antigas
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+	query := `SELECT id, subscription_id, outbox_id, event_type, status, attempts, next_attempt_at,
+	                 response_status, COALESCE(response_body, ''), COALESCE(last_error, ''), duration_ms,
+	                 created_at, last_attempt_at, delivered_at
+	          FROM webhook_deliveries
+	          WHERE subscription_id = $1 AND ($2 = '' OR status = $2)
+	          ORDER BY id DESC
+	          LIMIT $3`
+	rows, err := r.db.QueryContext(ctx, query, filter.SubscriptionID, filter.Status, filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]models.WebhookDelivery, 0)
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *d)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// Redeliver agenda uma nova entrega imediata, zerando as tentativas
+func (r *WebhookRepository) Redeliver(ctx context.Context, subscriptionID, deliveryID int64, now time.Time) (*models.WebhookDelivery, error) {
+	query := `UPDATE webhook_deliveries
+	          SET status = 'pending', attempts = 0, next_attempt_at = $1, delivered_at = NULL
+	          WHERE id = $2 AND subscription_id = $3
+	          RETURNING id, subscription_id, outbox_id, event_type, status, attempts, next_attempt_at,
+	                    response_status, COALESCE(response_body, ''), COALESCE(last_error, ''), duration_ms,
+	                    created_at, last_attempt_at, delivered_at`
+	d, err := scanWebhookDelivery(r.db.QueryRowContext(ctx, query, now, deliveryID, subscriptionID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperrors.NotFound("entrega de webhook não encontrada")
+		}
+		return nil, err
+	}
+	return d, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanWebhookDelivery(row rowScanner) (*models.WebhookDelivery, error) {
+	var d models.WebhookDelivery
+	var eventID int64
+	var nextAttemptAt, lastAttemptAt, deliveredAt sql.NullTime
+	var responseStatus, durationMs sql.NullInt64
+	if err := row.Scan(&d.ID, &d.SubscriptionID, &eventID, &d.EventType, &d.Status, &d.Attempts, &nextAttemptAt,
+		&responseStatus, &d.ResponseBody, &d.LastError, &durationMs,
+		&d.CreatedAt, &lastAttemptAt, &deliveredAt); err != nil {
+		return nil, err
+	}
+
+	d.EventID = webhookEventID(eventID)
+	if nextAttemptAt.Valid && d.Status == models.WebhookDeliveryPending {
+		d.NextAttemptAt = &nextAttemptAt.Time
+	}
+	if responseStatus.Valid {
+		status := int(responseStatus.Int64)
+		d.ResponseStatus = &status
+	}
+	if durationMs.Valid {
+		ms := int(durationMs.Int64)
+		d.DurationMs = &ms
+	}
+	if lastAttemptAt.Valid {
+		d.LastAttemptAt = &lastAttemptAt.Time
+	}
+	if deliveredAt.Valid {
+		d.DeliveredAt = &deliveredAt.Time
+	}
+
+	return &d, nil
+}
+
+// webhookEventID é o identificador público do evento (estável entre novas tentativas)
+func webhookEventID(outboxID int64) string {
+	return fmt.Sprintf("evt_%d", outboxID)
+}
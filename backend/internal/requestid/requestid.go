@@ -0,0 +1,31 @@
+// Package requestid guarda o identificador da requisição no context.Context,
+// permitindo que ele seja registrado nos logs e repassado às chamadas ao Spellbook.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Header é o cabeçalho HTTP usado para receber e propagar o ID da requisição
+const Header = "X-Request-ID"
+
+type contextKey struct{}
+
+// New gera um novo ID aleatório de 16 bytes em hexadecimal
+func New() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext retorna o ID da requisição ou string vazia se não houver
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
@@ -0,0 +1,122 @@
+// Package risk classifica OKRs, Key Results e trilhas educacionais como em dia
+// (on_track), em risco (at_risk) ou atrasados (off_track), comparando o tempo já
+// decorrido do prazo com o progresso concluído.
+package risk
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/config"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/conquista-ai/conquista-ai/internal/planning"
+)
+
+type Engine struct {
+	atRiskGap   float64
+	offTrackGap float64
+	clock       planning.Clock
+}
+
+func NewEngine(cfg config.RiskConfig, clock planning.Clock) *Engine {
+	return &Engine{
+		atRiskGap:   float64(cfg.AtRiskGapPercent) / 100,
+		offTrackGap: float64(cfg.OffTrackGapPercent) / 100,
+		clock:       clock,
+	}
+}
+
+// Now retorna o instante atual segundo o relógio do engine
+func (e *Engine) Now() time.Time {
+	return e.clock.Now()
+}
+
+// Assess avalia um item iniciado em start com prazo due e a fração concluída progress.
+// As datas são comparadas por dia de calendário; o dia do prazo ainda conta como em dia
+func (e *Engine) Assess(start time.Time, due *time.Time, progress float64) models.RiskAssessment {
+	progress = clamp(progress)
+	assessment := models.RiskAssessment{
+		Level:    models.RiskOnTrack,
+		Progress: round(progress),
+		DueDate:  due,
+	}
+
+	if progress >= 1 {
+		assessment.Reason = "concluído"
+		return assessment
+	}
+	if due == nil {
+		assessment.Reason = "sem prazo definido"
+		return assessment
+	}
+
+	today := planning.Date(e.clock.Now())
+	startDay := calendarDate(start, today.Location())
+	dueDay := calendarDate(*due, today.Location())
+
+	daysRemaining := daysBetween(today, dueDay)
+	assessment.DaysRemaining = &daysRemaining
+
+	if daysRemaining < 0 {
+		elapsed := 1.0
+		assessment.TimeElapsed = &elapsed
+		assessment.Overdue = true
+		assessment.Level = models.RiskOffTrack
+		assessment.Reason = fmt.Sprintf("prazo vencido há %d dia(s) com %.0f%% concluído", -daysRemaining, progress*100)
+		return assessment
+	}
+
+	// O prazo inclui o próprio dia de vencimento
+	totalDays := daysBetween(startDay, dueDay) + 1
+	elapsed := 0.0
+	if totalDays > 0 {
+		elapsed = round(clamp(float64(daysBetween(startDay, today)) / float64(totalDays)))
+	}
+	assessment.TimeElapsed = &elapsed
+
+	gap := elapsed - progress
+	switch {
+	case gap >= e.offTrackGap:
+		assessment.Level = models.RiskOffTrack
+	case gap >= e.atRiskGap:
+		assessment.Level = models.RiskAtRisk
+	}
+	assessment.Reason = fmt.Sprintf("%.0f%% do prazo decorrido e %.0f%% concluído", elapsed*100, progress*100)
+
+	return assessment
+}
+
+// Escalate piora a avaliação para level quando ele é mais grave, registrando o motivo.
+// Usado para refletir no OKR os Key Results atrasados
+func Escalate(assessment *models.RiskAssessment, level, reason string) {
+	if severity(level) > severity(assessment.Level) {
+		assessment.Level = level
+		assessment.Reason = reason
+	}
+}
+
+func severity(level string) int {
+	return slices.Index(models.RiskLevels, level)
+}
+
+// calendarDate retorna a meia-noite, no fuso loc, da data de calendário de t. Colunas
+// DATE são lidas em UTC, então a conversão de fuso mudaria o dia
+func calendarDate(t time.Time, loc *time.Location) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(math.Round(to.Sub(from).Hours() / 24))
+}
+
+func clamp(value float64) float64 {
+	return math.Min(math.Max(value, 0), 1)
+}
+
+// round mantém duas casas decimais nas frações expostas na API
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,226 @@
+package risk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/config"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/conquista-ai/conquista-ai/internal/planning"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestEngineAssess(t *testing.T) {
+	now := time.Date(2026, 3, 11, 14, 0, 0, 0, time.UTC)
+	start := date(2026, 3, 2)
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	tests := []struct {
+		name          string
+		now           time.Time
+		start         time.Time
+		due           *time.Time
+		progress      float64
+		level         string
+		overdue       bool
+		daysRemaining *int
+		elapsed       *float64
+		reason        string
+	}{
+		{
+			name:     "sem prazo",
+			start:    start,
+			progress: 0.2,
+			level:    models.RiskOnTrack,
+			reason:   "sem prazo definido",
+		},
+		{
+			name:     "concluído com prazo vencido",
+			start:    start,
+			due:      ptr(date(2026, 3, 1)),
+			progress: 1,
+			level:    models.RiskOnTrack,
+			reason:   "concluído",
+		},
+		{
+			name:     "progresso acima de 100%",
+			start:    start,
+			due:      ptr(date(2026, 3, 20)),
+			progress: 1.5,
+			level:    models.RiskOnTrack,
+			reason:   "concluído",
+		},
+		{
+			name:          "vencido ontem",
+			start:         start,
+			due:           ptr(date(2026, 3, 10)),
+			progress:      0.5,
+			level:         models.RiskOffTrack,
+			overdue:       true,
+			daysRemaining: intPtr(-1),
+			elapsed:       floatPtr(1),
+			reason:        "prazo vencido há 1 dia(s) com 50% concluído",
+		},
+		{
+			name:          "vencido com progresso negativo",
+			start:         start,
+			due:           ptr(date(2026, 3, 1)),
+			progress:      -0.3,
+			level:         models.RiskOffTrack,
+			overdue:       true,
+			daysRemaining: intPtr(-10),
+			elapsed:       floatPtr(1),
+			reason:        "prazo vencido há 10 dia(s) com 0% concluído",
+		},
+		{
+			name:          "dia do prazo ainda conta como em dia",
+			start:         start,
+			due:           ptr(date(2026, 3, 11)),
+			progress:      0.9,
+			level:         models.RiskOnTrack,
+			daysRemaining: intPtr(0),
+			elapsed:       floatPtr(0.9),
+			reason:        "90% do prazo decorrido e 90% concluído",
+		},
+		{
+			name:          "dia do prazo em outro fuso",
+			now:           time.Date(2026, 3, 11, 22, 0, 0, 0, time.FixedZone("BRT", -3*3600)),
+			start:         start,
+			due:           ptr(date(2026, 3, 11)),
+			progress:      0.9,
+			level:         models.RiskOnTrack,
+			daysRemaining: intPtr(0),
+			elapsed:       floatPtr(0.9),
+			reason:        "90% do prazo decorrido e 90% concluído",
+		},
+		{
+			name:          "dia do prazo em risco",
+			start:         start,
+			due:           ptr(date(2026, 3, 11)),
+			progress:      0.7,
+			level:         models.RiskAtRisk,
+			daysRemaining: intPtr(0),
+			elapsed:       floatPtr(0.9),
+			reason:        "90% do prazo decorrido e 70% concluído",
+		},
+		{
+			name:          "dia do prazo atrasado",
+			start:         start,
+			due:           ptr(date(2026, 3, 11)),
+			progress:      0.5,
+			level:         models.RiskOffTrack,
+			daysRemaining: intPtr(0),
+			elapsed:       floatPtr(0.9),
+			reason:        "90% do prazo decorrido e 50% concluído",
+		},
+		{
+			name:          "abaixo do limite de risco",
+			start:         start,
+			due:           ptr(date(2026, 3, 21)),
+			progress:      0.31,
+			level:         models.RiskOnTrack,
+			daysRemaining: intPtr(10),
+			elapsed:       floatPtr(0.45),
+		},
+		{
+			name:          "no limite de risco",
+			start:         start,
+			due:           ptr(date(2026, 3, 21)),
+			progress:      0.25,
+			level:         models.RiskAtRisk,
+			daysRemaining: intPtr(10),
+			elapsed:       floatPtr(0.45),
+		},
+		{
+			name:          "no limite de atraso",
+			start:         start,
+			due:           ptr(date(2026, 3, 21)),
+			progress:      0.05,
+			level:         models.RiskOffTrack,
+			daysRemaining: intPtr(10),
+			elapsed:       floatPtr(0.45),
+		},
+		{
+			name:          "início no futuro",
+			start:         date(2026, 3, 15),
+			due:           ptr(date(2026, 3, 20)),
+			progress:      0,
+			level:         models.RiskOnTrack,
+			daysRemaining: intPtr(9),
+			elapsed:       floatPtr(0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := planning.FixedClock{Time: now}
+			if !tt.now.IsZero() {
+				clock.Time = tt.now
+			}
+			engine := NewEngine(config.RiskConfig{AtRiskGapPercent: 20, OffTrackGapPercent: 40}, clock)
+
+			got := engine.Assess(tt.start, tt.due, tt.progress)
+			if got.Level != tt.level {
+				t.Errorf("Level = %q, want %q (%s)", got.Level, tt.level, got.Reason)
+			}
+			if got.Overdue != tt.overdue {
+				t.Errorf("Overdue = %v, want %v", got.Overdue, tt.overdue)
+			}
+			if !equalPtr(got.DaysRemaining, tt.daysRemaining) {
+				t.Errorf("DaysRemaining = %v, want %v", deref(got.DaysRemaining), deref(tt.daysRemaining))
+			}
+			if !equalPtr(got.TimeElapsed, tt.elapsed) {
+				t.Errorf("TimeElapsed = %v, want %v", deref(got.TimeElapsed), deref(tt.elapsed))
+			}
+			if tt.reason != "" && got.Reason != tt.reason {
+				t.Errorf("Reason = %q, want %q", got.Reason, tt.reason)
+			}
+		})
+	}
+}
+
+func TestEscalate(t *testing.T) {
+	tests := []struct {
+		name   string
+		from   string
+		to     string
+		level  string
+		reason string
+	}{
+		{"piora de em dia para atrasado", models.RiskOnTrack, models.RiskOffTrack, models.RiskOffTrack, "escalado"},
+		{"piora de em dia para em risco", models.RiskOnTrack, models.RiskAtRisk, models.RiskAtRisk, "escalado"},
+		{"não melhora a avaliação", models.RiskOffTrack, models.RiskAtRisk, models.RiskOffTrack, "original"},
+		{"mesmo nível mantém o motivo", models.RiskAtRisk, models.RiskAtRisk, models.RiskAtRisk, "original"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assessment := models.RiskAssessment{Level: tt.from, Reason: "original"}
+			Escalate(&assessment, tt.to, "escalado")
+			if assessment.Level != tt.level || assessment.Reason != tt.reason {
+				t.Errorf("Escalate = (%q, %q), want (%q, %q)", assessment.Level, assessment.Reason, tt.level, tt.reason)
+			}
+		})
+	}
+}
+
+func intPtr(v int) *int { return &v }
+
+func floatPtr(v float64) *float64 { return &v }
+
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func deref[T any](p *T) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
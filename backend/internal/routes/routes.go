@@ -3,7 +3,10 @@ package routes
 import (
 	"github.com/conquista-ai/conquista-ai/internal/handlers"
 	"github.com/conquista-ai/conquista-ai/internal/middleware"
+	"github.com/conquista-ai/conquista-ai/internal/telemetry"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 func SetupRoutes(
@@ -12,16 +15,42 @@ func SetupRoutes(
 	okrHandler *handlers.OKRHandler,
 	keyResultHandler *handlers.KeyResultHandler,
 	roadmapHandler *handlers.RoadmapHandler,
+	searchHandler *handlers.SearchHandler,
+	studySettingsHandler *handlers.StudySettingsHandler,
+	calendarHandler *handlers.CalendarHandler,
+	reportHandler *handlers.ReportHandler,
+	notificationHandler *handlers.NotificationHandler,
+	webhookHandler *handlers.WebhookHandler,
+	studyHandler *handlers.StudyHandler,
+	dashboardHandler *handlers.DashboardHandler,
+	auditHandler *handlers.AuditHandler,
+	trashHandler *handlers.TrashHandler,
+	backupHandler *handlers.BackupHandler,
+	shareHandler *handlers.ShareHandler,
+	healthHandler *handlers.HealthHandler,
+	corsOrigins []string,
 ) {
-	middleware.SetupCORS(router)
+	middleware.SetupCORS(router, corsOrigins)
+	router.Use(
+		middleware.RequestID(),
+		middleware.Actor(),
+		otelgin.Middleware(telemetry.ServiceName),
+		middleware.RequestLogger(),
+		middleware.Metrics(),
+		middleware.ErrorHandler(),
+	)
 
-	// Health check
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status":  "ok",
-			"service": "conquista-ai",
-		})
-	})
+	// Health checks: /health é mantido como alias do probe de readiness
+	router.GET("/livez", healthHandler.Livez)
+	router.GET("/readyz", healthHandler.Readyz)
+	router.GET("/health", healthHandler.Readyz)
+	router.GET("/version", healthHandler.Version)
+
+	// Métricas Prometheus
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	// Visão pública de roadmaps compartilhados (autenticada apenas pelo token do link)
+	router.GET("/public/share/:token", shareHandler.View)
 
 	// API v1
 	api := router.Group("/api/v1")
@@ -36,6 +65,8 @@ func SetupRoutes(
 		// OKRs
 		api.GET("/okrs", okrHandler.GetAll)
 		api.POST("/okrs", okrHandler.Create)
+		api.GET("/okrs/export.csv", keyResultHandler.ExportCSV)
+		api.GET("/okrs/export.xlsx", keyResultHandler.ExportXLSX)
 
 		// Rotas específicas de OKR (devem vir antes das genéricas)
 		okrs := api.Group("/okrs/:id")
@@ -43,7 +74,13 @@ func SetupRoutes(
 			okrs.GET("", okrHandler.GetByID)
 			okrs.PUT("", okrHandler.Update)
 			okrs.DELETE("", okrHandler.Delete)
+			okrs.POST("/restore", trashHandler.RestoreOKR)
+			okrs.POST("/archive", okrHandler.Archive)
+			okrs.POST("/unarchive", okrHandler.Unarchive)
+			okrs.POST("/status", okrHandler.ChangeStatus)
+			okrs.GET("/status-history", okrHandler.StatusHistory)
 			okrs.POST("/generate-key-results", okrHandler.GenerateKeyResults)
+			okrs.POST("/schedule", okrHandler.Schedule)
 			okrs.GET("/key-results", keyResultHandler.GetByOKRID)
 		}
 		// Rotas específicas de Key Results com roadmap (devem vir antes das genéricas)
@@ -52,7 +89,13 @@ func SetupRoutes(
 			keyResults.POST("/roadmap", roadmapHandler.GenerateRoadmap)
 			keyResults.GET("/roadmap", roadmapHandler.GetByKeyResultID)
 			keyResults.DELETE("/roadmap", roadmapHandler.DeleteRoadmap)
+			keyResults.GET("/roadmap/export", roadmapHandler.ExportRoadmap)
+			keyResults.POST("/roadmap/share", shareHandler.Create)
+			keyResults.GET("/roadmap/shares", shareHandler.List)
+			keyResults.POST("/restore", trashHandler.RestoreKeyResult)
 		}
+		api.POST("/roadmaps/:id/restore", trashHandler.RestoreRoadmap)
+		api.DELETE("/share-links/:id", shareHandler.Revoke)
 		api.PUT("/roadmap-items/:item_id", roadmapHandler.UpdateItem)
 
 		// Key Results - rota para buscar todos (deve vir antes das rotas específicas)
@@ -70,6 +113,64 @@ func SetupRoutes(
 		api.POST("/educational-trail", roadmapHandler.GenerateEducationalTrail)
 		api.GET("/roadmap-items/:roadmap_item_id/educational-trail", roadmapHandler.GetEducationalTrailByRoadmapItemID)
 		api.DELETE("/roadmap-items/:roadmap_item_id/educational-trail", roadmapHandler.DeleteEducationalTrail)
+		api.GET("/roadmap-items/:roadmap_item_id/educational-trail/export", roadmapHandler.ExportEducationalTrail)
+		api.POST("/roadmap-items/:roadmap_item_id/educational-trail/replan", roadmapHandler.ReplanEducationalTrail)
 		api.PUT("/trail-activities/:activity_id", roadmapHandler.UpdateTrailActivity)
+
+		// Tempo de estudo: cronômetro, lançamentos manuais e estatísticas
+		api.POST("/trail-activities/:activity_id/timer/start", studyHandler.StartTimer)
+		api.POST("/trail-activities/:activity_id/timer/stop", studyHandler.StopTimer)
+		api.GET("/trail-activities/:activity_id/sessions", studyHandler.ListSessions)
+		api.POST("/trail-activities/:activity_id/sessions", studyHandler.CreateSession)
+		api.DELETE("/study-sessions/:id", studyHandler.DeleteSession)
+		api.GET("/stats/study", studyHandler.Stats)
+
+		// Disponibilidade de estudo (dias da semana, horas, feriados e bloqueios)
+		api.GET("/settings/study", studySettingsHandler.Get)
+		api.PUT("/settings/study", studySettingsHandler.Update)
+		api.POST("/settings/study/days-off", studySettingsHandler.CreateDayOff)
+		api.DELETE("/settings/study/days-off/:id", studySettingsHandler.DeleteDayOff)
+
+		// Notificações: caixa de entrada e preferências
+		api.GET("/notifications", notificationHandler.Inbox)
+		api.POST("/notifications/read-all", notificationHandler.MarkAllRead)
+		api.POST("/notifications/test", notificationHandler.SendTest)
+		api.POST("/notifications/:id/read", notificationHandler.MarkRead)
+		api.GET("/settings/notifications", notificationHandler.GetPreferences)
+		api.PUT("/settings/notifications", notificationHandler.UpdatePreferences)
+
+		// Webhooks de integração (eventos de domínio assinados com HMAC)
+		api.GET("/webhooks", webhookHandler.List)
+		api.POST("/webhooks", webhookHandler.Create)
+		api.GET("/webhooks/:id", webhookHandler.Get)
+		api.PUT("/webhooks/:id", webhookHandler.Update)
+		api.DELETE("/webhooks/:id", webhookHandler.Delete)
+		api.POST("/webhooks/:id/rotate-secret", webhookHandler.RotateSecret)
+		api.GET("/webhooks/:id/deliveries", webhookHandler.Deliveries)
+		api.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", webhookHandler.Redeliver)
+
+		// Feed iCalendar (autenticado pelo token secreto no parâmetro token)
+		api.GET("/calendar.ics", calendarHandler.Feed)
+		api.POST("/calendar/token", calendarHandler.RotateToken)
+
+		// Dashboard (agregado em SQL, com cache curto)
+		api.GET("/dashboard", dashboardHandler.Get)
+
+		// Log de auditoria das mudanças (?entity=okr&id=)
+		api.GET("/activity", auditHandler.List)
+
+		// Lixeira (itens excluídos que ainda podem ser restaurados)
+		api.GET("/trash", trashHandler.List)
+
+		// Backup completo dos dados (JSON versionado)
+		api.GET("/export", backupHandler.Export)
+		api.POST("/import", backupHandler.Import)
+		api.POST("/import/okrs.csv", okrHandler.ImportCSV)
+
+		// Relatórios
+		api.GET("/reports/risk", reportHandler.Risk)
+
+		// Busca textual
+		api.GET("/search", searchHandler.Search)
 	}
 }
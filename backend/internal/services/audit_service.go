@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/logging"
+	"github.com/conquista-ai/conquista-ai/internal/planning"
+	"github.com/conquista-ai/conquista-ai/internal/repositories"
+)
+
+// AuditRetentionService aplica a política de retenção do log de auditoria
+type AuditRetentionService struct {
+	repo      *repositories.AuditRepository
+	clock     planning.Clock
+	retention time.Duration
+}
+
+func NewAuditRetentionService(repo *repositories.AuditRepository, clock planning.Clock, retentionDays int) *AuditRetentionService {
+	return &AuditRetentionService{
+		repo:      repo,
+		clock:     clock,
+		retention: time.Duration(retentionDays) * 24 * time.Hour,
+	}
+}
+
+// Run remove os eventos mais antigos que o período de retenção
+func (s *AuditRetentionService) Run(ctx context.Context) error {
+	removed, err := s.repo.DeleteBefore(ctx, s.clock.Now().Add(-s.retention))
+	if err != nil {
+		return fmt.Errorf("erro ao remover eventos de auditoria antigos: %w", err)
+	}
+	if removed > 0 {
+		logging.FromContext(ctx).Info("eventos de auditoria antigos removidos", "events", removed)
+	}
+	return nil
+}
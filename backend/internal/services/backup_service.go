@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+	"github.com/conquista-ai/conquista-ai/internal/logging"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/conquista-ai/conquista-ai/internal/planning"
+	"github.com/conquista-ai/conquista-ai/internal/repositories"
+)
+
+// BackupService exporta e importa todos os dados no formato de backup versionado.
+// Usado pela API e pelos subcomandos export/import do servidor
+type BackupService struct {
+	repo  *repositories.BackupRepository
+	clock planning.Clock
+}
+
+func NewBackupService(repo *repositories.BackupRepository, clock planning.Clock) *BackupService {
+	return &BackupService{repo: repo, clock: clock}
+}
+
+// Export monta o arquivo de backup com todos os dados fora da lixeira
+func (s *BackupService) Export(ctx context.Context) (*models.Backup, error) {
+	backup, err := s.repo.Export(ctx)
+	if err != nil {
+		return nil, apperrors.Wrap("erro ao exportar dados", err)
+	}
+	backup.ExportedAt = s.clock.Now().UTC()
+	return backup, nil
+}
+
+// Import valida o arquivo e grava seu conteúdo. strategy vazio equivale a skip; com
+// dryRun nada é gravado, mas o resultado mostra o que seria feito
+func (s *BackupService) Import(ctx context.Context, backup *models.Backup, strategy string, dryRun bool) (*models.ImportResult, error) {
+	if strategy == "" {
+		strategy = models.ImportSkip
+	}
+	if !slices.Contains(models.ImportStrategies, strategy) {
+		return nil, apperrors.InvalidField("strategy", "estratégia inválida: "+strategy+". Use "+strings.Join(models.ImportStrategies, ", "))
+	}
+	if err := backup.Validate(); err != nil {
+		return nil, apperrors.Validation("arquivo de backup inválido: " + err.Error())
+	}
+
+	result, err := s.repo.Import(ctx, backup, strategy, dryRun, s.clock.Now())
+	if err != nil {
+		return nil, apperrors.Wrap("erro ao importar dados", err)
+	}
+
+	logging.FromContext(ctx).Info("backup importado",
+		"strategy", strategy,
+		"dry_run", dryRun,
+		"okrs", result.Created[models.AuditEntityOKR],
+		"skipped", result.Skipped,
+		"overwritten", result.Overwritten)
+	return result, nil
+}
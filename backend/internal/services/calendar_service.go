@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+	"github.com/conquista-ai/conquista-ai/internal/ical"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/conquista-ai/conquista-ai/internal/repositories"
+)
+
+const (
+	calendarProdID = "-//Conquista AI//Planejamento//PT-BR"
+	calendarName   = "Conquista AI"
+	// Intervalo de atualização sugerido aos aplicativos que assinam o feed
+	calendarRefreshInterval = time.Hour
+	// Domínio dos UIDs: o UID de cada item depende apenas da origem e do ID, então
+	// alterações de data ou título atualizam o item existente no calendário
+	calendarUIDDomain = "conquista-ai"
+)
+
+type CalendarService struct {
+	calendarRepo      *repositories.CalendarRepository
+	studySettingsRepo *repositories.StudySettingsRepository
+}
+
+func NewCalendarService(calendarRepo *repositories.CalendarRepository, studySettingsRepo *repositories.StudySettingsRepository) *CalendarService {
+	return &CalendarService{
+		calendarRepo:      calendarRepo,
+		studySettingsRepo: studySettingsRepo,
+	}
+}
+
+// RotateFeedToken gera um novo token secreto para o feed, revogando o anterior
+func (s *CalendarService) RotateFeedToken(ctx context.Context) (string, time.Time, error) {
+	token, err := newToken()
+	if err != nil {
+		return "", time.Time{}, apperrors.Internal("erro ao gerar token do calendário", err)
+	}
+
+	createdAt, err := s.calendarRepo.ReplaceFeedToken(ctx, hashToken(token))
+	if err != nil {
+		return "", time.Time{}, apperrors.Internal("erro ao salvar token do calendário", err)
+	}
+	return token, createdAt, nil
+}
+
+// Feed valida o token e monta o calendário com os prazos de OKRs, as datas esperadas
+// dos Key Results (como tarefas) e as etapas agendadas das trilhas (como sessões de estudo)
+func (s *CalendarService) Feed(ctx context.Context, token string) (*ical.Calendar, error) {
+	if token == "" {
+		return nil, apperrors.Unauthorized("informe o token do calendário no parâmetro token")
+	}
+	valid, err := s.calendarRepo.UseFeedToken(ctx, hashToken(token))
+	if err != nil {
+		return nil, apperrors.Internal("erro ao validar token do calendário", err)
+	}
+	if !valid {
+		return nil, apperrors.Unauthorized("token do calendário inválido ou revogado")
+	}
+
+	entries, err := s.calendarRepo.Entries(ctx)
+	if err != nil {
+		return nil, apperrors.Internal("erro ao buscar datas do planejamento", err)
+	}
+	settings, err := s.studySettingsRepo.Get(ctx)
+	if err != nil {
+		return nil, apperrors.Internal("erro ao buscar configurações de estudo", err)
+	}
+
+	calendar := &ical.Calendar{
+		ProdID:          calendarProdID,
+		Name:            calendarName,
+		RefreshInterval: calendarRefreshInterval,
+		Components:      make([]ical.Component, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		calendar.Components = append(calendar.Components, calendarComponent(entry, settings.HoursPerDay))
+	}
+	return calendar, nil
+}
+
+func calendarComponent(entry models.CalendarEntry, hoursPerDay float64) ical.Component {
+	comp := ical.Component{
+		UID:       fmt.Sprintf("%s-%d@%s", entry.Kind, entry.ID, calendarUIDDomain),
+		Date:      entry.Date,
+		Completed: entry.Completed,
+		Modified:  entry.UpdatedAt,
+	}
+
+	switch entry.Kind {
+	case models.CalendarEntryOKR:
+		comp.Kind = ical.KindEvent
+		comp.Summary = "Prazo do OKR: " + entry.Title
+		comp.Categories = []string{"OKR"}
+	case models.CalendarEntryKeyResult:
+		comp.Kind = ical.KindTodo
+		comp.Summary = entry.Title
+		comp.Description = "Key Result do OKR: " + entry.Context
+		comp.Categories = []string{"Key Result"}
+	case models.CalendarEntryTrailStep:
+		comp.Kind = ical.KindEvent
+		comp.Summary = "Estudo: " + entry.Title
+		comp.Description = fmt.Sprintf("Trilha: %s\nTempo de estudo previsto: %sh", entry.Context,
+			strconv.FormatFloat(hoursPerDay, 'f', -1, 64))
+		if entry.Completed {
+			comp.Description += "\nEtapa concluída"
+		}
+		comp.Categories = []string{"Estudo"}
+	}
+
+	return comp
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/conquista-ai/conquista-ai/internal/planning"
+	"github.com/conquista-ai/conquista-ai/internal/repositories"
+)
+
+// Janela e limites das listas do dashboard
+const (
+	dashboardUpcomingDays  = 14
+	dashboardUpcomingLimit = 10
+	dashboardActivityLimit = 10
+)
+
+// DashboardService calcula o dashboard e o reaproveita durante o ttl, para que
+// recarregamentos seguidos da página não repitam a consulta agregada
+type DashboardService struct {
+	repo  *repositories.DashboardRepository
+	clock planning.Clock
+	ttl   time.Duration
+
+	mu     sync.Mutex
+	cached *models.Dashboard
+}
+
+func NewDashboardService(repo *repositories.DashboardRepository, clock planning.Clock, ttl time.Duration) *DashboardService {
+	return &DashboardService{repo: repo, clock: clock, ttl: ttl}
+}
+
+// Get retorna o dashboard em cache ou o recalcula quando o cache expirou
+func (s *DashboardService) Get(ctx context.Context) (*models.Dashboard, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	if s.cached != nil && now.Sub(s.cached.GeneratedAt) < s.ttl {
+		return s.cached, nil
+	}
+
+	dashboard, err := s.repo.Get(ctx, planning.Date(now), dashboardUpcomingDays, dashboardUpcomingLimit, dashboardActivityLimit)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao calcular dashboard: %w", err)
+	}
+	dashboard.GeneratedAt = now
+
+	dashboard.Totals.CompletionRate = completionRate(dashboard.Totals.CompletedKeyResults, dashboard.Totals.KeyResults)
+	for i := range dashboard.Categories {
+		category := &dashboard.Categories[i]
+		category.CompletionRate = completionRate(category.CompletedKeyResults, category.KeyResults)
+	}
+	dashboard.Roadmaps.CompletionRate = completionRate(dashboard.Roadmaps.CompletedItems, dashboard.Roadmaps.Items)
+	dashboard.Trails.CompletionRate = completionRate(dashboard.Trails.CompletedActivities, dashboard.Trails.Activities)
+
+	s.cached = dashboard
+	return dashboard, nil
+}
+
+// TTL é o tempo de reaproveitamento do dashboard calculado
+func (s *DashboardService) TTL() time.Duration {
+	return s.ttl
+}
+
+// completionRate retorna a fração concluída (0 a 1) com duas casas decimais; 0 quando não há itens
+func completionRate(completed, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(completed)/float64(total)*100) / 100
+}
@@ -0,0 +1,330 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+	"github.com/conquista-ai/conquista-ai/internal/logging"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+)
+
+// Colunas da planilha de importação de OKRs
+const (
+	importColObjective      = "objective"
+	importColCategory       = "category"
+	importColCompletionDate = "completion_date"
+	importColKeyResult      = "key_result"
+	importColExpectedDate   = "expected_completion_date"
+	importColDifficulty     = "difficulty"
+)
+
+// okrImportHeaders associa os nomes de coluna aceitos (sem diferenciar maiúsculas) às
+// colunas da importação. Os nomes em português são os da exportação em planilha;
+// colunas desconhecidas são ignoradas
+var okrImportHeaders = map[string]string{
+	"objective":                importColObjective,
+	"objetivo":                 importColObjective,
+	"category":                 importColCategory,
+	"categoria":                importColCategory,
+	"completion_date":          importColCompletionDate,
+	"data de conclusão":        importColCompletionDate,
+	"key_result":               importColKeyResult,
+	"key result":               importColKeyResult,
+	"expected_completion_date": importColExpectedDate,
+	"data esperada":            importColExpectedDate,
+	"difficulty":               importColDifficulty,
+	"dificuldade":              importColDifficulty,
+}
+
+// okrImportGroup reúne as linhas de um mesmo OKR (mesmo objetivo na mesma categoria)
+type okrImportGroup struct {
+	okr            models.OKRImport
+	rows           []int
+	failed         bool
+	completionDate string
+	completionRow  int
+}
+
+// ImportOKRsCSV importa OKRs e Key Results de uma planilha CSV (separada por vírgula ou
+// ponto e vírgula, com ou sem BOM). Cada linha traz um objetivo e, opcionalmente, um
+// Key Result; linhas com o mesmo objetivo e categoria formam um único OKR. Todas as
+// linhas são validadas e os erros voltam no resultado; os OKRs sem erros são gravados
+// em uma única transação
+func (s *OKRService) ImportOKRsCSV(ctx context.Context, r io.Reader, opts models.OKRImportOptions) (*models.OKRImportResult, error) {
+	categories, err := s.categoryRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar categorias: %w", err)
+	}
+	categoryIDs := make(map[string]int64, len(categories))
+	for _, c := range categories {
+		categoryIDs[strings.ToLower(c.Name)] = c.ID
+	}
+
+	// Padrão para OKRs sem data de conclusão: mesma duração usada na criação pela API
+	result, okrs, err := parseOKRImport(r, categoryIDs, s.planner.DefaultOKRDeadline())
+	if err != nil {
+		return nil, err
+	}
+	result.DryRun = opts.DryRun
+
+	if len(okrs) > 0 {
+		if err := s.okrRepo.Import(ctx, okrs, opts.DryRun); err != nil {
+			return nil, apperrors.Wrap("erro ao importar OKRs", err)
+		}
+	}
+	for _, okr := range okrs {
+		result.OKRIDs = append(result.OKRIDs, okr.OKR.ID)
+		result.OKRsCreated++
+		result.KeyResultsCreated += len(okr.KeyResults)
+	}
+
+	logging.FromContext(ctx).Info("planilha de OKRs importada",
+		"dry_run", opts.DryRun,
+		"rows", result.Rows,
+		"okrs", result.OKRsCreated,
+		"key_results", result.KeyResultsCreated,
+		"errors", len(result.Errors))
+
+	if opts.GenerateKeyResults && !opts.DryRun {
+		result.Generation = s.generateImportedKeyResults(ctx, okrs)
+	}
+	return result, nil
+}
+
+// parseOKRImport lê e valida as linhas da planilha, agrupando-as em OKRs. categoryIDs
+// associa o nome da categoria, em minúsculas, ao seu ID; defaultDeadline é a data de
+// conclusão dos OKRs sem uma. Retorna o resultado com as contagens de linhas e os
+// erros, e os OKRs sem erros a gravar
+func parseOKRImport(r io.Reader, categoryIDs map[string]int64, defaultDeadline time.Time) (*models.OKRImportResult, []models.OKRImport, error) {
+	reader, err := newImportCSVReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, apperrors.Validation("planilha vazia")
+	}
+	if err != nil {
+		return nil, nil, apperrors.Validation("CSV inválido: " + err.Error())
+	}
+	columns, err := okrImportColumns(header)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := &models.OKRImportResult{
+		OKRIDs: make([]int64, 0),
+		Errors: make([]models.OKRImportRowError, 0),
+	}
+	groups := make(map[string]*okrImportGroup)
+	var order []*okrImportGroup
+	rowFailed := make(map[int]bool)
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, apperrors.Validation("CSV inválido: " + err.Error())
+		}
+		line, _ := reader.FieldPos(0)
+
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		if slices.IndexFunc(record, func(v string) bool { return strings.TrimSpace(v) != "" }) < 0 {
+			continue
+		}
+		result.Rows++
+
+		var rowErrors []models.OKRImportRowError
+		fail := func(field, format string, args ...any) {
+			rowErrors = append(rowErrors, models.OKRImportRowError{Row: line, Field: field, Message: fmt.Sprintf(format, args...)})
+		}
+
+		objective := field(importColObjective)
+		if objective == "" {
+			fail(importColObjective, "objetivo é obrigatório")
+		}
+		categoryName := field(importColCategory)
+		categoryID, ok := categoryIDs[strings.ToLower(categoryName)]
+		if categoryName == "" {
+			fail(importColCategory, "categoria é obrigatória")
+		} else if !ok {
+			fail(importColCategory, "categoria desconhecida: %s", categoryName)
+		}
+
+		completionDate := field(importColCompletionDate)
+		if completionDate != "" {
+			if _, err := time.Parse("2006-01-02", completionDate); err != nil {
+				fail(importColCompletionDate, "data de conclusão inválida: %s. Use YYYY-MM-DD", completionDate)
+				completionDate = ""
+			}
+		}
+
+		kr := models.KeyResult{Title: field(importColKeyResult)}
+		if raw := field(importColExpectedDate); raw != "" {
+			if date, err := time.Parse("2006-01-02", raw); err != nil {
+				fail(importColExpectedDate, "data esperada inválida: %s. Use YYYY-MM-DD", raw)
+			} else {
+				kr.ExpectedCompletionDate = &date
+			}
+			if kr.Title == "" {
+				fail(importColExpectedDate, "data esperada informada sem Key Result")
+			}
+		}
+		if raw := field(importColDifficulty); raw != "" {
+			difficulty, err := strconv.Atoi(raw)
+			if err != nil || difficulty < models.MinKeyResultDifficulty || difficulty > models.MaxKeyResultDifficulty {
+				fail(importColDifficulty, "dificuldade inválida: %s. Use um valor de %d a %d", raw, models.MinKeyResultDifficulty, models.MaxKeyResultDifficulty)
+			}
+			kr.Difficulty = difficulty
+			if kr.Title == "" {
+				fail(importColDifficulty, "dificuldade informada sem Key Result")
+			}
+		}
+
+		key := strings.ToLower(objective) + "\x00" + strings.ToLower(categoryName)
+		group, ok := groups[key]
+		if !ok {
+			group = &okrImportGroup{okr: models.OKRImport{OKR: models.OKR{
+				Objective:  objective,
+				CategoryID: categoryID,
+				Status:     models.OKRStatusActive,
+			}}}
+			groups[key] = group
+			order = append(order, group)
+		}
+		group.rows = append(group.rows, line)
+
+		if completionDate != "" {
+			if group.completionDate == "" {
+				group.completionDate, group.completionRow = completionDate, line
+			} else if group.completionDate != completionDate {
+				fail(importColCompletionDate, "data de conclusão diverge da linha %d (%s)", group.completionRow, group.completionDate)
+			}
+		}
+
+		if len(rowErrors) > 0 {
+			group.failed = true
+			rowFailed[line] = true
+			result.Errors = append(result.Errors, rowErrors...)
+			continue
+		}
+		if kr.Title != "" {
+			group.okr.KeyResults = append(group.okr.KeyResults, kr)
+		}
+	}
+
+	var okrs []models.OKRImport
+	for _, group := range order {
+		if group.failed {
+			for _, line := range group.rows {
+				if !rowFailed[line] {
+					result.Errors = append(result.Errors, models.OKRImportRowError{
+						Row:     line,
+						Message: "linha não importada: há erros em outras linhas do mesmo OKR",
+					})
+				}
+			}
+			continue
+		}
+
+		date := defaultDeadline
+		if group.completionDate != "" {
+			date, _ = time.Parse("2006-01-02", group.completionDate)
+		}
+		group.okr.OKR.CompletionDate = &date
+		okrs = append(okrs, group.okr)
+		result.ImportedRows += len(group.rows)
+	}
+	slices.SortStableFunc(result.Errors, func(a, b models.OKRImportRowError) int { return a.Row - b.Row })
+
+	return result, okrs, nil
+}
+
+// generateImportedKeyResults gera Key Results para os OKRs importados sem nenhum. Uma
+// falha no Spellbook não desfaz a importação: fica registrada no resultado do OKR
+func (s *OKRService) generateImportedKeyResults(ctx context.Context, okrs []models.OKRImport) []models.OKRImportGeneration {
+	var generation []models.OKRImportGeneration
+	for _, okr := range okrs {
+		if len(okr.KeyResults) > 0 {
+			continue
+		}
+		g := models.OKRImportGeneration{OKRID: okr.OKR.ID}
+		if err := s.generateKeyResults(ctx, okr.OKR.ID, okr.OKR.Objective, okr.OKR.CompletionDate); err != nil {
+			logging.FromContext(ctx).Warn("falha ao gerar Key Results do OKR importado", "okr_id", okr.OKR.ID, "error", err)
+			g.Error = err.Error()
+		} else if keyResults, err := s.keyResultRepo.GetByOKRID(ctx, okr.OKR.ID); err == nil {
+			g.KeyResults = len(keyResults)
+		}
+		generation = append(generation, g)
+	}
+	return generation
+}
+
+// newImportCSVReader descarta o BOM do UTF-8 e detecta o separador (vírgula ou ponto e
+// vírgula) pela linha de cabeçalho
+func newImportCSVReader(r io.Reader) (*csv.Reader, error) {
+	br := bufio.NewReader(r)
+	if bom, err := br.Peek(3); err == nil && bytes.Equal(bom, []byte("\xef\xbb\xbf")) {
+		br.Discard(3)
+	}
+
+	head, err := br.Peek(br.Size())
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, apperrors.Validation("erro ao ler a planilha: " + err.Error())
+	}
+	if i := bytes.IndexByte(head, '\n'); i >= 0 {
+		head = head[:i]
+	}
+
+	reader := csv.NewReader(br)
+	if bytes.Count(head, []byte(";")) > bytes.Count(head, []byte(",")) {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	return reader, nil
+}
+
+// okrImportColumns mapeia as colunas do cabeçalho para suas posições
+func okrImportColumns(header []string) (map[string]int, error) {
+	columns := make(map[string]int)
+	for i, name := range header {
+		column, ok := okrImportHeaders[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			continue
+		}
+		if _, dup := columns[column]; dup {
+			return nil, apperrors.Validation("coluna repetida no cabeçalho: " + name)
+		}
+		columns[column] = i
+	}
+
+	var missing []string
+	for _, required := range []string{importColObjective, importColCategory} {
+		if _, ok := columns[required]; !ok {
+			missing = append(missing, required)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, apperrors.Validation("planilha sem as colunas obrigatórias: " + strings.Join(missing, ", "))
+	}
+	return columns, nil
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+)
+
+func TestParseOKRImport(t *testing.T) {
+	categoryIDs := map[string]int64{"carreira": 1, "saúde": 2}
+	deadline := time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+	date := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+
+	// okrSummary resume um OKR importado para comparação
+	type okrSummary struct {
+		objective  string
+		categoryID int64
+		completion time.Time
+		keyResults []string
+	}
+
+	tests := []struct {
+		name         string
+		csv          string
+		rows         int
+		importedRows int
+		okrs         []okrSummary
+		errors       []models.OKRImportRowError
+	}{
+		{
+			name: "BOM, ponto e vírgula e cabeçalho em português",
+			csv: "\xef\xbb\xbfObjetivo;Categoria;Data de conclusão;Key Result;Data esperada;Dificuldade\r\n" +
+				"Aprender Go;Carreira;2026-12-31;Concluir o Tour of Go;2026-04-01;2\r\n" +
+				"aprender go;carreira;;Publicar um projeto;;4\r\n" +
+				"\r\n" +
+				"Correr 10 km;Saúde;;;;\r\n",
+			rows:         3,
+			importedRows: 3,
+			okrs: []okrSummary{
+				{"Aprender Go", 1, date("2026-12-31"), []string{"Concluir o Tour of Go", "Publicar um projeto"}},
+				{"Correr 10 km", 2, deadline, nil},
+			},
+			errors: []models.OKRImportRowError{},
+		},
+		{
+			name: "categoria desconhecida",
+			csv: "objective,category,key_result\n" +
+				"Investir melhor,Finanças,Montar reserva\n" +
+				"Aprender Go,Carreira,Concluir o Tour of Go\n",
+			rows:         2,
+			importedRows: 1,
+			okrs: []okrSummary{
+				{"Aprender Go", 1, deadline, []string{"Concluir o Tour of Go"}},
+			},
+			errors: []models.OKRImportRowError{
+				{Row: 2, Field: "category", Message: "categoria desconhecida: Finanças"},
+			},
+		},
+		{
+			name: "datas inválidas",
+			csv: "objective,category,completion_date,key_result,expected_completion_date\n" +
+				"Aprender Go,Carreira,31/12/2026,Concluir o Tour of Go,\n" +
+				"Correr 10 km,Saúde,,Correr 5 km,2026-13-01\n",
+			rows: 2,
+			errors: []models.OKRImportRowError{
+				{Row: 2, Field: "completion_date", Message: "data de conclusão inválida: 31/12/2026. Use YYYY-MM-DD"},
+				{Row: 3, Field: "expected_completion_date", Message: "data esperada inválida: 2026-13-01. Use YYYY-MM-DD"},
+			},
+		},
+		{
+			name: "datas de conclusão divergentes no mesmo OKR",
+			csv: "objective,category,completion_date,key_result\n" +
+				"Aprender Go,Carreira,2026-12-31,Concluir o Tour of Go\n" +
+				"Aprender Go,Carreira,2026-12-31,Ler Effective Go\n" +
+				"Aprender Go,Carreira,2026-11-30,Publicar um projeto\n",
+			rows: 3,
+			errors: []models.OKRImportRowError{
+				{Row: 2, Message: "linha não importada: há erros em outras linhas do mesmo OKR"},
+				{Row: 3, Message: "linha não importada: há erros em outras linhas do mesmo OKR"},
+				{Row: 4, Field: "completion_date", Message: "data de conclusão diverge da linha 2 (2026-12-31)"},
+			},
+		},
+		{
+			name: "erro em uma linha rejeita as demais linhas do mesmo OKR",
+			csv: "objective,category,key_result,difficulty\n" +
+				"Aprender Go,Carreira,Concluir o Tour of Go,2\n" +
+				"Correr 10 km,Saúde,Correr 5 km,3\n" +
+				"Aprender Go,Carreira,Publicar um projeto,9\n" +
+				"Aprender Go,Carreira,,\n",
+			rows:         4,
+			importedRows: 1,
+			okrs: []okrSummary{
+				{"Correr 10 km", 2, deadline, []string{"Correr 5 km"}},
+			},
+			errors: []models.OKRImportRowError{
+				{Row: 2, Message: "linha não importada: há erros em outras linhas do mesmo OKR"},
+				{Row: 4, Field: "difficulty", Message: "dificuldade inválida: 9. Use um valor de 1 a 5"},
+				{Row: 5, Message: "linha não importada: há erros em outras linhas do mesmo OKR"},
+			},
+		},
+		{
+			name: "campos obrigatórios e dados de Key Result sem título",
+			csv: "objective,category,key_result,expected_completion_date,difficulty\n" +
+				",Carreira,,,\n" +
+				"Aprender Go,,,2026-04-01,3\n",
+			rows: 2,
+			errors: []models.OKRImportRowError{
+				{Row: 2, Field: "objective", Message: "objetivo é obrigatório"},
+				{Row: 3, Field: "category", Message: "categoria é obrigatória"},
+				{Row: 3, Field: "expected_completion_date", Message: "data esperada informada sem Key Result"},
+				{Row: 3, Field: "difficulty", Message: "dificuldade informada sem Key Result"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, okrs, err := parseOKRImport(strings.NewReader(tt.csv), categoryIDs, deadline)
+			if err != nil {
+				t.Fatalf("parseOKRImport: %v", err)
+			}
+
+			if result.Rows != tt.rows || result.ImportedRows != tt.importedRows {
+				t.Errorf("rows = %d, imported_rows = %d, want %d e %d", result.Rows, result.ImportedRows, tt.rows, tt.importedRows)
+			}
+			if tt.errors == nil {
+				tt.errors = []models.OKRImportRowError{}
+			}
+			if !reflect.DeepEqual(result.Errors, tt.errors) {
+				t.Errorf("errors =\n%+v\nwant\n%+v", result.Errors, tt.errors)
+			}
+
+			got := make([]okrSummary, 0, len(okrs))
+			for _, okr := range okrs {
+				if okr.OKR.Status != models.OKRStatusActive {
+					t.Errorf("status de %q = %q, want %q", okr.OKR.Objective, okr.OKR.Status, models.OKRStatusActive)
+				}
+				summary := okrSummary{
+					objective:  okr.OKR.Objective,
+					categoryID: okr.OKR.CategoryID,
+					completion: *okr.OKR.CompletionDate,
+				}
+				for _, kr := range okr.KeyResults {
+					summary.keyResults = append(summary.keyResults, kr.Title)
+				}
+				got = append(got, summary)
+			}
+			if tt.okrs == nil {
+				tt.okrs = []okrSummary{}
+			}
+			if !reflect.DeepEqual(got, tt.okrs) {
+				t.Errorf("okrs =\n%+v\nwant\n%+v", got, tt.okrs)
+			}
+		})
+	}
+}
+
+func TestParseOKRImportKeyResultFields(t *testing.T) {
+	csv := "objective;category;key_result;expected_completion_date;difficulty\n" +
+		"Aprender Go;Carreira;Concluir o Tour of Go;2026-04-01;2\n" +
+		"Aprender Go;Carreira;Ler Effective Go;;\n"
+	_, okrs, err := parseOKRImport(strings.NewReader(csv), map[string]int64{"carreira": 1}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(okrs) != 1 || len(okrs[0].KeyResults) != 2 {
+		t.Fatalf("okrs = %+v, want 1 OKR com 2 Key Results", okrs)
+	}
+
+	first, second := okrs[0].KeyResults[0], okrs[0].KeyResults[1]
+	if first.ExpectedCompletionDate == nil || first.ExpectedCompletionDate.Format("2006-01-02") != "2026-04-01" || first.Difficulty != 2 {
+		t.Errorf("primeiro Key Result = %+v", first)
+	}
+	if second.ExpectedCompletionDate != nil || second.Difficulty != 0 {
+		t.Errorf("segundo Key Result = %+v", second)
+	}
+}
+
+func TestParseOKRImportInvalidSheet(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+	}{
+		{"planilha vazia", ""},
+		{"só o BOM", "\xef\xbb\xbf"},
+		{"sem coluna obrigatória", "objective,key_result\nAprender Go,Concluir o Tour of Go\n"},
+		{"coluna repetida", "objective,objetivo,category\nA,B,Carreira\n"},
+		{"aspas não fechadas", "objective,category\n\"Aprender Go,Carreira\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := parseOKRImport(strings.NewReader(tt.csv), map[string]int64{"carreira": 1}, time.Now())
+			if !errors.Is(err, apperrors.ErrValidation) {
+				t.Errorf("err = %v, want erro de validação", err)
+			}
+		})
+	}
+}
@@ -1,10 +1,13 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"time"
 
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
 	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/conquista-ai/conquista-ai/internal/planning"
 	"github.com/conquista-ai/conquista-ai/internal/repositories"
 	"github.com/conquista-ai/conquista-ai/internal/services/spellbook"
 )
@@ -14,6 +17,8 @@ type OKRService struct {
 	keyResultRepo   *repositories.KeyResultRepository
 	categoryRepo    *repositories.CategoryRepository
 	spellbookClient *spellbook.Client
+	planner         *planning.Planner
+	riskService     *RiskService
 }
 
 func NewOKRService(
@@ -21,55 +26,63 @@ func NewOKRService(
 	keyResultRepo *repositories.KeyResultRepository,
 	categoryRepo *repositories.CategoryRepository,
 	spellbookClient *spellbook.Client,
+	planner *planning.Planner,
+	riskService *RiskService,
 ) *OKRService {
 	return &OKRService{
 		okrRepo:         okrRepo,
 		keyResultRepo:   keyResultRepo,
 		categoryRepo:    categoryRepo,
 		spellbookClient: spellbookClient,
+		planner:         planner,
+		riskService:     riskService,
 	}
 }
 
-func (s *OKRService) CreateOKR(req models.CreateOKRRequest) (*models.OKR, error) {
+func (s *OKRService) CreateOKR(ctx context.Context, req models.CreateOKRRequest) (*models.OKR, error) {
 	// Verificar se categoria existe
-	category, err := s.categoryRepo.GetByID(req.CategoryID)
+	category, err := s.categoryRepo.GetByID(ctx, req.CategoryID)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao buscar categoria: %w", err)
 	}
 	if category == nil {
-		return nil, fmt.Errorf("categoria não encontrada")
+		return nil, apperrors.InvalidField("category_id", "categoria não encontrada")
 	}
 
 	okr := &models.OKR{
 		Objective:  req.Objective,
 		CategoryID: req.CategoryID,
+		Status:     req.Status,
+	}
+	if okr.Status == "" {
+		okr.Status = models.OKRStatusActive
 	}
 
 	// Processar completion_date
 	if req.CompletionDate != nil && *req.CompletionDate != "" {
 		completionDate, err := time.Parse("2006-01-02", *req.CompletionDate)
 		if err != nil {
-			return nil, fmt.Errorf("data de conclusão inválida: %w", err)
+			return nil, apperrors.InvalidField("completion_date", "data de conclusão inválida. Use YYYY-MM-DD")
 		}
 		okr.CompletionDate = &completionDate
 	} else {
-		// Padrão: 3 meses a partir de hoje
-		defaultDate := time.Now().AddDate(0, 3, 0)
+		// Padrão: duração configurada (3 meses) a partir de hoje
+		defaultDate := s.planner.DefaultOKRDeadline()
 		okr.CompletionDate = &defaultDate
 	}
 
-	if err := s.okrRepo.Create(okr); err != nil {
+	if err := s.okrRepo.Create(ctx, okr); err != nil {
 		return nil, fmt.Errorf("erro ao criar OKR: %w", err)
 	}
 
 	return okr, nil
 }
 
-func (s *OKRService) generateKeyResults(okrID int64, objective string, completionDate *time.Time) error {
+func (s *OKRService) generateKeyResults(ctx context.Context, okrID int64, objective string, completionDate *time.Time) error {
 	// Usar o endpoint /key-results do Spellbook para gerar Key Results
-	keyResultsResp, err := s.spellbookClient.GenerateKeyResults(objective, 5, completionDate)
+	keyResultsResp, err := s.spellbookClient.GenerateKeyResults(ctx, objective, 5, completionDate)
 	if err != nil {
-		return fmt.Errorf("erro ao gerar Key Results: %w", err)
+		return apperrors.Upstream("erro ao gerar Key Results", err)
 	}
 
 	var keyResults []models.KeyResult
@@ -81,42 +94,86 @@ func (s *OKRService) generateKeyResults(okrID int64, objective string, completio
 		})
 	}
 
-	if len(keyResults) > 0 {
-		return s.keyResultRepo.CreateBatch(keyResults)
+	if len(keyResults) == 0 {
+		return nil
+	}
+
+	if err := s.keyResultRepo.CreateBatch(ctx, keyResults); err != nil {
+		return err
+	}
+
+	// Distribuir o prazo do OKR entre os Key Results gerados
+	okr, err := s.okrRepo.GetByID(ctx, okrID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar OKR: %w", err)
+	}
+	if okr != nil && okr.CompletionDate != nil {
+		if _, err := s.schedule(ctx, okr, s.planner, false, true); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func (s *OKRService) GetAllOKRs() ([]models.OKR, error) {
-	return s.okrRepo.GetAll()
+func (s *OKRService) GetAllOKRs(ctx context.Context) ([]models.OKR, error) {
+	return s.okrRepo.GetAll(ctx)
 }
 
-func (s *OKRService) GetOKRByID(id int64) (*models.OKR, error) {
-	return s.okrRepo.GetByID(id)
+// GetOKRByID retorna o OKR com a avaliação de risco
+func (s *OKRService) GetOKRByID(ctx context.Context, id int64) (*models.OKR, error) {
+	okr, err := s.okrRepo.GetByID(ctx, id)
+	if err != nil || okr == nil {
+		return okr, err
+	}
+
+	okrs := []models.OKR{*okr}
+	if err := s.riskService.Annotate(ctx, okrs); err != nil {
+		return nil, err
+	}
+	return &okrs[0], nil
 }
 
-func (s *OKRService) GetOKRsByCategory(categoryID int64) ([]models.OKR, error) {
-	return s.okrRepo.GetByCategoryID(categoryID)
+// ListOKRs retorna a página de OKRs com a avaliação de risco de cada um
+func (s *OKRService) ListOKRs(ctx context.Context, filter models.OKRFilter) (*models.Page[models.OKR], error) {
+	page, err := s.okrRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.riskService.Annotate(ctx, page.Items); err != nil {
+		return nil, err
+	}
+	return page, nil
 }
 
-func (s *OKRService) UpdateOKR(id int64, req models.UpdateOKRRequest) (*models.OKR, error) {
-	okr, err := s.okrRepo.GetByID(id)
+func (s *OKRService) UpdateOKR(ctx context.Context, id int64, req models.UpdateOKRRequest) (*models.OKR, error) {
+	okr, err := s.okrRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao buscar OKR: %w", err)
 	}
 	if okr == nil {
-		return nil, fmt.Errorf("OKR não encontrado")
+		return nil, apperrors.NotFound("OKR não encontrado")
+	}
+
+	category, err := s.categoryRepo.GetByID(ctx, req.CategoryID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar categoria: %w", err)
+	}
+	if category == nil {
+		return nil, apperrors.InvalidField("category_id", "categoria não encontrada")
 	}
 
 	okr.Objective = req.Objective
 	okr.CategoryID = req.CategoryID
+	okr.Category = category
+
+	previousCompletionDate := okr.CompletionDate
 
 	// Processar completion_date
 	if req.CompletionDate != nil && *req.CompletionDate != "" {
 		completionDate, err := time.Parse("2006-01-02", *req.CompletionDate)
 		if err != nil {
-			return nil, fmt.Errorf("data de conclusão inválida: %w", err)
+			return nil, apperrors.InvalidField("completion_date", "data de conclusão inválida. Use YYYY-MM-DD")
 		}
 		okr.CompletionDate = &completionDate
 	} else if req.CompletionDate != nil && *req.CompletionDate == "" {
@@ -124,25 +181,145 @@ func (s *OKRService) UpdateOKR(id int64, req models.UpdateOKRRequest) (*models.O
 		okr.CompletionDate = nil
 	}
 
-	if err := s.okrRepo.Update(okr); err != nil {
+	if err := s.okrRepo.Update(ctx, okr); err != nil {
 		return nil, fmt.Errorf("erro ao atualizar OKR: %w", err)
 	}
 
+	// Mudança no prazo do OKR redistribui as datas calculadas dos Key Results
+	if okr.CompletionDate != nil && !sameDay(previousCompletionDate, okr.CompletionDate) {
+		if _, err := s.schedule(ctx, okr, s.planner, false, true); err != nil {
+			return nil, err
+		}
+	}
+
 	return okr, nil
 }
 
-func (s *OKRService) DeleteOKR(id int64) error {
-	return s.okrRepo.Delete(id)
+func (s *OKRService) DeleteOKR(ctx context.Context, id int64) error {
+	return s.okrRepo.Delete(ctx, id)
 }
 
-func (s *OKRService) GenerateKeyResults(okrID int64) error {
-	okr, err := s.okrRepo.GetByID(okrID)
+// ArchiveOKR arquiva o OKR, tirando-o (e a seus Key Results e roadmaps) das listagens padrão
+func (s *OKRService) ArchiveOKR(ctx context.Context, id int64) (*models.OKR, error) {
+	if err := s.okrRepo.SetArchived(ctx, id, true); err != nil {
+		return nil, apperrors.Wrap("erro ao arquivar OKR", err)
+	}
+	return s.GetOKRByID(ctx, id)
+}
+
+// UnarchiveOKR devolve o OKR arquivado às listagens
+func (s *OKRService) UnarchiveOKR(ctx context.Context, id int64) (*models.OKR, error) {
+	if err := s.okrRepo.SetArchived(ctx, id, false); err != nil {
+		return nil, apperrors.Wrap("erro ao desarquivar OKR", err)
+	}
+	return s.GetOKRByID(ctx, id)
+}
+
+func (s *OKRService) GenerateKeyResults(ctx context.Context, okrID int64) error {
+	okr, err := s.okrRepo.GetByID(ctx, okrID)
 	if err != nil {
 		return fmt.Errorf("erro ao buscar OKR: %w", err)
 	}
 	if okr == nil {
-		return fmt.Errorf("OKR não encontrado")
+		return apperrors.NotFound("OKR não encontrado")
+	}
+	if err := requireOKRStatus(okr, "gerar Key Results", keyResultGenerationStatuses); err != nil {
+		return err
+	}
+
+	return s.generateKeyResults(ctx, okrID, okr.Objective, okr.CompletionDate)
+}
+
+// ScheduleKeyResults calcula as datas esperadas dos Key Results pendentes do OKR,
+// distribuindo o prazo restante segundo a estratégia de planejamento. Por padrão
+// apenas as datas calculadas (ou ausentes) são consideradas e nada é gravado
+// até que req.Apply seja verdadeiro
+func (s *OKRService) ScheduleKeyResults(ctx context.Context, okrID int64, req models.ScheduleRequest) (*models.ScheduleResult, error) {
+	okr, err := s.okrRepo.GetByID(ctx, okrID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar OKR: %w", err)
+	}
+	if okr == nil {
+		return nil, apperrors.NotFound("OKR não encontrado")
+	}
+	if okr.CompletionDate == nil {
+		return nil, apperrors.Validation("OKR não possui data de conclusão para distribuir entre os Key Results")
+	}
+
+	planner := s.planner
+	if req.Strategy != "" {
+		scheduler, err := planning.NewScheduler(req.Strategy)
+		if err != nil {
+			return nil, apperrors.InvalidField("strategy", err.Error())
+		}
+		planner = planner.WithScheduler(scheduler)
+	}
+
+	return s.schedule(ctx, okr, planner, req.IncludeManual, req.Apply)
+}
+
+// schedule calcula (e opcionalmente grava) as datas dos Key Results não concluídos do OKR
+func (s *OKRService) schedule(ctx context.Context, okr *models.OKR, planner *planning.Planner, includeManual, apply bool) (*models.ScheduleResult, error) {
+	keyResults, err := s.keyResultRepo.GetByOKRID(ctx, okr.ID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar Key Results: %w", err)
+	}
+
+	schedulable := make([]models.KeyResult, 0, len(keyResults))
+	for _, kr := range keyResults {
+		if kr.Completed {
+			continue
+		}
+		if !includeManual && kr.ExpectedCompletionDate != nil && !kr.DueDateComputed {
+			continue
+		}
+		schedulable = append(schedulable, kr)
+	}
+
+	result := &models.ScheduleResult{
+		OKRID:          okr.ID,
+		Strategy:       planner.Scheduler().Name(),
+		CompletionDate: okr.CompletionDate.Format("2006-01-02"),
+		Changes:        make([]models.ScheduleChange, 0, len(schedulable)),
 	}
 
-	return s.generateKeyResults(okrID, okr.Objective, okr.CompletionDate)
+	dueDates := planner.DueDates(*okr.CompletionDate, planning.KeyResultWeights(schedulable))
+	changed := make([]models.KeyResult, 0, len(schedulable))
+	for i := range schedulable {
+		kr := schedulable[i]
+		change := models.ScheduleChange{
+			KeyResultID:  kr.ID,
+			Title:        kr.Title,
+			Difficulty:   kr.EffectiveDifficulty(),
+			ProposedDate: dueDates[i].Format("2006-01-02"),
+			Changed:      !sameDay(kr.ExpectedCompletionDate, &dueDates[i]) || !kr.DueDateComputed,
+		}
+		if kr.ExpectedCompletionDate != nil {
+			current := kr.ExpectedCompletionDate.Format("2006-01-02")
+			change.CurrentDate = &current
+		}
+		result.Changes = append(result.Changes, change)
+
+		if change.Changed {
+			kr.ExpectedCompletionDate = &dueDates[i]
+			changed = append(changed, kr)
+		}
+	}
+
+	if apply && len(changed) > 0 {
+		if err := s.keyResultRepo.UpdateDueDates(ctx, changed); err != nil {
+			return nil, fmt.Errorf("erro ao gravar datas dos Key Results: %w", err)
+		}
+	}
+	result.Applied = apply
+
+	return result, nil
+}
+
+// sameDay compara duas datas opcionais considerando apenas o dia
+func sameDay(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Format("2006-01-02") == b.Format("2006-01-02")
 }
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+)
+
+// okrStatusTransitions define as transições permitidas do ciclo de vida de um OKR.
+// completed e abandoned são estados finais
+var okrStatusTransitions = map[string][]string{
+	models.OKRStatusDraft:  {models.OKRStatusActive, models.OKRStatusAbandoned},
+	models.OKRStatusActive: {models.OKRStatusOnHold, models.OKRStatusCompleted, models.OKRStatusAbandoned},
+	models.OKRStatusOnHold: {models.OKRStatusActive, models.OKRStatusAbandoned},
+}
+
+// Status em que cada tipo de geração é permitido
+var (
+	keyResultGenerationStatuses = []string{models.OKRStatusDraft, models.OKRStatusActive}
+	roadmapGenerationStatuses   = []string{models.OKRStatusActive}
+)
+
+// requireOKRStatus recusa a ação quando o OKR não está em um dos status permitidos
+func requireOKRStatus(okr *models.OKR, action string, allowed []string) error {
+	if slices.Contains(allowed, okr.Status) {
+		return nil
+	}
+	return apperrors.Conflict("não é possível %s: o OKR está com status %s (permitido: %s)",
+		action, okr.Status, strings.Join(allowed, ", "))
+}
+
+// ChangeOKRStatus aplica uma transição de status ao OKR. Abandonar exige um motivo
+func (s *OKRService) ChangeOKRStatus(ctx context.Context, id int64, req models.ChangeOKRStatusRequest) (*models.OKR, error) {
+	okr, err := s.okrRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar OKR: %w", err)
+	}
+	if okr == nil {
+		return nil, apperrors.NotFound("OKR não encontrado")
+	}
+
+	reason, err := validateOKRStatusChange(okr.Status, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.okrRepo.ChangeStatus(ctx, id, okr.Status, req.Status, reason); err != nil {
+		return nil, apperrors.Wrap("erro ao alterar status do OKR", err)
+	}
+
+	return s.GetOKRByID(ctx, id)
+}
+
+// validateOKRStatusChange verifica se a transição de from para req.Status é permitida
+// e retorna o motivo informado, sem espaços nas pontas (nil quando vazio)
+func validateOKRStatusChange(from string, req models.ChangeOKRStatusRequest) (*string, error) {
+	if req.Status == from {
+		return nil, apperrors.Conflict("o OKR já está com status %s", from)
+	}
+	if !slices.Contains(okrStatusTransitions[from], req.Status) {
+		return nil, apperrors.Conflict("transição de status inválida: %s → %s", from, req.Status)
+	}
+
+	var reason *string
+	if trimmed := strings.TrimSpace(req.Reason); trimmed != "" {
+		reason = &trimmed
+	}
+	if req.Status == models.OKRStatusAbandoned && reason == nil {
+		return nil, apperrors.InvalidField("reason", "informe o motivo do abandono")
+	}
+	return reason, nil
+}
+
+// OKRStatusHistory retorna as mudanças de status do OKR
+func (s *OKRService) OKRStatusHistory(ctx context.Context, id int64) ([]models.OKRStatusChange, error) {
+	okr, err := s.okrRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar OKR: %w", err)
+	}
+	if okr == nil {
+		return nil, apperrors.NotFound("OKR não encontrado")
+	}
+
+	return s.okrRepo.StatusHistory(ctx, id)
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+)
+
+func TestValidateOKRStatusChange(t *testing.T) {
+	// allowed lista as transições permitidas; as demais devem ser recusadas
+	allowed := map[[2]string]bool{
+		{models.OKRStatusDraft, models.OKRStatusActive}:     true,
+		{models.OKRStatusDraft, models.OKRStatusAbandoned}:  true,
+		{models.OKRStatusActive, models.OKRStatusOnHold}:    true,
+		{models.OKRStatusActive, models.OKRStatusCompleted}: true,
+		{models.OKRStatusActive, models.OKRStatusAbandoned}: true,
+		{models.OKRStatusOnHold, models.OKRStatusActive}:    true,
+		{models.OKRStatusOnHold, models.OKRStatusAbandoned}: true,
+	}
+
+	for _, from := range models.OKRStatuses {
+		for _, to := range models.OKRStatuses {
+			t.Run(from+"→"+to, func(t *testing.T) {
+				_, err := validateOKRStatusChange(from, models.ChangeOKRStatusRequest{Status: to, Reason: "sem tempo"})
+				if allowed[[2]string{from, to}] {
+					if err != nil {
+						t.Errorf("err = %v, want transição permitida", err)
+					}
+				} else if !errors.Is(err, apperrors.ErrConflict) {
+					t.Errorf("err = %v, want conflito", err)
+				}
+			})
+		}
+	}
+}
+
+func TestValidateOKRStatusChangeReason(t *testing.T) {
+	tests := []struct {
+		name   string
+		to     string
+		reason string
+		want   string
+		err    error
+	}{
+		{"abandonar sem motivo", models.OKRStatusAbandoned, "", "", apperrors.ErrValidation},
+		{"abandonar com motivo em branco", models.OKRStatusAbandoned, "   ", "", apperrors.ErrValidation},
+		{"abandonar com motivo", models.OKRStatusAbandoned, "  mudança de prioridade ", "mudança de prioridade", nil},
+		{"pausar sem motivo", models.OKRStatusOnHold, "", "", nil},
+		{"pausar com motivo", models.OKRStatusOnHold, "férias", "férias", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, err := validateOKRStatusChange(models.OKRStatusActive, models.ChangeOKRStatusRequest{Status: tt.to, Reason: tt.reason})
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("err = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			got := ""
+			if reason != nil {
+				got = *reason
+			}
+			if got != tt.want || (tt.want == "") != (reason == nil) {
+				t.Errorf("reason = %v, want %q", reason, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequireOKRStatus(t *testing.T) {
+	for _, status := range models.OKRStatuses {
+		okr := &models.OKR{Status: status}
+
+		err := requireOKRStatus(okr, "gerar Key Results", keyResultGenerationStatuses)
+		if want := status == models.OKRStatusDraft || status == models.OKRStatusActive; (err == nil) != want {
+			t.Errorf("geração de Key Results com status %s: err = %v", status, err)
+		}
+		if err != nil && !errors.Is(err, apperrors.ErrConflict) {
+			t.Errorf("geração de Key Results com status %s: err = %v, want conflito", status, err)
+		}
+
+		err = requireOKRStatus(okr, "gerar o roadmap", roadmapGenerationStatuses)
+		if want := status == models.OKRStatusActive; (err == nil) != want {
+			t.Errorf("geração de roadmap com status %s: err = %v", status, err)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/conquista-ai/conquista-ai/internal/repositories"
+	"github.com/conquista-ai/conquista-ai/internal/risk"
+)
+
+type RiskService struct {
+	riskRepo *repositories.RiskRepository
+	okrRepo  *repositories.OKRRepository
+	engine   *risk.Engine
+}
+
+func NewRiskService(riskRepo *repositories.RiskRepository, okrRepo *repositories.OKRRepository, engine *risk.Engine) *RiskService {
+	return &RiskService{
+		riskRepo: riskRepo,
+		okrRepo:  okrRepo,
+		engine:   engine,
+	}
+}
+
+// Report avalia os OKRs acompanhados, seus Key Results e trilhas educacionais. OKRs
+// pausados ou encerrados ficam fora do relatório
+func (s *RiskService) Report(ctx context.Context) (*models.RiskReport, error) {
+	okrs, err := s.okrRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar OKRs: %w", err)
+	}
+	okrs = slices.DeleteFunc(okrs, func(okr models.OKR) bool { return !trackedStatus(okr) })
+
+	risks, err := s.assess(ctx, okrs)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.RiskReport{
+		GeneratedAt: s.engine.Now(),
+		Summary: models.RiskSummary{
+			OKRs:       emptyRiskCounts(),
+			KeyResults: emptyRiskCounts(),
+			Trails:     emptyRiskCounts(),
+		},
+		OKRs: risks,
+	}
+	for _, okr := range risks {
+		report.Summary.OKRs[okr.Level]++
+		for _, kr := range okr.KeyResults {
+			report.Summary.KeyResults[kr.Level]++
+			for _, trail := range kr.Trails {
+				report.Summary.Trails[trail.Level]++
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// Annotate preenche o campo Risk dos OKRs informados. OKRs pausados ou encerrados não
+// têm prazo acompanhado e ficam sem avaliação
+func (s *RiskService) Annotate(ctx context.Context, okrs []models.OKR) error {
+	indexes := make([]int, 0, len(okrs))
+	trackedOKRs := make([]models.OKR, 0, len(okrs))
+	for i, okr := range okrs {
+		if trackedStatus(okr) {
+			indexes = append(indexes, i)
+			trackedOKRs = append(trackedOKRs, okr)
+		}
+	}
+
+	risks, err := s.assess(ctx, trackedOKRs)
+	if err != nil {
+		return err
+	}
+	for i, index := range indexes {
+		okrs[index].Risk = &risks[i].RiskAssessment
+	}
+	return nil
+}
+
+// trackedStatus indica se os prazos do OKR são acompanhados, segundo o status
+func trackedStatus(okr models.OKR) bool {
+	return slices.Contains(models.OKRTrackedStatuses, okr.Status)
+}
+
+// assess avalia os OKRs na ordem recebida, buscando os dados de todos em duas consultas
+func (s *RiskService) assess(ctx context.Context, okrs []models.OKR) ([]models.OKRRisk, error) {
+	risks := make([]models.OKRRisk, 0, len(okrs))
+	if len(okrs) == 0 {
+		return risks, nil
+	}
+
+	okrIDs := make([]int64, len(okrs))
+	for i, okr := range okrs {
+		okrIDs[i] = okr.ID
+	}
+
+	keyResults, err := s.riskRepo.KeyResults(ctx, okrIDs)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar progresso dos Key Results: %w", err)
+	}
+	trails, err := s.riskRepo.Trails(ctx, okrIDs)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar progresso das trilhas: %w", err)
+	}
+
+	trailsByKeyResult := make(map[int64][]models.TrailRisk)
+	for _, trail := range trails {
+		progress := 0.0
+		switch {
+		case trail.ItemCompleted:
+			progress = 1
+		case trail.TotalActivities > 0:
+			progress = float64(trail.CompletedActivities) / float64(trail.TotalActivities)
+		}
+		due := trail.DueDate
+		trailsByKeyResult[trail.KeyResultID] = append(trailsByKeyResult[trail.KeyResultID], models.TrailRisk{
+			TrailID:        trail.ID,
+			RoadmapItemID:  trail.RoadmapItemID,
+			Topic:          trail.Topic,
+			RiskAssessment: s.engine.Assess(trail.StartDate, &due, progress),
+		})
+	}
+
+	keyResultsByOKR := make(map[int64][]repositories.RiskKeyResult)
+	for _, kr := range keyResults {
+		keyResultsByOKR[kr.OKRID] = append(keyResultsByOKR[kr.OKRID], kr)
+	}
+
+	for _, okr := range okrs {
+		okrRisk := models.OKRRisk{
+			OKRID:      okr.ID,
+			Objective:  okr.Objective,
+			KeyResults: make([]models.KeyResultRisk, 0),
+		}
+
+		// O progresso do OKR é a média do progresso dos seus Key Results
+		totalProgress := 0.0
+		offTrack := 0
+		for _, kr := range keyResultsByOKR[okr.ID] {
+			progress := kr.RoadmapProgress
+			if kr.Completed {
+				progress = 1
+			}
+			totalProgress += progress
+
+			// Sem data própria, o Key Result herda o prazo do OKR
+			due := kr.ExpectedCompletionDate
+			if due == nil {
+				due = okr.CompletionDate
+			}
+
+			krRisk := models.KeyResultRisk{
+				KeyResultID:    kr.ID,
+				Title:          kr.Title,
+				Trails:         trailsByKeyResult[kr.ID],
+				RiskAssessment: s.engine.Assess(kr.CreatedAt, due, progress),
+			}
+			if krRisk.Trails == nil {
+				krRisk.Trails = make([]models.TrailRisk, 0)
+			}
+			if krRisk.Level == models.RiskOffTrack {
+				offTrack++
+			}
+			okrRisk.KeyResults = append(okrRisk.KeyResults, krRisk)
+		}
+
+		progress := 0.0
+		if len(okrRisk.KeyResults) > 0 {
+			progress = totalProgress / float64(len(okrRisk.KeyResults))
+		}
+		okrRisk.RiskAssessment = s.engine.Assess(okr.CreatedAt, okr.CompletionDate, progress)
+
+		// Key Results atrasados colocam o OKR ao menos em risco
+		if offTrack > 0 {
+			risk.Escalate(&okrRisk.RiskAssessment, models.RiskAtRisk,
+				fmt.Sprintf("%d Key Result(s) atrasado(s)", offTrack))
+		}
+
+		risks = append(risks, okrRisk)
+	}
+
+	return risks, nil
+}
+
+func emptyRiskCounts() map[string]int {
+	counts := make(map[string]int, len(models.RiskLevels))
+	for _, level := range models.RiskLevels {
+		counts[level] = 0
+	}
+	return counts
+}
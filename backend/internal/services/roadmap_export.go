@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+	"github.com/conquista-ai/conquista-ai/internal/document"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+)
+
+// Formato das datas nos documentos exportados
+const documentDateFormat = "02/01/2006"
+
+// Rótulos dos tipos de atividade da trilha, os mesmos exibidos no front-end
+var trailActivityLabels = map[string]string{
+	"read_book":     "Ler Livro",
+	"read_chapters": "Ler Capítulos",
+	"watch_video":   "Assistir Vídeo",
+	"read_article":  "Ler Artigo",
+	"take_course":   "Fazer Curso",
+	"do_project":    "Fazer Projeto",
+}
+
+// RoadmapDocument monta o documento para impressão do roadmap do Key Result, com os
+// itens agrupados por categoria e marcados conforme a conclusão
+func (s *RoadmapService) RoadmapDocument(ctx context.Context, keyResultID int64) (*document.Document, error) {
+	roadmap, err := s.roadmapRepo.GetByKeyResultID(ctx, keyResultID)
+	if err != nil {
+		return nil, apperrors.Wrap("erro ao buscar roadmap", err)
+	}
+	if roadmap == nil {
+		return nil, apperrors.NotFound("roadmap não encontrado")
+	}
+	kr, err := s.keyResultRepo.GetByID(ctx, keyResultID)
+	if err != nil {
+		return nil, apperrors.Wrap("erro ao buscar Key Result", err)
+	}
+
+	doc := &document.Document{Title: "Roadmap: " + roadmap.Topic, Created: s.planner.Now()}
+	doc.Heading(1, doc.Title)
+
+	total, completed := 0, 0
+	for _, category := range roadmap.Categories {
+		for _, item := range category.Items {
+			total++
+			if item.Completed {
+				completed++
+			}
+		}
+	}
+	var summary []string
+	if kr != nil {
+		summary = append(summary, "Key Result: "+kr.Title)
+	}
+	summary = append(summary, fmt.Sprintf("Progresso: %d de %d itens concluídos", completed, total))
+	doc.Paragraph(strings.Join(summary, "\n"))
+
+	for _, category := range roadmap.Categories {
+		doc.Heading(2, category.Category)
+		for _, item := range category.Items {
+			doc.Task(0, item.Completed, "", item.Title)
+		}
+	}
+	return doc, nil
+}
+
+// EducationalTrailDocument monta o documento para impressão da trilha do item do
+// roadmap: os dias com suas atividades (capítulos, duração, progresso e links) marcadas
+// conforme a conclusão, seguidos dos recursos de estudo
+func (s *RoadmapService) EducationalTrailDocument(ctx context.Context, roadmapItemID int64) (*document.Document, error) {
+	trail, err := s.GetEducationalTrailByRoadmapItemID(ctx, roadmapItemID)
+	if err != nil {
+		return nil, apperrors.Wrap("erro ao buscar trilha educacional", err)
+	}
+	if trail == nil {
+		return nil, apperrors.NotFound("trilha educacional não encontrada")
+	}
+
+	doc := &document.Document{Title: "Trilha: " + trail.Topic, Created: s.planner.Now()}
+	doc.Heading(1, doc.Title)
+	doc.Paragraph(trail.Description)
+
+	total, completed := 0, 0
+	for _, step := range trail.Steps {
+		for _, activity := range step.Activities {
+			total++
+			if activity.Completed {
+				completed++
+			}
+		}
+	}
+	var summary []string
+	if trail.StartDate != nil {
+		summary = append(summary, "Início: "+trail.StartDate.Format(documentDateFormat))
+	}
+	summary = append(summary,
+		fmt.Sprintf("Duração: %d dias", trail.TotalDays),
+		fmt.Sprintf("Progresso: %d de %d atividades concluídas", completed, total))
+	doc.Paragraph(strings.Join(summary, "\n"))
+
+	for _, step := range trail.Steps {
+		heading := fmt.Sprintf("Dia %d: %s", step.Day, step.Title)
+		if step.ScheduledDate != nil {
+			heading += " (" + step.ScheduledDate.Format(documentDateFormat) + ")"
+		}
+		doc.Heading(2, heading)
+		doc.Paragraph(step.Description)
+
+		for _, activity := range step.Activities {
+			label, ok := trailActivityLabels[activity.Type]
+			if !ok {
+				label = "Atividade"
+			}
+			title := activity.Title
+			if activity.Duration != "" {
+				title += " (" + activity.Duration + ")"
+			}
+			doc.Task(0, activity.Completed, label+":", title)
+
+			if activity.Description != "" {
+				doc.Item(1, "", activity.Description)
+			}
+			if activity.Progress != "" {
+				doc.Item(1, "Progresso:", activity.Progress)
+			}
+			if len(activity.Chapters) > 0 {
+				doc.Item(1, "Capítulos:", "")
+				for _, chapter := range activity.Chapters {
+					doc.Item(2, "", chapter)
+				}
+			}
+			if activity.URL != "" {
+				doc.Link(1, "Link:", activity.URL, activity.URL)
+			}
+		}
+	}
+
+	if len(trail.Resources) > 0 {
+		doc.Heading(2, "Recursos")
+		resources := make([]models.TrailResource, 0, len(trail.Resources))
+		for _, resource := range trail.Resources {
+			resources = append(resources, resource)
+		}
+		sort.Slice(resources, func(i, j int) bool { return resources[i].Title < resources[j].Title })
+
+		for _, resource := range resources {
+			var details []string
+			if resource.Author != "" {
+				details = append(details, resource.Author)
+			}
+			if resource.Duration != "" {
+				details = append(details, resource.Duration)
+			}
+			text := ""
+			if len(details) > 0 {
+				text = "(" + strings.Join(details, ", ") + ")"
+			}
+			doc.Item(0, resource.Title, text)
+			if resource.URL != "" {
+				doc.Link(1, "Link:", resource.URL, resource.URL)
+			}
+		}
+	}
+	return doc, nil
+}
@@ -1,10 +1,16 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"slices"
 	"time"
 
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+	"github.com/conquista-ai/conquista-ai/internal/logging"
+	"github.com/conquista-ai/conquista-ai/internal/metrics"
 	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/conquista-ai/conquista-ai/internal/planning"
 	"github.com/conquista-ai/conquista-ai/internal/repositories"
 	"github.com/conquista-ai/conquista-ai/internal/services/spellbook"
 	"github.com/conquista-ai/conquista-ai/internal/utils"
@@ -14,41 +20,57 @@ type RoadmapService struct {
 	roadmapRepo              *repositories.RoadmapRepository
 	educationalRoadmapRepo   *repositories.EducationalRoadmapRepository
 	educationalTrailRepo     *repositories.EducationalTrailRepository
+	studySettingsRepo        *repositories.StudySettingsRepository
 	keyResultRepo            *repositories.KeyResultRepository
 	okrRepo                  *repositories.OKRRepository
 	spellbookClient          *spellbook.Client
+	planner                  *planning.Planner
 }
 
 func NewRoadmapService(
 	roadmapRepo *repositories.RoadmapRepository,
 	educationalRoadmapRepo *repositories.EducationalRoadmapRepository,
 	educationalTrailRepo *repositories.EducationalTrailRepository,
+	studySettingsRepo *repositories.StudySettingsRepository,
 	keyResultRepo *repositories.KeyResultRepository,
 	okrRepo *repositories.OKRRepository,
 	spellbookClient *spellbook.Client,
+	planner *planning.Planner,
 ) *RoadmapService {
 	return &RoadmapService{
 		roadmapRepo:            roadmapRepo,
 		educationalRoadmapRepo:  educationalRoadmapRepo,
 		educationalTrailRepo:    educationalTrailRepo,
+		studySettingsRepo:       studySettingsRepo,
 		keyResultRepo:          keyResultRepo,
 		okrRepo:                okrRepo,
 		spellbookClient:         spellbookClient,
+		planner:                planner,
 	}
 }
 
-func (s *RoadmapService) GenerateRoadmap(keyResultID int64) (*models.Roadmap, error) {
+func (s *RoadmapService) GenerateRoadmap(ctx context.Context, keyResultID int64) (*models.Roadmap, error) {
 	// Verificar se Key Result existe
-	kr, err := s.keyResultRepo.GetByID(keyResultID)
+	kr, err := s.keyResultRepo.GetByID(ctx, keyResultID)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao buscar Key Result: %w", err)
 	}
 	if kr == nil {
-		return nil, fmt.Errorf("Key Result não encontrado")
+		return nil, apperrors.NotFound("Key Result não encontrado")
+	}
+
+	okr, err := s.okrRepo.GetByID(ctx, kr.OKRID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar OKR: %w", err)
+	}
+	if okr != nil {
+		if err := requireOKRStatus(okr, "gerar o roadmap", roadmapGenerationStatuses); err != nil {
+			return nil, err
+		}
 	}
 
 	// Verificar se já existe roadmap
-	existing, err := s.roadmapRepo.GetByKeyResultID(keyResultID)
+	existing, err := s.roadmapRepo.GetByKeyResultID(ctx, keyResultID)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao verificar roadmap existente: %w", err)
 	}
@@ -56,108 +78,27 @@ func (s *RoadmapService) GenerateRoadmap(keyResultID int64) (*models.Roadmap, er
 		return existing, nil
 	}
 
-	// Calcular tempo disponível do Key Result
-	var availableDays *int
-	now := time.Now()
-	
-	// Prioridade 1: Usar expected_completion_date do Key Result se disponível
-	if kr.ExpectedCompletionDate != nil {
-		expectedDate := *kr.ExpectedCompletionDate
-		daysRemaining := int(expectedDate.Sub(now).Hours() / 24)
-		
-		if daysRemaining > 0 {
-			// Aplicar limite mínimo de 3 dias para evitar roadmaps muito curtos
-			if daysRemaining < 3 {
-				daysRemaining = 3
-			}
-			availableDays = &daysRemaining
-		}
-	}
-	
-	// Prioridade 2: Se não tiver expected_completion_date, calcular baseado no OKR
-	if availableDays == nil {
-		okr, err := s.okrRepo.GetByID(kr.OKRID)
-		if err != nil {
-			return nil, fmt.Errorf("erro ao buscar OKR: %w", err)
-		}
-		
-		if okr != nil && okr.CompletionDate != nil {
-			// Buscar todos os Key Results do OKR para contar
-			allKeyResults, err := s.keyResultRepo.GetByOKRID(okr.ID)
-			if err != nil {
-				return nil, fmt.Errorf("erro ao buscar Key Results: %w", err)
-			}
-			
-			totalKeyResults := len(allKeyResults)
-			if totalKeyResults > 0 {
-				completionDate := *okr.CompletionDate
-				
-				// Calcular dias restantes
-				daysRemaining := int(completionDate.Sub(now).Hours() / 24)
-				
-				if daysRemaining > 0 {
-					// Dividir o tempo pelo número de Key Results
-					calculatedDays := daysRemaining / totalKeyResults
-					
-					// Aplicar limite mínimo de 3 dias para evitar roadmaps muito curtos
-					if calculatedDays < 3 {
-						calculatedDays = 3
-					}
-					
-					availableDays = &calculatedDays
-				}
-			}
-		}
-	}
-	
-	// Se não calculou tempo ou completion_date é nulo/passado, usar padrão de 30 dias para roadmap
-	if availableDays == nil {
-		defaultDays := 30
-		availableDays = &defaultDays
-	}
-
-	// Calcular número de itens baseado no tempo disponível
-	// Cada item do roadmap se tornará uma trilha educacional com tempo específico
-	// Definir tempo mínimo por trilha baseado no tempo disponível
-	var minDaysPerTrail int
-	if *availableDays < 14 {
-		// Tempo curto: trilhas curtas (3 dias)
-		minDaysPerTrail = 3
-	} else if *availableDays <= 30 {
-		// Tempo médio: trilhas médias (5 dias)
-		minDaysPerTrail = 5
-	} else if *availableDays <= 60 {
-		// Tempo médio-longo: trilhas mais longas (6 dias)
-		minDaysPerTrail = 6
-	} else {
-		// Tempo longo: trilhas extensas (7 dias)
-		minDaysPerTrail = 7
-	}
-
-	// Calcular número de itens: dividir tempo disponível pelo tempo mínimo por trilha
-	exactItemCount := *availableDays / minDaysPerTrail
-
-	// Garantir limites: mínimo de 3 itens, máximo de 20 itens
-	if exactItemCount < 3 {
-		exactItemCount = 3
-	} else if exactItemCount > 20 {
-		exactItemCount = 20
-	}
-
-	// Log para debug
-	estimatedDaysPerTrail := *availableDays / exactItemCount
-	if kr.ExpectedCompletionDate != nil {
-		fmt.Printf("[DEBUG] GenerateRoadmap - KeyResultID: %d, ExpectedCompletionDate: %v, AvailableDays: %d, ExactItemCount: %d, MinDaysPerTrail: %d, EstimatedDaysPerTrail: %d\n", 
-			keyResultID, *kr.ExpectedCompletionDate, *availableDays, exactItemCount, minDaysPerTrail, estimatedDaysPerTrail)
-	} else {
-		fmt.Printf("[DEBUG] GenerateRoadmap - KeyResultID: %d, ExpectedCompletionDate: nil, AvailableDays: %d (calculado do OKR), ExactItemCount: %d, MinDaysPerTrail: %d, EstimatedDaysPerTrail: %d\n", 
-			keyResultID, *availableDays, exactItemCount, minDaysPerTrail, estimatedDaysPerTrail)
+	// Calcular tempo disponível do Key Result e o número de itens do roadmap
+	availableDays, err := s.roadmapDays(ctx, kr)
+	if err != nil {
+		return nil, err
 	}
+	exactItemCount, minDaysPerTrail := s.planner.RoadmapItemCount(availableDays)
+
+	logger := logging.FromContext(ctx)
+	logger.Debug("GenerateRoadmap: parâmetros calculados",
+		"key_result_id", keyResultID,
+		"expected_completion_date", kr.ExpectedCompletionDate,
+		"available_days", availableDays,
+		"exact_item_count", exactItemCount,
+		"min_days_per_trail", minDaysPerTrail,
+		"estimated_days_per_trail", availableDays/exactItemCount,
+	)
 
 	// Gerar roadmap via Spellbook passando o número exato de itens
-	roadmapResp, err := s.spellbookClient.GenerateRoadmap(kr.Title, availableDays, &exactItemCount)
+	roadmapResp, err := s.spellbookClient.GenerateRoadmap(ctx, kr.Title, &availableDays, &exactItemCount)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao gerar roadmap: %w", err)
+		return nil, apperrors.Upstream("erro ao gerar roadmap", err)
 	}
 
 	// Contar itens gerados para debug
@@ -165,8 +106,11 @@ func (s *RoadmapService) GenerateRoadmap(keyResultID int64) (*models.Roadmap, er
 	for _, cat := range roadmapResp.Roadmap {
 		totalItemsGenerated += len(cat.Items)
 	}
-	fmt.Printf("[DEBUG] GenerateRoadmap - KeyResultID: %d, TotalItemsGenerated: %d, ExpectedExactItems: %d\n", 
-		keyResultID, totalItemsGenerated, exactItemCount)
+	logger.Debug("GenerateRoadmap: itens gerados",
+		"key_result_id", keyResultID,
+		"total_items_generated", totalItemsGenerated,
+		"expected_exact_items", exactItemCount,
+	)
 
 	// Converter resposta do Spellbook para modelo interno
 	roadmap := &models.Roadmap{
@@ -194,37 +138,37 @@ func (s *RoadmapService) GenerateRoadmap(keyResultID int64) (*models.Roadmap, er
 	}
 
 	// Salvar no banco
-	if err := s.roadmapRepo.Create(roadmap); err != nil {
+	if err := s.roadmapRepo.Create(ctx, roadmap); err != nil {
 		return nil, fmt.Errorf("erro ao salvar roadmap: %w", err)
 	}
 
 	return roadmap, nil
 }
 
-func (s *RoadmapService) GetRoadmapByKeyResultID(keyResultID int64) (*models.Roadmap, error) {
-	return s.roadmapRepo.GetByKeyResultID(keyResultID)
+func (s *RoadmapService) GetRoadmapByKeyResultID(ctx context.Context, keyResultID int64) (*models.Roadmap, error) {
+	return s.roadmapRepo.GetByKeyResultID(ctx, keyResultID)
 }
 
-func (s *RoadmapService) DeleteRoadmap(keyResultID int64) error {
+func (s *RoadmapService) DeleteRoadmap(ctx context.Context, keyResultID int64) error {
 	// Verificar se roadmap existe antes de deletar
-	existing, err := s.roadmapRepo.GetByKeyResultID(keyResultID)
+	existing, err := s.roadmapRepo.GetByKeyResultID(ctx, keyResultID)
 	if err != nil {
 		return fmt.Errorf("erro ao verificar roadmap existente: %w", err)
 	}
 	if existing == nil {
-		return fmt.Errorf("roadmap não encontrado para key_result_id %d", keyResultID)
+		return apperrors.NotFound("roadmap não encontrado para key_result_id %d", keyResultID)
 	}
 	
-	return s.roadmapRepo.DeleteByKeyResultID(keyResultID)
+	return s.roadmapRepo.DeleteByKeyResultID(ctx, keyResultID)
 }
 
-func (s *RoadmapService) UpdateRoadmapItem(itemID int64, completed bool) error {
-	return s.roadmapRepo.UpdateItem(itemID, completed)
+func (s *RoadmapService) UpdateRoadmapItem(ctx context.Context, itemID int64, completed bool) error {
+	return s.roadmapRepo.UpdateItem(ctx, itemID, completed)
 }
 
-func (s *RoadmapService) GenerateEducationalRoadmap(roadmapItemID int64, itemTitle string) (*models.EducationalRoadmap, error) {
+func (s *RoadmapService) GenerateEducationalRoadmap(ctx context.Context, roadmapItemID int64, itemTitle string) (*models.EducationalRoadmap, error) {
 	// Verificar se já existe roadmap educacional para este item
-	existing, err := s.educationalRoadmapRepo.GetByRoadmapItemID(roadmapItemID)
+	existing, err := s.educationalRoadmapRepo.GetByRoadmapItemID(ctx, roadmapItemID)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao verificar roadmap educacional existente: %w", err)
 	}
@@ -232,10 +176,20 @@ func (s *RoadmapService) GenerateEducationalRoadmap(roadmapItemID int64, itemTit
 		return existing, nil
 	}
 
+	itemPlanning, err := s.roadmapRepo.GetPlanningByRoadmapItemID(ctx, roadmapItemID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar OKR: %w", err)
+	}
+	if itemPlanning != nil {
+		if err := requireOKRStatus(itemPlanning.OKR, "gerar o roadmap educacional", roadmapGenerationStatuses); err != nil {
+			return nil, err
+		}
+	}
+
 	// Gerar roadmap educacional via Spellbook
-	educationalRoadmapResp, err := s.spellbookClient.GenerateEducationalRoadmap(itemTitle)
+	educationalRoadmapResp, err := s.spellbookClient.GenerateEducationalRoadmap(ctx, itemTitle)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao gerar roadmap educacional: %w", err)
+		return nil, apperrors.Upstream("erro ao gerar roadmap educacional", err)
 	}
 
 	// Converter resposta do Spellbook para modelo interno
@@ -314,24 +268,26 @@ func (s *RoadmapService) GenerateEducationalRoadmap(roadmapItemID int64, itemTit
 	}
 
 	// Salvar no banco
-	if err := s.educationalRoadmapRepo.Create(educationalRoadmap); err != nil {
+	if err := s.educationalRoadmapRepo.Create(ctx, educationalRoadmap); err != nil {
 		return nil, fmt.Errorf("erro ao salvar roadmap educacional: %w", err)
 	}
 
 	return educationalRoadmap, nil
 }
 
-func (s *RoadmapService) GetEducationalRoadmapByRoadmapItemID(roadmapItemID int64) (*models.EducationalRoadmap, error) {
-	return s.educationalRoadmapRepo.GetByRoadmapItemID(roadmapItemID)
+func (s *RoadmapService) GetEducationalRoadmapByRoadmapItemID(ctx context.Context, roadmapItemID int64) (*models.EducationalRoadmap, error) {
+	return s.educationalRoadmapRepo.GetByRoadmapItemID(ctx, roadmapItemID)
 }
 
-func (s *RoadmapService) UpdateEducationalResourceCompleted(resourceID int64, completed bool) error {
-	return s.educationalRoadmapRepo.UpdateResourceCompleted(resourceID, completed)
+func (s *RoadmapService) UpdateEducationalResourceCompleted(ctx context.Context, resourceID int64, completed bool) error {
+	return s.educationalRoadmapRepo.UpdateResourceCompleted(ctx, resourceID, completed)
 }
 
-func (s *RoadmapService) GenerateEducationalTrail(roadmapItemID int64, itemTitle string) (*models.EducationalTrail, error) {
+func (s *RoadmapService) GenerateEducationalTrail(ctx context.Context, roadmapItemID int64, itemTitle string) (*models.EducationalTrail, error) {
+	logger := logging.FromContext(ctx)
+
 	// Verificar se já existe trilha para este item
-	existing, err := s.educationalTrailRepo.GetByRoadmapItemID(roadmapItemID)
+	existing, err := s.educationalTrailRepo.GetByRoadmapItemID(ctx, roadmapItemID)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao verificar trilha existente: %w", err)
 	}
@@ -340,97 +296,26 @@ func (s *RoadmapService) GenerateEducationalTrail(roadmapItemID int64, itemTitle
 	}
 
 	// Buscar OKR, Key Result e calcular tempo disponível
-	okr, keyResult, totalKeyResults, totalRoadmapItems, err := s.roadmapRepo.GetOKRByRoadmapItemID(roadmapItemID)
+	itemPlanning, err := s.roadmapRepo.GetPlanningByRoadmapItemID(ctx, roadmapItemID)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao buscar OKR: %w", err)
 	}
-
-	var availableDays *int
-	now := time.Now()
-	
-	// Prioridade 1: Usar expected_completion_date do Key Result se disponível
-	if keyResult != nil && keyResult.ExpectedCompletionDate != nil {
-		expectedDate := *keyResult.ExpectedCompletionDate
-		daysRemaining := int(expectedDate.Sub(now).Hours() / 24)
-		
-		fmt.Printf("[DEBUG] GenerateEducationalTrail - RoadmapItemID: %d, KeyResultExpectedDate: %v, DaysRemaining: %d, TotalRoadmapItems: %d\n", 
-			roadmapItemID, expectedDate, daysRemaining, totalRoadmapItems)
-		
-		if daysRemaining > 0 && totalRoadmapItems > 0 {
-			// Dividir o tempo do Key Result diretamente pelo número total de itens do roadmap
-			// Na estrutura de grade curricular, todos os itens (aulas) devem ter conteúdo (trilhas)
-			calculatedDays := daysRemaining / totalRoadmapItems
-			
-			fmt.Printf("[DEBUG] GenerateEducationalTrail - CalculatedDays (before limits): %d (DaysRemaining: %d / TotalItems: %d)\n", 
-				calculatedDays, daysRemaining, totalRoadmapItems)
-			
-			// Aplicar limites: mínimo 3 dias, máximo 30 dias
-			if calculatedDays < 3 {
-				calculatedDays = 3
-			} else if calculatedDays > 30 {
-				calculatedDays = 30
-			}
-			
-			fmt.Printf("[DEBUG] GenerateEducationalTrail - FinalAvailableDays: %d\n", calculatedDays)
-			availableDays = &calculatedDays
-		} else if daysRemaining > 0 {
-			// Se não houver itens no roadmap, usar o tempo do Key Result diretamente
-			if daysRemaining < 3 {
-				daysRemaining = 3
-			} else if daysRemaining > 30 {
-				daysRemaining = 30
-			}
-			availableDays = &daysRemaining
-		}
-	}
-	
-	// Prioridade 2: Se não tiver expected_completion_date do Key Result, calcular baseado no OKR
-	if availableDays == nil && okr != nil && okr.CompletionDate != nil && totalKeyResults > 0 {
-		completionDate := *okr.CompletionDate
-		
-		// Calcular dias restantes do OKR
-		daysRemaining := int(completionDate.Sub(now).Hours() / 24)
-		
-		if daysRemaining > 0 {
-			// Primeiro dividir o tempo pelo número de Key Results (tempo do Key Result)
-			daysPerKeyResult := daysRemaining / totalKeyResults
-			
-			// Depois dividir pelo número total de itens do roadmap
-			// Na estrutura de grade curricular, todos os itens (aulas) devem ter conteúdo (trilhas)
-			if totalRoadmapItems > 0 {
-				// Dividir o tempo do Key Result diretamente pelo número total de itens
-				calculatedDays := daysPerKeyResult / totalRoadmapItems
-				
-				// Aplicar limites: mínimo 3 dias, máximo 30 dias
-				if calculatedDays < 3 {
-					calculatedDays = 3
-				} else if calculatedDays > 30 {
-					calculatedDays = 30
-				}
-				
-				availableDays = &calculatedDays
-			} else {
-				// Se não houver itens no roadmap, usar o tempo do Key Result
-				if daysPerKeyResult < 3 {
-					daysPerKeyResult = 3
-				} else if daysPerKeyResult > 30 {
-					daysPerKeyResult = 30
-				}
-				availableDays = &daysPerKeyResult
-			}
+	if itemPlanning != nil {
+		if err := requireOKRStatus(itemPlanning.OKR, "gerar a trilha educacional", roadmapGenerationStatuses); err != nil {
+			return nil, err
 		}
 	}
-	
-	// Se não calculou tempo ou completion_date é nulo/passado, usar padrão de 3 dias (mínimo)
-	if availableDays == nil {
-		defaultDays := 3
-		availableDays = &defaultDays
+
+	availableDays, err := s.trailDays(ctx, itemPlanning)
+	if err != nil {
+		return nil, err
 	}
+	logger.Debug("GenerateEducationalTrail: dias disponíveis", "roadmap_item_id", roadmapItemID, "available_days", availableDays)
 
 	// Gerar trilha educacional via Spellbook
-	trailResp, err := s.spellbookClient.GenerateEducationalTrail(itemTitle, availableDays)
+	trailResp, err := s.spellbookClient.GenerateEducationalTrail(ctx, itemTitle, &availableDays)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao gerar trilha educacional: %w", err)
+		return nil, apperrors.Upstream("erro ao gerar trilha educacional", err)
 	}
 
 	// Converter resposta do Spellbook para modelo interno
@@ -458,9 +343,10 @@ func (s *RoadmapService) GenerateEducationalTrail(roadmapItemID int64, itemTitle
 		// Validar URL do recurso
 		if resource.URL != "" {
 			valid, err := utils.ValidateURL(resource.URL)
+			metrics.ObserveURLValidation("resource", valid && err == nil)
 			if !valid || err != nil {
 				// Logar URL inválida mas não falhar - apenas remover URL
-				fmt.Printf("URL inválida removida do recurso %s: %s - Erro: %v\n", resourceID, resource.URL, err)
+				logger.Warn("URL inválida removida do recurso", "resource_id", resourceID, "url", resource.URL, "error", err)
 				resource.URL = ""
 			}
 		}
@@ -494,9 +380,10 @@ func (s *RoadmapService) GenerateEducationalTrail(roadmapItemID int64, itemTitle
 			// Validar URL da atividade
 			if activity.URL != "" {
 				valid, err := utils.ValidateURL(activity.URL)
+				metrics.ObserveURLValidation("activity", valid && err == nil)
 				if !valid || err != nil {
 					// Logar URL inválida mas não falhar - apenas remover URL
-					fmt.Printf("URL inválida removida da atividade %s: %s - Erro: %v\n", activity.Title, activity.URL, err)
+					logger.Warn("URL inválida removida da atividade", "activity", activity.Title, "url", activity.URL, "error", err)
 					activity.URL = ""
 				}
 			}
@@ -507,23 +394,230 @@ func (s *RoadmapService) GenerateEducationalTrail(roadmapItemID int64, itemTitle
 		trail.Steps = append(trail.Steps, step)
 	}
 
+	// Agendar os dias da trilha no calendário de estudo a partir de hoje
+	calendar, err := s.studyCalendar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	startDate := planning.Date(s.planner.Now())
+	trail.StartDate = &startDate
+	scheduleSteps(calendar, startDate, trail.Steps)
+
 	// Salvar no banco
-	if err := s.educationalTrailRepo.Create(trail); err != nil {
+	if err := s.educationalTrailRepo.Create(ctx, trail); err != nil {
 		return nil, fmt.Errorf("erro ao salvar trilha educacional: %w", err)
 	}
+	estimateActivities(trail)
+
+	return trail, nil
+}
+
+// roadmapDays calcula os dias disponíveis para o roadmap de um Key Result
+func (s *RoadmapService) roadmapDays(ctx context.Context, kr *models.KeyResult) (int, error) {
+	// Prioridade 1: Usar expected_completion_date do Key Result se disponível
+	if kr.ExpectedCompletionDate != nil {
+		if daysRemaining := s.planner.DaysUntil(*kr.ExpectedCompletionDate); daysRemaining > 0 {
+			// Aplicar limite mínimo (3 dias) para evitar roadmaps muito curtos
+			return max(daysRemaining, s.planner.MinTrailDays()), nil
+		}
+	}
+
+	// Prioridade 2: Se não tiver expected_completion_date, usar a parte do prazo do OKR
+	// que cabe a este Key Result segundo a estratégia de planejamento
+	okr, err := s.okrRepo.GetByID(ctx, kr.OKRID)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao buscar OKR: %w", err)
+	}
+	if okr != nil && okr.CompletionDate != nil {
+		if daysRemaining := s.planner.DaysUntil(*okr.CompletionDate); daysRemaining > 0 {
+			allKeyResults, err := s.keyResultRepo.GetByOKRID(ctx, okr.ID)
+			if err != nil {
+				return 0, fmt.Errorf("erro ao buscar Key Results: %w", err)
+			}
+
+			position := slices.IndexFunc(allKeyResults, func(other models.KeyResult) bool { return other.ID == kr.ID })
+			if position >= 0 {
+				days := s.planner.Share(daysRemaining, planning.KeyResultWeights(allKeyResults), position)
+				return max(days, s.planner.MinTrailDays()), nil
+			}
+		}
+	}
+
+	// Se não calculou tempo ou completion_date é nulo/passado, usar padrão configurado (30 dias)
+	return s.planner.DefaultRoadmapDays(), nil
+}
+
+// trailDays calcula os dias disponíveis para a trilha educacional de um item do roadmap.
+// Na estrutura de grade curricular, todos os itens (aulas) devem ter conteúdo (trilhas),
+// então o prazo do Key Result é distribuído entre os itens do roadmap
+func (s *RoadmapService) trailDays(ctx context.Context, item *repositories.RoadmapItemPlanning) (int, error) {
+	if item == nil {
+		return s.planner.MinTrailDays(), nil
+	}
+
+	// Prioridade 1: Usar expected_completion_date do Key Result se disponível
+	if item.KeyResult.ExpectedCompletionDate != nil {
+		if daysRemaining := s.planner.DaysUntil(*item.KeyResult.ExpectedCompletionDate); daysRemaining > 0 {
+			return s.itemShare(daysRemaining, item), nil
+		}
+	}
+
+	// Prioridade 2: Usar a parte do prazo do OKR que cabe ao Key Result
+	if item.OKR.CompletionDate != nil && item.TotalKeyResults > 0 {
+		if daysRemaining := s.planner.DaysUntil(*item.OKR.CompletionDate); daysRemaining > 0 {
+			allKeyResults, err := s.keyResultRepo.GetByOKRID(ctx, item.OKR.ID)
+			if err != nil {
+				return 0, fmt.Errorf("erro ao buscar Key Results: %w", err)
+			}
+
+			position := slices.IndexFunc(allKeyResults, func(other models.KeyResult) bool { return other.ID == item.KeyResult.ID })
+			if position >= 0 {
+				daysPerKeyResult := s.planner.Share(daysRemaining, planning.KeyResultWeights(allKeyResults), position)
+				return s.itemShare(daysPerKeyResult, item), nil
+			}
+		}
+	}
+
+	// Se não calculou tempo ou completion_date é nulo/passado, usar o mínimo configurado (3 dias)
+	return s.planner.MinTrailDays(), nil
+}
 
+// itemShare distribui os dias do Key Result entre os itens do roadmap e aplica os
+// limites de uma trilha à parte do item
+func (s *RoadmapService) itemShare(keyResultDays int, item *repositories.RoadmapItemPlanning) int {
+	if item.TotalRoadmapItems == 0 {
+		// Se não houver itens no roadmap, usar o tempo do Key Result diretamente
+		return s.planner.ClampTrailDays(keyResultDays)
+	}
+	days := s.planner.Share(keyResultDays, planning.Uniform(item.TotalRoadmapItems), item.ItemPosition)
+	return s.planner.ClampTrailDays(days)
+}
+
+func (s *RoadmapService) GetEducationalTrailByRoadmapItemID(ctx context.Context, roadmapItemID int64) (*models.EducationalTrail, error) {
+	trail, err := s.educationalTrailRepo.GetByRoadmapItemID(ctx, roadmapItemID)
+	if err != nil || trail == nil {
+		return trail, err
+	}
+	trail.OverdueSteps = s.overdueSteps(trail)
+	estimateActivities(trail)
 	return trail, nil
 }
 
-func (s *RoadmapService) GetEducationalTrailByRoadmapItemID(roadmapItemID int64) (*models.EducationalTrail, error) {
-	return s.educationalTrailRepo.GetByRoadmapItemID(roadmapItemID)
+// ReplanEducationalTrail reagenda as etapas pendentes da trilha em dias de estudo
+// consecutivos a partir de startDate (ou de hoje), mantendo a ordem e as lacunas entre
+// os dias. Etapas concluídas mantêm a data em que estavam agendadas
+func (s *RoadmapService) ReplanEducationalTrail(ctx context.Context, roadmapItemID int64, startDate *time.Time) (*models.EducationalTrail, error) {
+	trail, err := s.educationalTrailRepo.GetByRoadmapItemID(ctx, roadmapItemID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar trilha educacional: %w", err)
+	}
+	if trail == nil {
+		return nil, apperrors.NotFound("trilha educacional não encontrada")
+	}
+
+	calendar, err := s.studyCalendar(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	start := planning.Date(s.planner.Now())
+	if startDate != nil {
+		start = planning.Date(*startDate)
+	}
+
+	// O primeiro dia pendente passa a ser o dia 1 do novo planejamento
+	pending := make([]models.EducationalTrailStep, 0, len(trail.Steps))
+	for _, step := range trail.Steps {
+		if !step.Completed() {
+			pending = append(pending, step)
+		}
+	}
+	if len(pending) > 0 {
+		offset := pending[0].Day - 1
+		for i := range pending {
+			pending[i].Day -= offset
+		}
+		scheduleSteps(calendar, start, pending)
+	}
+
+	if err := s.educationalTrailRepo.UpdateSchedule(ctx, trail.ID, start, pending); err != nil {
+		return nil, fmt.Errorf("erro ao salvar planejamento da trilha: %w", err)
+	}
+
+	logging.FromContext(ctx).Info("trilha educacional replanejada",
+		"roadmap_item_id", roadmapItemID, "start_date", start.Format("2006-01-02"), "pending_steps", len(pending))
+
+	return s.GetEducationalTrailByRoadmapItemID(ctx, roadmapItemID)
+}
+
+// studyCalendar monta o calendário de estudo a partir das configurações do usuário
+func (s *RoadmapService) studyCalendar(ctx context.Context) (planning.Calendar, error) {
+	settings, err := s.studySettingsRepo.Get(ctx)
+	if err != nil {
+		return planning.Calendar{}, fmt.Errorf("erro ao buscar configurações de estudo: %w", err)
+	}
+	return planning.NewCalendar(settings), nil
+}
+
+// overdueSteps conta as etapas pendentes agendadas para antes de hoje
+func (s *RoadmapService) overdueSteps(trail *models.EducationalTrail) int {
+	// Compara pela data do calendário: scheduled_date vem de uma coluna DATE (em UTC)
+	today := s.planner.Now().Format("2006-01-02")
+	overdue := 0
+	for _, step := range trail.Steps {
+		if step.ScheduledDate != nil && step.ScheduledDate.Format("2006-01-02") < today && !step.Completed() {
+			overdue++
+		}
+	}
+	return overdue
+}
+
+// estimateActivities preenche a duração estimada, em minutos, de cada atividade
+func estimateActivities(trail *models.EducationalTrail) {
+	for i := range trail.Steps {
+		for j := range trail.Steps[i].Activities {
+			activity := &trail.Steps[i].Activities[j]
+			if minutes, ok := planning.ParseMinutes(activity.Duration); ok {
+				activity.EstimatedMinutes = &minutes
+			}
+		}
+	}
+}
+
+// scheduleSteps preenche a data agendada de cada etapa a partir do seu dia relativo e
+// da duração estimada das atividades pendentes, respeitando as horas de estudo por dia
+func scheduleSteps(calendar planning.Calendar, start time.Time, steps []models.EducationalTrailStep) {
+	planned := make([]planning.Step, len(steps))
+	for i, step := range steps {
+		planned[i] = planning.Step{Day: step.Day, Minutes: pendingMinutes(step)}
+	}
+	for i, date := range calendar.ScheduleSteps(start, planned) {
+		steps[i].ScheduledDate = date
+	}
+}
+
+// pendingMinutes soma a duração estimada das atividades não concluídas da etapa.
+// Retorna nil quando alguma delas não tem duração reconhecível
+func pendingMinutes(step models.EducationalTrailStep) *int {
+	total := 0
+	for _, activity := range step.Activities {
+		if activity.Completed {
+			continue
+		}
+		minutes, ok := planning.ParseMinutes(activity.Duration)
+		if !ok {
+			return nil
+		}
+		total += minutes
+	}
+	return &total
 }
 
-func (s *RoadmapService) DeleteEducationalTrail(roadmapItemID int64) error {
-	return s.educationalTrailRepo.DeleteByRoadmapItemID(roadmapItemID)
+func (s *RoadmapService) DeleteEducationalTrail(ctx context.Context, roadmapItemID int64) error {
+	return s.educationalTrailRepo.DeleteByRoadmapItemID(ctx, roadmapItemID)
 }
 
-func (s *RoadmapService) UpdateTrailActivityCompleted(activityID int64, completed bool) error {
-	return s.educationalTrailRepo.UpdateActivityCompleted(activityID, completed)
+func (s *RoadmapService) UpdateTrailActivityCompleted(ctx context.Context, activityID int64, completed bool) error {
+	return s.educationalTrailRepo.UpdateActivityCompleted(ctx, activityID, completed)
 }
 
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"net/url"
+	"sort"
+
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/conquista-ai/conquista-ai/internal/planning"
+	"github.com/conquista-ai/conquista-ai/internal/repositories"
+)
+
+// ShareService gerencia os links públicos de leitura dos roadmaps e monta a visão
+// compartilhada, sem dados internos
+type ShareService struct {
+	shareRepo            *repositories.ShareRepository
+	roadmapRepo          *repositories.RoadmapRepository
+	educationalTrailRepo *repositories.EducationalTrailRepository
+	keyResultRepo        *repositories.KeyResultRepository
+	clock                planning.Clock
+}
+
+func NewShareService(
+	shareRepo *repositories.ShareRepository,
+	roadmapRepo *repositories.RoadmapRepository,
+	educationalTrailRepo *repositories.EducationalTrailRepository,
+	keyResultRepo *repositories.KeyResultRepository,
+	clock planning.Clock,
+) *ShareService {
+	return &ShareService{
+		shareRepo:            shareRepo,
+		roadmapRepo:          roadmapRepo,
+		educationalTrailRepo: educationalTrailRepo,
+		keyResultRepo:        keyResultRepo,
+		clock:                clock,
+	}
+}
+
+// Create gera um link público para o roadmap do Key Result. O token volta apenas
+// nesta resposta
+func (s *ShareService) Create(ctx context.Context, keyResultID int64, req models.CreateShareLinkRequest) (*models.ShareLink, error) {
+	roadmap, err := s.roadmapRepo.GetByKeyResultID(ctx, keyResultID)
+	if err != nil {
+		return nil, apperrors.Wrap("erro ao buscar roadmap", err)
+	}
+	if roadmap == nil {
+		return nil, apperrors.NotFound("roadmap não encontrado")
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, apperrors.Internal("erro ao gerar token do link", err)
+	}
+
+	now := s.clock.Now()
+	link := &models.ShareLink{KeyResultID: keyResultID}
+	if req.ExpiresInDays != nil {
+		expiresAt := now.AddDate(0, 0, *req.ExpiresInDays)
+		link.ExpiresAt = &expiresAt
+	}
+	if err := s.shareRepo.Create(ctx, link, hashToken(token), now); err != nil {
+		return nil, apperrors.Wrap("erro ao salvar link", err)
+	}
+	link.Token = token
+	return link, nil
+}
+
+// List retorna os links do roadmap do Key Result, inclusive revogados e expirados
+func (s *ShareService) List(ctx context.Context, keyResultID int64) ([]models.ShareLink, error) {
+	links, err := s.shareRepo.ListByKeyResultID(ctx, keyResultID)
+	if err != nil {
+		return nil, apperrors.Wrap("erro ao buscar links", err)
+	}
+	return links, nil
+}
+
+// Revoke desativa o link imediatamente. Revogar um link já revogado não tem efeito
+func (s *ShareService) Revoke(ctx context.Context, id int64) (*models.ShareLink, error) {
+	link, err := s.shareRepo.Revoke(ctx, id, s.clock.Now())
+	if err != nil {
+		return nil, apperrors.Wrap("erro ao revogar link", err)
+	}
+	if link == nil {
+		return nil, apperrors.NotFound("link não encontrado")
+	}
+	return link, nil
+}
+
+// View valida o token, conta a visualização e monta a visão pública do roadmap. Tokens
+// inválidos, revogados ou expirados e roadmaps excluídos respondem igualmente como não
+// encontrados, sem revelar qual é o caso
+func (s *ShareService) View(ctx context.Context, token string) (*models.SharedRoadmap, error) {
+	notFound := apperrors.NotFound("link não encontrado ou expirado")
+
+	link, err := s.shareRepo.View(ctx, hashToken(token), s.clock.Now())
+	if err != nil {
+		return nil, apperrors.Wrap("erro ao validar link", err)
+	}
+	if link == nil {
+		return nil, notFound
+	}
+
+	kr, err := s.keyResultRepo.GetByID(ctx, link.KeyResultID)
+	if err != nil {
+		return nil, apperrors.Wrap("erro ao buscar Key Result", err)
+	}
+	roadmap, err := s.roadmapRepo.GetByKeyResultID(ctx, link.KeyResultID)
+	if err != nil {
+		return nil, apperrors.Wrap("erro ao buscar roadmap", err)
+	}
+	if kr == nil || roadmap == nil {
+		return nil, notFound
+	}
+
+	shared := &models.SharedRoadmap{
+		Topic:      roadmap.Topic,
+		KeyResult:  kr.Title,
+		Categories: make([]models.SharedRoadmapCategory, 0, len(roadmap.Categories)),
+		ExpiresAt:  link.ExpiresAt,
+	}
+	for _, category := range roadmap.Categories {
+		sharedCategory := models.SharedRoadmapCategory{
+			Name:  category.Category,
+			Items: make([]models.SharedRoadmapItem, 0, len(category.Items)),
+		}
+		for _, item := range category.Items {
+			trail, err := s.educationalTrailRepo.GetByRoadmapItemID(ctx, item.ID)
+			if err != nil {
+				return nil, apperrors.Wrap("erro ao buscar trilha educacional", err)
+			}
+			sharedCategory.Items = append(sharedCategory.Items, models.SharedRoadmapItem{
+				Title:     item.Title,
+				Completed: item.Completed,
+				Trail:     sharedTrail(trail),
+			})
+		}
+		shared.Categories = append(shared.Categories, sharedCategory)
+	}
+	return shared, nil
+}
+
+// sharedTrail copia da trilha apenas os campos públicos. Links que não sejam http(s)
+// são descartados
+func sharedTrail(trail *models.EducationalTrail) *models.SharedTrail {
+	if trail == nil {
+		return nil
+	}
+
+	shared := &models.SharedTrail{
+		Topic:       trail.Topic,
+		Description: trail.Description,
+		TotalDays:   trail.TotalDays,
+		StartDate:   trail.StartDate,
+		Steps:       make([]models.SharedTrailStep, 0, len(trail.Steps)),
+		Resources:   make([]models.SharedTrailResource, 0, len(trail.Resources)),
+	}
+	for _, step := range trail.Steps {
+		sharedStep := models.SharedTrailStep{
+			Day:           step.Day,
+			Title:         step.Title,
+			Description:   step.Description,
+			ScheduledDate: step.ScheduledDate,
+			Completed:     step.Completed(),
+			Activities:    make([]models.SharedTrailActivity, 0, len(step.Activities)),
+		}
+		for _, activity := range step.Activities {
+			sharedStep.Activities = append(sharedStep.Activities, models.SharedTrailActivity{
+				Type:        activity.Type,
+				Title:       activity.Title,
+				Description: activity.Description,
+				Duration:    activity.Duration,
+				URL:         publicURL(activity.URL),
+				Chapters:    activity.Chapters,
+				Completed:   activity.Completed,
+			})
+		}
+		shared.Steps = append(shared.Steps, sharedStep)
+	}
+	for _, resource := range trail.Resources {
+		shared.Resources = append(shared.Resources, models.SharedTrailResource{
+			Title:       resource.Title,
+			Description: resource.Description,
+			Author:      resource.Author,
+			Duration:    resource.Duration,
+			URL:         publicURL(resource.URL),
+			Chapters:    resource.Chapters,
+		})
+	}
+	sort.Slice(shared.Resources, func(i, j int) bool { return shared.Resources[i].Title < shared.Resources[j].Title })
+	return shared
+}
+
+// publicURL mantém apenas URLs http(s), evitando links javascript: e similares na
+// página pública
+func publicURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ""
+	}
+	return raw
+}
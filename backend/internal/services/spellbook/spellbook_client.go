@@ -2,11 +2,17 @@ package spellbook
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/logging"
+	"github.com/conquista-ai/conquista-ai/internal/metrics"
+	"github.com/conquista-ai/conquista-ai/internal/requestid"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 type Client struct {
@@ -14,15 +20,88 @@ type Client struct {
 	httpClient *http.Client
 }
 
-func NewClient(baseURL string) *Client {
+// NewClient cria o cliente da API Spellbook. O timeout precisa comportar as gerações
+// de trilhas educacionais complexas, que levam alguns minutos
+func NewClient(baseURL string, timeout time.Duration) *Client {
 	return &Client{
 		baseURL: baseURL,
 		httpClient: &http.Client{
-			Timeout: 180 * time.Second, // 3 minutos para trilhas educacionais complexas
+			Timeout: timeout,
+			// Cria um span por chamada e propaga o contexto de trace para o Spellbook
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
 		},
 	}
 }
 
+// Ping verifica se a API Spellbook está acessível. Qualquer resposta abaixo de 500
+// indica que o serviço está no ar, mesmo que a rota raiz não exista
+func (c *Client) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao criar requisição: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Spellbook inacessível: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("Spellbook respondeu com status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// post envia reqBody como JSON para o endpoint do Spellbook e decodifica a resposta em out.
+// O ID da requisição presente no contexto é repassado no cabeçalho X-Request-ID
+func (c *Client) post(ctx context.Context, endpoint string, reqBody interface{}, out interface{}) error {
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar requisição: %w", err)
+	}
+
+	url := fmt.Sprintf("%s%s", c.baseURL, endpoint)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("erro ao criar requisição: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if id := requestid.FromContext(ctx); id != "" {
+		req.Header.Set(requestid.Header, id)
+	}
+
+	logger := logging.FromContext(ctx).With("endpoint", endpoint)
+	start := time.Now()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		metrics.ObserveSpellbookCall(endpoint, time.Since(start), metrics.SpellbookErrorTransport)
+		logger.Error("falha na chamada ao Spellbook", "error", err, "duration_ms", time.Since(start).Milliseconds())
+		return fmt.Errorf("erro ao fazer requisição: %w", err)
+	}
+	defer resp.Body.Close()
+
+	logger.Info("chamada ao Spellbook concluída", "status", resp.StatusCode, "duration_ms", time.Since(start).Milliseconds())
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		metrics.ObserveSpellbookCall(endpoint, time.Since(start), metrics.SpellbookErrorStatus)
+		return fmt.Errorf("erro na API Spellbook: status %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		metrics.ObserveSpellbookCall(endpoint, time.Since(start), metrics.SpellbookErrorDecode)
+		return fmt.Errorf("erro ao decodificar resposta: %w", err)
+	}
+
+	metrics.ObserveSpellbookCall(endpoint, time.Since(start), "")
+	return nil
+}
+
 // ============================================================================
 // Topics API Types
 // ============================================================================
@@ -104,45 +183,21 @@ type EducationalResource struct {
 	Author      string   `json:"author,omitempty"`
 }
 
-func (c *Client) GenerateTopics(subject string, count int) (*TopicsResponse, error) {
+func (c *Client) GenerateTopics(ctx context.Context, subject string, count int) (*TopicsResponse, error) {
 	reqBody := TopicsRequest{
 		Subject: subject,
 		Count:   count,
 	}
 
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao serializar requisição: %w", err)
-	}
-
-	url := fmt.Sprintf("%s/api/v1/topics", c.baseURL)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("erro ao criar requisição: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao fazer requisição: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("erro na API Spellbook: status %d, body: %s", resp.StatusCode, string(body))
-	}
-
 	var topicsResp TopicsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&topicsResp); err != nil {
-		return nil, fmt.Errorf("erro ao decodificar resposta: %w", err)
+	if err := c.post(ctx, "/api/v1/topics", reqBody, &topicsResp); err != nil {
+		return nil, err
 	}
 
 	return &topicsResp, nil
 }
 
-func (c *Client) GenerateKeyResults(objective string, count int, completionDate *time.Time) (*KeyResultsResponse, error) {
+func (c *Client) GenerateKeyResults(ctx context.Context, objective string, count int, completionDate *time.Time) (*KeyResultsResponse, error) {
 	var completionDateStr *string
 	if completionDate != nil {
 		formatted := completionDate.Format("2006-01-02")
@@ -155,72 +210,24 @@ func (c *Client) GenerateKeyResults(objective string, count int, completionDate
 		CompletionDate: completionDateStr,
 	}
 
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao serializar requisição: %w", err)
-	}
-
-	url := fmt.Sprintf("%s/api/v1/key-results", c.baseURL)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("erro ao criar requisição: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao fazer requisição: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("erro na API Spellbook: status %d, body: %s", resp.StatusCode, string(body))
-	}
-
 	var keyResultsResp KeyResultsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&keyResultsResp); err != nil {
-		return nil, fmt.Errorf("erro ao decodificar resposta: %w", err)
+	if err := c.post(ctx, "/api/v1/key-results", reqBody, &keyResultsResp); err != nil {
+		return nil, err
 	}
 
 	return &keyResultsResp, nil
 }
 
-func (c *Client) GenerateRoadmap(topic string, availableDays *int, exactItemCount *int) (*RoadmapResponse, error) {
+func (c *Client) GenerateRoadmap(ctx context.Context, topic string, availableDays *int, exactItemCount *int) (*RoadmapResponse, error) {
 	reqBody := RoadmapRequest{
 		Topic:        topic,
 		AvailableDays: availableDays,
 		ExactItemCount: exactItemCount,
 	}
 
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao serializar requisição: %w", err)
-	}
-
-	url := fmt.Sprintf("%s/api/v1/roadmap", c.baseURL)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("erro ao criar requisição: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao fazer requisição: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("erro na API Spellbook: status %d, body: %s", resp.StatusCode, string(body))
-	}
-
 	var roadmapResp RoadmapResponse
-	if err := json.NewDecoder(resp.Body).Decode(&roadmapResp); err != nil {
-		return nil, fmt.Errorf("erro ao decodificar resposta: %w", err)
+	if err := c.post(ctx, "/api/v1/roadmap", reqBody, &roadmapResp); err != nil {
+		return nil, err
 	}
 
 	return &roadmapResp, nil
@@ -232,38 +239,14 @@ func (c *Client) GenerateRoadmap(topic string, availableDays *int, exactItemCoun
 
 // GenerateEducationalRoadmap gera um roadmap educacional detalhado para um tópico específico,
 // incluindo livros, cursos, vídeos, artigos e projetos lúdicos para consolidar o conhecimento.
-func (c *Client) GenerateEducationalRoadmap(topic string) (*EducationalRoadmapResponse, error) {
+func (c *Client) GenerateEducationalRoadmap(ctx context.Context, topic string) (*EducationalRoadmapResponse, error) {
 	reqBody := EducationalRoadmapRequest{
 		Topic: topic,
 	}
 
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao serializar requisição: %w", err)
-	}
-
-	url := fmt.Sprintf("%s/api/v1/educational-roadmap", c.baseURL)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("erro ao criar requisição: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao fazer requisição: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("erro na API Spellbook: status %d, body: %s", resp.StatusCode, string(body))
-	}
-
 	var roadmapResp EducationalRoadmapResponse
-	if err := json.NewDecoder(resp.Body).Decode(&roadmapResp); err != nil {
-		return nil, fmt.Errorf("erro ao decodificar resposta: %w", err)
+	if err := c.post(ctx, "/api/v1/educational-roadmap", reqBody, &roadmapResp); err != nil {
+		return nil, err
 	}
 
 	return &roadmapResp, nil
@@ -314,39 +297,15 @@ type EducationalTrailResponse struct {
 }
 
 // GenerateEducationalTrail gera uma trilha educacional estruturada em dias/etapas
-func (c *Client) GenerateEducationalTrail(topic string, availableDays *int) (*EducationalTrailResponse, error) {
+func (c *Client) GenerateEducationalTrail(ctx context.Context, topic string, availableDays *int) (*EducationalTrailResponse, error) {
 	reqBody := EducationalTrailRequest{
 		Topic:        topic,
 		AvailableDays: availableDays,
 	}
 
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao serializar requisição: %w", err)
-	}
-
-	url := fmt.Sprintf("%s/api/v1/educational-trail", c.baseURL)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("erro ao criar requisição: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao fazer requisição: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("erro na API Spellbook: status %d, body: %s", resp.StatusCode, string(body))
-	}
-
 	var trailResp EducationalTrailResponse
-	if err := json.NewDecoder(resp.Body).Decode(&trailResp); err != nil {
-		return nil, fmt.Errorf("erro ao decodificar resposta: %w", err)
+	if err := c.post(ctx, "/api/v1/educational-trail", reqBody, &trailResp); err != nil {
+		return nil, err
 	}
 
 	return &trailResp, nil
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/apperrors"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/conquista-ai/conquista-ai/internal/planning"
+	"github.com/conquista-ai/conquista-ai/internal/repositories"
+)
+
+// Semanas exibidas por padrão e no máximo em GET /stats/study
+const (
+	DefaultStudyStatsWeeks = 8
+	MaxStudyStatsWeeks     = 52
+)
+
+// StudyService calcula as estatísticas de estudo: sequência de dias, minutos por
+// semana e categoria e a precisão das estimativas de duração das atividades
+type StudyService struct {
+	studyRepo         *repositories.StudyRepository
+	studySettingsRepo *repositories.StudySettingsRepository
+	clock             planning.Clock
+}
+
+func NewStudyService(studyRepo *repositories.StudyRepository, studySettingsRepo *repositories.StudySettingsRepository, clock planning.Clock) *StudyService {
+	return &StudyService{
+		studyRepo:         studyRepo,
+		studySettingsRepo: studySettingsRepo,
+		clock:             clock,
+	}
+}
+
+// StartTimer inicia o cronômetro de estudo da atividade
+func (s *StudyService) StartTimer(ctx context.Context, activityID int64) (*models.StudySession, error) {
+	return s.studyRepo.StartTimer(ctx, activityID, s.clock.Now())
+}
+
+// StopTimer encerra o cronômetro da atividade e registra o tempo decorrido
+func (s *StudyService) StopTimer(ctx context.Context, activityID int64) (*models.StudySession, error) {
+	return s.studyRepo.StopTimer(ctx, activityID, s.clock.Now())
+}
+
+// CreateSession lança manualmente minutes de estudo na atividade. Sem date, a sessão
+// termina agora; com date, começa à meia-noite do dia, que não pode ser futuro
+func (s *StudyService) CreateSession(ctx context.Context, activityID int64, minutes int, date *time.Time, note string) (*models.StudySession, error) {
+	now := s.clock.Now()
+	startedAt := now.Add(-time.Duration(minutes) * time.Minute)
+	if date != nil {
+		if date.Format("2006-01-02") > now.Format("2006-01-02") {
+			return nil, apperrors.InvalidField("date", "não é possível lançar estudo em uma data futura")
+		}
+		startedAt = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, now.Location())
+	}
+
+	session := &models.StudySession{
+		ActivityID: activityID,
+		StartedAt:  startedAt,
+		Minutes:    minutes,
+		Note:       note,
+	}
+	if err := s.studyRepo.CreateSession(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (s *StudyService) Stats(ctx context.Context, weeks int) (*models.StudyStats, error) {
+	now := s.clock.Now()
+	stats := &models.StudyStats{GeneratedAt: now}
+
+	settings, err := s.studySettingsRepo.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar configurações de estudo: %w", err)
+	}
+	dates, err := s.studyRepo.StudyDates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar dias de estudo: %w", err)
+	}
+	stats.Streak = studyStreak(planning.NewCalendar(settings), dates, planning.Date(now))
+
+	stats.Weekly, err = s.weekly(ctx, planning.Date(now), weeks)
+	if err != nil {
+		return nil, err
+	}
+
+	times, err := s.studyRepo.CompletedActivityTimes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar tempo das atividades: %w", err)
+	}
+	stats.Estimates = estimateReport(times)
+
+	return stats, nil
+}
+
+// studyStreak percorre os dias desde o primeiro estudo até hoje. Um dia de estudo sem
+// registro interrompe a sequência; dias fora do calendário (dias da semana sem estudo,
+// feriados e bloqueios) não interrompem. Hoje ainda sem registro também não interrompe
+func studyStreak(calendar planning.Calendar, dates []time.Time, today time.Time) models.StudyStreak {
+	var streak models.StudyStreak
+	if len(dates) == 0 {
+		return streak
+	}
+
+	// As datas vêm de colunas DATE (em UTC); a comparação é feita pela data do calendário
+	studied := make(map[string]bool, len(dates))
+	for _, date := range dates {
+		studied[date.Format("2006-01-02")] = true
+	}
+	last := dates[len(dates)-1]
+	streak.LastStudyDate = &last
+
+	first := time.Date(dates[0].Year(), dates[0].Month(), dates[0].Day(), 0, 0, 0, 0, today.Location())
+	run := 0
+	for day := first; !day.After(today); day = day.AddDate(0, 0, 1) {
+		key := day.Format("2006-01-02")
+		switch {
+		case studied[key]:
+			run++
+			streak.Longest = max(streak.Longest, run)
+		case day.Equal(today):
+		case calendar.IsStudyDay(day):
+			run = 0
+		}
+	}
+	streak.Current = run
+	streak.StudiedToday = studied[today.Format("2006-01-02")]
+
+	return streak
+}
+
+// weekly monta as últimas semanas (segunda a domingo), incluindo as semanas sem estudo
+func (s *StudyService) weekly(ctx context.Context, today time.Time, weeks int) ([]models.WeeklyStudyTime, error) {
+	monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	since := monday.AddDate(0, 0, -7*(weeks-1))
+
+	rows, err := s.studyRepo.WeeklyMinutes(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar minutos de estudo por semana: %w", err)
+	}
+
+	result := make([]models.WeeklyStudyTime, weeks)
+	index := make(map[string]int, weeks)
+	for i := range result {
+		weekStart := since.AddDate(0, 0, 7*i)
+		result[i] = models.WeeklyStudyTime{WeekStart: weekStart, Categories: make([]models.CategoryStudyTime, 0)}
+		index[weekStart.Format("2006-01-02")] = i
+	}
+
+	for _, row := range rows {
+		i, ok := index[row.WeekStart.Format("2006-01-02")]
+		if !ok {
+			continue
+		}
+		result[i].Minutes += row.Minutes
+		result[i].Categories = append(result[i].Categories, models.CategoryStudyTime{
+			CategoryID: row.CategoryID,
+			Name:       row.Name,
+			Minutes:    row.Minutes,
+		})
+	}
+
+	return result, nil
+}
+
+// estimateReport compara estimado e real das atividades com duração reconhecida
+func estimateReport(times []repositories.ActivityTime) models.StudyEstimateReport {
+	report := models.StudyEstimateReport{ByActivityType: make([]models.ActivityTypeEstimate, 0)}
+	byType := make(map[string]*models.StudyEstimate)
+
+	for _, t := range times {
+		estimated, ok := planning.ParseMinutes(t.Duration)
+		if !ok {
+			continue
+		}
+		typeEstimate, exists := byType[t.Type]
+		if !exists {
+			typeEstimate = &models.StudyEstimate{}
+			byType[t.Type] = typeEstimate
+		}
+		for _, e := range []*models.StudyEstimate{&report.StudyEstimate, typeEstimate} {
+			e.Activities++
+			e.EstimatedMinutes += estimated
+			e.ActualMinutes += t.ActualMinutes
+		}
+	}
+
+	report.Ratio = estimateRatio(report.StudyEstimate)
+	for activityType, e := range byType {
+		e.Ratio = estimateRatio(*e)
+		report.ByActivityType = append(report.ByActivityType, models.ActivityTypeEstimate{Type: activityType, StudyEstimate: *e})
+	}
+	sort.Slice(report.ByActivityType, func(i, j int) bool {
+		return report.ByActivityType[i].Type < report.ByActivityType[j].Type
+	})
+
+	return report
+}
+
+func estimateRatio(e models.StudyEstimate) *float64 {
+	if e.EstimatedMinutes == 0 {
+		return nil
+	}
+	ratio := math.Round(float64(e.ActualMinutes)/float64(e.EstimatedMinutes)*100) / 100
+	return &ratio
+}
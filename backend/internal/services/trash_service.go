@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/logging"
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/conquista-ai/conquista-ai/internal/planning"
+	"github.com/conquista-ai/conquista-ai/internal/repositories"
+)
+
+// TrashService lista a lixeira e remove definitivamente os itens excluídos há mais
+// tempo que o período de retenção. retention zero desativa o expurgo
+type TrashService struct {
+	repo      *repositories.TrashRepository
+	clock     planning.Clock
+	retention time.Duration
+}
+
+func NewTrashService(repo *repositories.TrashRepository, clock planning.Clock, retentionDays int) *TrashService {
+	return &TrashService{
+		repo:      repo,
+		clock:     clock,
+		retention: time.Duration(retentionDays) * 24 * time.Hour,
+	}
+}
+
+// List retorna os itens da lixeira com a data prevista de remoção definitiva
+func (s *TrashService) List(ctx context.Context) ([]models.TrashItem, error) {
+	items, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.retention > 0 {
+		for i := range items {
+			purgeAt := items[i].DeletedAt.Add(s.retention)
+			items[i].PurgeAt = &purgeAt
+		}
+	}
+	return items, nil
+}
+
+// Run remove definitivamente os itens que estão na lixeira há mais que o período de retenção
+func (s *TrashService) Run(ctx context.Context) error {
+	if s.retention <= 0 {
+		return nil
+	}
+	removed, err := s.repo.Purge(ctx, s.clock.Now().Add(-s.retention))
+	if err != nil {
+		return fmt.Errorf("erro ao esvaziar a lixeira: %w", err)
+	}
+	total := removed[models.TrashTypeOKR] + removed[models.TrashTypeKeyResult] + removed[models.TrashTypeRoadmap]
+	if total > 0 {
+		logging.FromContext(ctx).Info("itens removidos definitivamente da lixeira",
+			"okrs", removed[models.TrashTypeOKR],
+			"key_results", removed[models.TrashTypeKeyResult],
+			"roadmaps", removed[models.TrashTypeRoadmap])
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCSV(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewCSV(&buf, ';')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != utf8BOM {
+		t.Fatalf("o BOM deve ser gravado na criação, antes de qualquer linha: %q", buf.String())
+	}
+
+	title := "Ação; com \"aspas\""
+	var missing *string
+	rows := [][]any{
+		{"Objetivo", "Categoria", "Prazo", "Concluído", "Dificuldade"},
+		{"=HYPERLINK(\"http://x\")", "Carreira", time.Date(2026, 3, 2, 23, 30, 0, 0, time.UTC), true, 3},
+		{&title, missing, nil, false, int64(5)},
+	}
+	for _, row := range rows {
+		if err := w.WriteRow(row...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if !strings.HasPrefix(out, utf8BOM+"Objetivo;Categoria;") {
+		t.Errorf("início do CSV = %q", out[:min(len(out), 40)])
+	}
+	if !strings.Contains(out, "\r\n") {
+		t.Errorf("as linhas devem terminar em CRLF")
+	}
+
+	r := csv.NewReader(strings.NewReader(strings.TrimPrefix(out, utf8BOM)))
+	r.Comma = ';'
+	got, err := r.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"Objetivo", "Categoria", "Prazo", "Concluído", "Dificuldade"},
+		{"'=HYPERLINK(\"http://x\")", "Carreira", "2026-03-02", "true", "3"},
+		{"Ação; com \"aspas\"", "", "", "false", "5"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("linhas =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestEscapeFormula(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"=1+1", "'=1+1"},
+		{"+55 11 99999-0000", "'+55 11 99999-0000"},
+		{"-2", "'-2"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\tcmd", "'\tcmd"},
+		{"\rcmd", "'\rcmd"},
+		{"Aprender Go", "Aprender Go"},
+		{"1-2", "1-2"},
+		{"a=b", "a=b"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := escapeFormula(tt.in); got != tt.want {
+			t.Errorf("escapeFormula(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	tests := []struct {
+		i    int
+		want string
+	}{
+		{0, "A"},
+		{1, "B"},
+		{25, "Z"},
+		{26, "AA"},
+		{27, "AB"},
+		{51, "AZ"},
+		{52, "BA"},
+		{701, "ZZ"},
+		{702, "AAA"},
+		{16383, "XFD"}, // última coluna do Excel
+	}
+	for _, tt := range tests {
+		if got := columnName(tt.i); got != tt.want {
+			t.Errorf("columnName(%d) = %q, want %q", tt.i, got, tt.want)
+		}
+	}
+}
+
+// xlsxCell é uma célula lida de volta da aba do XLSX
+type xlsxCell struct {
+	Ref    string `xml:"r,attr"`
+	Type   string `xml:"t,attr"`
+	Style  string `xml:"s,attr"`
+	Value  string `xml:"v"`
+	Inline string `xml:"is>t"`
+}
+
+func TestXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewXLSX(&buf, "OKRs & <KRs>")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	saoPaulo := time.FixedZone("UTC-3", -3*60*60)
+	done := true
+	var missing *time.Time
+	rows := [][]any{
+		{"Objetivo", "Prazo"},
+		// A data vale pelo dia do calendário, mesmo perto da meia-noite em outro fuso
+		{"Aprender <Go> & Rust", time.Date(2026, 3, 2, 23, 30, 0, 0, saoPaulo), &done, 3, int64(4), 2.5},
+		{"Época do Unix", time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), nil, missing},
+		{"1º de março de 1900", time.Date(1900, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	rows = append(rows, make([]any, 28))
+	rows[len(rows)-1][27] = "AB"
+	for _, row := range rows {
+		if err := w.WriteRow(row...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("o XLSX deve ser um zip válido: %v", err)
+	}
+	parts := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts[f.Name] = string(data)
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml"} {
+		content, ok := parts[name]
+		if !ok {
+			t.Errorf("parte ausente: %s", name)
+			continue
+		}
+		if err := xml.Unmarshal([]byte(content), new(struct{})); err != nil {
+			t.Errorf("%s não é XML válido: %v", name, err)
+		}
+	}
+
+	var workbook struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := xml.Unmarshal([]byte(parts["xl/workbook.xml"]), &workbook); err != nil {
+		t.Fatal(err)
+	}
+	if len(workbook.Sheets) != 1 || workbook.Sheets[0].Name != "OKRs & <KRs>" {
+		t.Errorf("abas = %+v, want uma aba \"OKRs & <KRs>\"", workbook.Sheets)
+	}
+
+	var sheet struct {
+		Rows []struct {
+			Ref   string     `xml:"r,attr"`
+			Cells []xlsxCell `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := xml.Unmarshal([]byte(parts["xl/worksheets/sheet1.xml"]), &sheet); err != nil {
+		t.Fatal(err)
+	}
+	var got [][]xlsxCell
+	for i, row := range sheet.Rows {
+		if want := strconv.Itoa(i + 1); row.Ref != want {
+			t.Errorf("linha %d com r=%q, want %q", i+1, row.Ref, want)
+		}
+		got = append(got, row.Cells)
+	}
+
+	want := [][]xlsxCell{
+		{{Ref: "A1", Type: "inlineStr", Inline: "Objetivo"}, {Ref: "B1", Type: "inlineStr", Inline: "Prazo"}},
+		{
+			{Ref: "A2", Type: "inlineStr", Inline: "Aprender <Go> & Rust"},
+			{Ref: "B2", Style: "1", Value: "46083"},
+			{Ref: "C2", Type: "b", Value: "1"},
+			{Ref: "D2", Value: "3"},
+			{Ref: "E2", Value: "4"},
+			{Ref: "F2", Value: "2.5"},
+		},
+		{{Ref: "A3", Type: "inlineStr", Inline: "Época do Unix"}, {Ref: "B3", Style: "1", Value: "25569"}},
+		{{Ref: "A4", Type: "inlineStr", Inline: "1º de março de 1900"}, {Ref: "B4", Style: "1", Value: "61"}},
+		{{Ref: "AB5", Type: "inlineStr", Inline: "AB"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("células =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestXLSXUnsupportedType(t *testing.T) {
+	w, err := NewXLSX(io.Discard, "Aba")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteRow(struct{}{}); err == nil {
+		t.Errorf("WriteRow(struct{}{}): want erro de tipo não suportado")
+	}
+}
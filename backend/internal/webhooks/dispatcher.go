@@ -0,0 +1,161 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/jobs"
+	"github.com/conquista-ai/conquista-ai/internal/logging"
+	"github.com/conquista-ai/conquista-ai/internal/metrics"
+	"github.com/conquista-ai/conquista-ai/internal/planning"
+	"github.com/conquista-ai/conquista-ai/internal/repositories"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+const (
+	// Limite de eventos da outbox e de entregas lidos a cada ciclo do dispatcher
+	batchSize = 100
+	// Backoff das entregas que falharam (veja jobs.Backoff)
+	retryBaseDelay = 30 * time.Second
+	retryMaxDelay  = 6 * time.Hour
+	// Trecho da resposta do receptor guardado no log de entregas
+	maxResponseBody = 1024
+)
+
+// Dispatcher distribui os eventos da outbox às assinaturas e entrega os pendentes
+type Dispatcher struct {
+	repo        *repositories.WebhookRepository
+	httpClient  *http.Client
+	clock       planning.Clock
+	maxAttempts int
+}
+
+func NewDispatcher(repo *repositories.WebhookRepository, clock planning.Clock, timeout time.Duration, maxAttempts int) *Dispatcher {
+	return &Dispatcher{
+		repo: repo,
+		httpClient: &http.Client{
+			Timeout:   timeout,
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
+			// Redirecionamentos não são seguidos: o receptor deve responder na URL cadastrada
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		clock:       clock,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Run distribui os novos eventos da outbox e entrega as pendências. Executado
+// periodicamente pelo job de webhooks
+func (d *Dispatcher) Run(ctx context.Context) error {
+	logger := logging.FromContext(ctx)
+
+	created, err := d.repo.DispatchOutbox(ctx, d.clock.Now(), batchSize)
+	if err != nil {
+		return fmt.Errorf("erro ao distribuir eventos da outbox: %w", err)
+	}
+	if created > 0 {
+		logger.Info("entregas de webhook criadas", "deliveries", created)
+	}
+
+	jobs, err := d.repo.DueDeliveries(ctx, d.clock.Now(), batchSize)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar entregas de webhook pendentes: %w", err)
+	}
+
+	for _, job := range jobs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		attempt, outcome := d.attempt(ctx, job)
+		if !attempt.Succeeded {
+			logger.Warn("falha ao entregar webhook",
+				"delivery_id", job.DeliveryID, "event", job.Event.Type, "attempts", job.Attempts+1, "error", attempt.Error)
+		}
+		metrics.WebhookDeliveries.WithLabelValues(job.Event.Type, outcome).Inc()
+
+		if err := d.repo.RecordAttempt(ctx, job.DeliveryID, attempt); err != nil {
+			return fmt.Errorf("erro ao registrar entrega de webhook: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// attempt entrega o job e, em caso de falha, agenda a próxima tentativa enquanto o
+// limite de tentativas não for atingido. Retorna também o resultado para as métricas:
+// succeeded, retry ou failed
+func (d *Dispatcher) attempt(ctx context.Context, job repositories.WebhookJob) (repositories.WebhookAttempt, string) {
+	attempt := d.deliver(ctx, job)
+	if attempt.Succeeded {
+		return attempt, "succeeded"
+	}
+	if attempts := job.Attempts + 1; attempts < d.maxAttempts {
+		next := attempt.At.Add(jobs.Backoff(retryBaseDelay, retryMaxDelay, attempts))
+		attempt.NextAttempt = &next
+		return attempt, "retry"
+	}
+	return attempt, "failed"
+}
+
+// deliver envia o evento assinado. Qualquer resposta fora da faixa 2xx é uma falha
+func (d *Dispatcher) deliver(ctx context.Context, job repositories.WebhookJob) repositories.WebhookAttempt {
+	attempt := repositories.WebhookAttempt{At: d.clock.Now()}
+
+	body, err := json.Marshal(job.Event)
+	if err != nil {
+		attempt.Error = fmt.Sprintf("erro ao serializar evento: %v", err)
+		return attempt
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.URL, bytes.NewReader(body))
+	if err != nil {
+		attempt.Error = fmt.Sprintf("erro ao criar requisição: %v", err)
+		return attempt
+	}
+	timestamp := attempt.At.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "conquista-ai-webhooks")
+	req.Header.Set(HeaderEvent, job.Event.Type)
+	req.Header.Set(HeaderEventID, job.Event.ID)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(job.DeliveryID, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(job.Secret, timestamp, body))
+
+	start := time.Now()
+	resp, err := d.httpClient.Do(req)
+	attempt.Duration = time.Since(start)
+	if err != nil {
+		attempt.Error = fmt.Sprintf("erro ao chamar webhook: %v", err)
+		return attempt
+	}
+	defer resp.Body.Close()
+
+	status := resp.StatusCode
+	attempt.ResponseStatus = &status
+	responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	attempt.ResponseBody = validUTF8(responseBody)
+
+	if status < 200 || status > 299 {
+		attempt.Error = fmt.Sprintf("webhook respondeu com status %d", status)
+		return attempt
+	}
+	attempt.Succeeded = true
+	return attempt
+}
+
+// validUTF8 descarta bytes inválidos (inclusive o final truncado de uma sequência
+// multibyte) e NULs para que o trecho possa ser gravado em uma coluna TEXT
+func validUTF8(b []byte) string {
+	return strings.ReplaceAll(strings.ToValidUTF8(string(b), ""), "\x00", "")
+}
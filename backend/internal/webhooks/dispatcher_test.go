@@ -0,0 +1,141 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conquista-ai/conquista-ai/internal/models"
+	"github.com/conquista-ai/conquista-ai/internal/planning"
+	"github.com/conquista-ai/conquista-ai/internal/repositories"
+)
+
+// receiver é um receptor de webhooks de teste que guarda a última requisição
+type receiver struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.header = req.Header.Clone()
+	r.body, _ = io.ReadAll(req.Body)
+	if r.status == http.StatusFound {
+		w.Header().Set("Location", "/elsewhere")
+	}
+	w.WriteHeader(r.status)
+	io.WriteString(w, strings.Repeat("x", 2*maxResponseBody))
+}
+
+func TestDispatcherAttempt(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	const secret = "whsec_test"
+
+	tests := []struct {
+		name     string
+		status   int
+		attempts int
+		outcome  string
+		next     *time.Time
+	}{
+		{name: "entrega com sucesso", status: http.StatusNoContent, outcome: "succeeded"},
+		{name: "primeira falha", status: http.StatusInternalServerError, attempts: 0, outcome: "retry", next: ptr(now.Add(30 * time.Second))},
+		{name: "segunda falha", status: http.StatusBadGateway, attempts: 1, outcome: "retry", next: ptr(now.Add(time.Minute))},
+		{name: "última tentativa", status: http.StatusInternalServerError, attempts: 2, outcome: "failed"},
+		{name: "redirecionamento não é seguido", status: http.StatusFound, attempts: 0, outcome: "retry", next: ptr(now.Add(30 * time.Second))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recv := &receiver{status: tt.status}
+			server := httptest.NewServer(recv)
+			defer server.Close()
+
+			dispatcher := NewDispatcher(nil, planning.FixedClock{Time: now}, 5*time.Second, 3)
+			job := repositories.WebhookJob{
+				DeliveryID: 42,
+				Attempts:   tt.attempts,
+				URL:        server.URL,
+				Secret:     secret,
+				Event: models.WebhookEvent{
+					ID:         "evt_1",
+					Type:       "okr.created",
+					OccurredAt: now,
+					Data:       json.RawMessage(`{"id":1}`),
+				},
+			}
+
+			attempt, outcome := dispatcher.attempt(context.Background(), job)
+			if outcome != tt.outcome {
+				t.Errorf("outcome = %q, want %q (%s)", outcome, tt.outcome, attempt.Error)
+			}
+			if attempt.Succeeded != (tt.outcome == "succeeded") {
+				t.Errorf("Succeeded = %v", attempt.Succeeded)
+			}
+			if !attempt.At.Equal(now) {
+				t.Errorf("At = %s, want %s", attempt.At, now)
+			}
+			if (attempt.NextAttempt == nil) != (tt.next == nil) ||
+				(tt.next != nil && !attempt.NextAttempt.Equal(*tt.next)) {
+				t.Errorf("NextAttempt = %v, want %v", attempt.NextAttempt, tt.next)
+			}
+			if attempt.ResponseStatus == nil || *attempt.ResponseStatus != tt.status {
+				t.Errorf("ResponseStatus = %v, want %d", attempt.ResponseStatus, tt.status)
+			}
+			if tt.status != http.StatusNoContent && len(attempt.ResponseBody) != maxResponseBody {
+				t.Errorf("ResponseBody tem %d bytes, want %d", len(attempt.ResponseBody), maxResponseBody)
+			}
+
+			// Cabeçalhos e assinatura recebidos
+			header := recv.header
+			if got := header.Get(HeaderEvent); got != "okr.created" {
+				t.Errorf("%s = %q", HeaderEvent, got)
+			}
+			if got := header.Get(HeaderEventID); got != "evt_1" {
+				t.Errorf("%s = %q", HeaderEventID, got)
+			}
+			if got := header.Get(HeaderDelivery); got != "42" {
+				t.Errorf("%s = %q", HeaderDelivery, got)
+			}
+			timestamp, err := strconv.ParseInt(header.Get(HeaderTimestamp), 10, 64)
+			if err != nil || timestamp != now.Unix() {
+				t.Errorf("%s = %q, want %d", HeaderTimestamp, header.Get(HeaderTimestamp), now.Unix())
+			}
+			if !Verify(secret, timestamp, recv.body, header.Get(HeaderSignature)) {
+				t.Errorf("%s = %q não confere com o corpo recebido", HeaderSignature, header.Get(HeaderSignature))
+			}
+
+			var event models.WebhookEvent
+			if err := json.Unmarshal(recv.body, &event); err != nil || event.ID != "evt_1" {
+				t.Errorf("corpo recebido = %s", recv.body)
+			}
+		})
+	}
+}
+
+func TestDispatcherAttemptConnectionError(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	dispatcher := NewDispatcher(nil, planning.FixedClock{Time: now}, time.Second, 3)
+	attempt, outcome := dispatcher.attempt(context.Background(), repositories.WebhookJob{
+		URL:   url,
+		Event: models.WebhookEvent{ID: "evt_1", Type: "okr.created"},
+	})
+	if outcome != "retry" || attempt.ResponseStatus != nil || attempt.Error == "" {
+		t.Errorf("attempt = (%q, status %v, erro %q), want retry sem status e com erro", outcome, attempt.ResponseStatus, attempt.Error)
+	}
+	if want := now.Add(retryBaseDelay); attempt.NextAttempt == nil || !attempt.NextAttempt.Equal(want) {
+		t.Errorf("NextAttempt = %v, want %s", attempt.NextAttempt, want)
+	}
+}
+
+func ptr(t time.Time) *time.Time { return &t }
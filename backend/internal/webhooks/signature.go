@@ -0,0 +1,45 @@
+// Package webhooks entrega os eventos de domínio registrados na outbox aos webhooks de
+// integração, com assinatura HMAC-SHA256 e novas tentativas com espera exponencial.
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// Cabeçalhos enviados em cada entrega
+const (
+	HeaderEvent     = "X-Conquista-Event"
+	HeaderEventID   = "X-Conquista-Event-Id"
+	HeaderDelivery  = "X-Conquista-Delivery"
+	HeaderTimestamp = "X-Conquista-Timestamp"
+	HeaderSignature = "X-Conquista-Signature"
+)
+
+// Sign calcula a assinatura enviada em X-Conquista-Signature: HMAC-SHA256, com o
+// segredo da assinatura, de "<timestamp>.<corpo>", no formato "sha256=<hex>". Incluir
+// o timestamp permite ao receptor recusar reenvios antigos do mesmo corpo
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify confere uma assinatura gerada por Sign em tempo constante
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// NewSecret gera um segredo aleatório para uma nova assinatura
+func NewSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,64 @@
+package webhooks
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"id":"evt_1"}`)
+
+	// HMAC-SHA256 de "1700000000.{"id":"evt_1"}" com o segredo whsec_test
+	want := "sha256=c89214b5b5da833daed6f0b8c5bb6bd58cea9022bd80ccc78230f3942d632925"
+	if got := Sign("whsec_test", 1700000000, body); got != want {
+		t.Errorf("Sign = %q, want %q", got, want)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	const secret = "whsec_test"
+	const timestamp = int64(1700000000)
+	body := []byte(`{"id":"evt_1"}`)
+	signature := Sign(secret, timestamp, body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      []byte
+		signature string
+		want      bool
+	}{
+		{"assinatura válida", secret, timestamp, body, signature, true},
+		{"outro segredo", "whsec_other", timestamp, body, signature, false},
+		{"outro timestamp", secret, timestamp + 1, body, signature, false},
+		{"corpo alterado", secret, timestamp, []byte(`{"id":"evt_2"}`), signature, false},
+		{"assinatura sem prefixo", secret, timestamp, body, strings.TrimPrefix(signature, "sha256="), false},
+		{"assinatura vazia", secret, timestamp, body, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Verify(tt.secret, tt.timestamp, tt.body, tt.signature); got != tt.want {
+				t.Errorf("Verify = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSecret(t *testing.T) {
+	a, err := NewSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(a, "whsec_") || len(a) != len("whsec_")+64 {
+		t.Errorf("NewSecret = %q, want whsec_ seguido de 64 dígitos hexadecimais", a)
+	}
+	if a == b {
+		t.Error("NewSecret gerou o mesmo segredo duas vezes")
+	}
+}
@@ -0,0 +1,5 @@
+-- Índices para paginação, filtros e ordenação das listagens de OKRs e Key Results
+CREATE INDEX IF NOT EXISTS idx_okrs_created_at_id ON okrs(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_okrs_completion_date ON okrs(completion_date);
+CREATE INDEX IF NOT EXISTS idx_key_results_expected_completion_date ON key_results(expected_completion_date);
+CREATE INDEX IF NOT EXISTS idx_key_results_created_at_id ON key_results(created_at, id);
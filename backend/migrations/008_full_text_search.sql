@@ -0,0 +1,42 @@
+-- Busca textual (full-text search) em português, ignorando acentos
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'portuguese_unaccent') THEN
+        CREATE TEXT SEARCH CONFIGURATION public.portuguese_unaccent (COPY = pg_catalog.portuguese);
+        ALTER TEXT SEARCH CONFIGURATION public.portuguese_unaccent
+            ALTER MAPPING FOR hword, hword_part, word WITH unaccent, portuguese_stem;
+    END IF;
+END
+$$;
+
+-- Colunas tsvector geradas a partir dos campos pesquisáveis
+ALTER TABLE okrs ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('public.portuguese_unaccent', coalesce(objective, ''))) STORED;
+
+ALTER TABLE key_results ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('public.portuguese_unaccent', coalesce(title, ''))) STORED;
+
+ALTER TABLE roadmap_items ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('public.portuguese_unaccent', coalesce(title, ''))) STORED;
+
+ALTER TABLE educational_trail_steps ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('public.portuguese_unaccent', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('public.portuguese_unaccent', coalesce(description, '')), 'B')
+    ) STORED;
+
+ALTER TABLE educational_trail_resources ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('public.portuguese_unaccent', coalesce(title, ''))) STORED;
+
+ALTER TABLE educational_resources ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('public.portuguese_unaccent', coalesce(title, ''))) STORED;
+
+-- Índices GIN para as buscas
+CREATE INDEX IF NOT EXISTS idx_okrs_search_vector ON okrs USING GIN(search_vector);
+CREATE INDEX IF NOT EXISTS idx_key_results_search_vector ON key_results USING GIN(search_vector);
+CREATE INDEX IF NOT EXISTS idx_roadmap_items_search_vector ON roadmap_items USING GIN(search_vector);
+CREATE INDEX IF NOT EXISTS idx_educational_trail_steps_search_vector ON educational_trail_steps USING GIN(search_vector);
+CREATE INDEX IF NOT EXISTS idx_educational_trail_resources_search_vector ON educational_trail_resources USING GIN(search_vector);
+CREATE INDEX IF NOT EXISTS idx_educational_resources_search_vector ON educational_resources USING GIN(search_vector);
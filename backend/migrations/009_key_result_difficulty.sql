@@ -0,0 +1,12 @@
+-- Dificuldade do Key Result (1 a 5), usada como peso na distribuição de prazos
+ALTER TABLE key_results ADD COLUMN IF NOT EXISTS difficulty SMALLINT NOT NULL DEFAULT 3;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'key_results_difficulty_check'
+    ) THEN
+        ALTER TABLE key_results
+            ADD CONSTRAINT key_results_difficulty_check CHECK (difficulty BETWEEN 1 AND 5);
+    END IF;
+END $$;
@@ -0,0 +1,3 @@
+-- Indica se a data esperada do Key Result foi calculada pelo planejamento (e pode ser
+-- recalculada) ou definida manualmente pelo usuário
+ALTER TABLE key_results ADD COLUMN IF NOT EXISTS due_date_computed BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,30 @@
+-- Disponibilidade de estudo do usuário (linha única). Os dias da semana seguem
+-- time.Weekday: 0 = domingo ... 6 = sábado. O padrão (todos os dias) mantém a
+-- equivalência anterior entre "dia N" da trilha e N dias a partir do início
+CREATE TABLE IF NOT EXISTS study_settings (
+    id INTEGER PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    study_weekdays INTEGER[] NOT NULL DEFAULT '{0,1,2,3,4,5,6}',
+    hours_per_day NUMERIC(4,2) NOT NULL DEFAULT 2 CHECK (hours_per_day > 0 AND hours_per_day <= 24),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO study_settings (id) VALUES (1) ON CONFLICT (id) DO NOTHING;
+
+-- Feriados (um único dia) e bloqueios (intervalos, ex.: férias) em que não há estudo
+CREATE TABLE IF NOT EXISTS study_days_off (
+    id SERIAL PRIMARY KEY,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('holiday', 'blackout')),
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_date >= start_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_study_days_off_dates ON study_days_off(start_date, end_date);
+
+-- Datas reais da trilha: início do planejamento e data agendada de cada etapa
+ALTER TABLE educational_trails ADD COLUMN IF NOT EXISTS start_date DATE;
+ALTER TABLE educational_trail_steps ADD COLUMN IF NOT EXISTS scheduled_date DATE;
+
+CREATE INDEX IF NOT EXISTS idx_educational_trail_steps_scheduled_date ON educational_trail_steps(scheduled_date);
@@ -0,0 +1,8 @@
+-- Tokens secretos do feed iCalendar (GET /api/v1/calendar.ics?token=...). Apenas o
+-- hash SHA-256 é armazenado; gerar um novo token revoga os anteriores
+CREATE TABLE IF NOT EXISTS calendar_feed_tokens (
+    id SERIAL PRIMARY KEY,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP
+);
@@ -0,0 +1,49 @@
+-- Preferências de notificação do usuário (linha única): canais, tipos de lembrete,
+-- horário de silêncio (HH:MM, pode cruzar a meia-noite) e dia do check-in semanal
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    id INTEGER PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    inbox_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    email_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    email_address TEXT NOT NULL DEFAULT '',
+    webhook_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    webhook_url TEXT NOT NULL DEFAULT '',
+    kinds TEXT[] NOT NULL DEFAULT '{trail_today,key_result_due_soon,overdue,weekly_checkin}',
+    quiet_hours_start VARCHAR(5),
+    quiet_hours_end VARCHAR(5),
+    weekly_checkin_weekday INTEGER NOT NULL DEFAULT 1 CHECK (weekly_checkin_weekday BETWEEN 0 AND 6),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO notification_preferences (id) VALUES (1) ON CONFLICT (id) DO NOTHING;
+
+-- Fila de entrega por canal. A chave de deduplicação garante que cada lembrete seja
+-- gerado uma única vez por canal, mesmo com o job rodando várias vezes ao dia
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+    id SERIAL PRIMARY KEY,
+    dedup_key VARCHAR(255) NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+    kind VARCHAR(50) NOT NULL,
+    title TEXT NOT NULL,
+    body TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP,
+    UNIQUE (dedup_key, channel)
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_deliveries_pending ON notification_deliveries(next_attempt_at) WHERE status = 'pending';
+
+-- Caixa de entrada do aplicativo
+CREATE TABLE IF NOT EXISTS notifications (
+    id SERIAL PRIMARY KEY,
+    kind VARCHAR(50) NOT NULL,
+    title TEXT NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    read_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications(created_at DESC) WHERE read_at IS NULL;
@@ -0,0 +1,24 @@
+-- Momento em que a atividade foi concluída (base das sequências de estudo). Atividades
+-- já concluídas recebem a data da última atualização
+ALTER TABLE educational_trail_activities ADD COLUMN IF NOT EXISTS completed_at TIMESTAMP;
+
+UPDATE educational_trail_activities SET completed_at = updated_at
+WHERE completed AND completed_at IS NULL;
+
+-- Sessões de estudo de uma atividade: cronômetro (iniciar/parar) ou lançamento manual.
+-- Uma sessão com ended_at nulo é um cronômetro em andamento
+CREATE TABLE IF NOT EXISTS study_sessions (
+    id SERIAL PRIMARY KEY,
+    activity_id INTEGER NOT NULL REFERENCES educational_trail_activities(id) ON DELETE CASCADE,
+    source VARCHAR(10) NOT NULL CHECK (source IN ('timer', 'manual')),
+    started_at TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP,
+    minutes INTEGER NOT NULL DEFAULT 0 CHECK (minutes >= 0),
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Apenas um cronômetro em andamento por vez
+CREATE UNIQUE INDEX IF NOT EXISTS idx_study_sessions_running ON study_sessions ((TRUE)) WHERE ended_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_study_sessions_activity_id ON study_sessions(activity_id);
+CREATE INDEX IF NOT EXISTS idx_study_sessions_started_at ON study_sessions(started_at);
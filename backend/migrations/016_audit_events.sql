@@ -0,0 +1,16 @@
+-- Log de auditoria: uma linha por criação, alteração ou exclusão de categorias, OKRs,
+-- Key Results, itens de roadmap, recursos educacionais e atividades de trilha. changes
+-- guarda apenas os campos alterados, no formato {"campo": {"before": ..., "after": ...}}
+CREATE TABLE IF NOT EXISTS audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    occurred_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    actor VARCHAR(100) NOT NULL,
+    request_id VARCHAR(128),
+    entity VARCHAR(30) NOT NULL,
+    entity_id BIGINT NOT NULL,
+    action VARCHAR(10) NOT NULL CHECK (action IN ('create', 'update', 'delete')),
+    changes JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_entity ON audit_events(entity, entity_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_occurred_at ON audit_events(occurred_at);
@@ -0,0 +1,16 @@
+-- Exclusão lógica de OKRs, Key Results e roadmaps. Ao excluir um item, os descendentes
+-- ainda ativos recebem o mesmo deleted_at; a restauração usa esse valor para trazer de
+-- volta exatamente a subárvore excluída junto com o item
+ALTER TABLE okrs ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE key_results ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE roadmaps ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+-- Listagem da lixeira e job de expurgo
+CREATE INDEX IF NOT EXISTS idx_okrs_deleted_at ON okrs(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_key_results_deleted_at ON key_results(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_roadmaps_deleted_at ON roadmaps(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Restaurações são registradas no log de auditoria
+ALTER TABLE audit_events DROP CONSTRAINT IF EXISTS audit_events_action_check;
+ALTER TABLE audit_events ADD CONSTRAINT audit_events_action_check
+    CHECK (action IN ('create', 'update', 'delete', 'restore'));
@@ -0,0 +1,5 @@
+-- Arquivamento de OKRs. Um OKR arquivado sai das listagens padrão junto com seus Key
+-- Results e roadmaps, mas continua disponível em relatórios e na busca
+ALTER TABLE okrs ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_okrs_archived_at ON okrs(archived_at) WHERE archived_at IS NOT NULL;
@@ -0,0 +1,23 @@
+-- Ciclo de vida dos OKRs: draft → active → completed/abandoned, com pausa em on_hold.
+-- OKRs existentes já estavam em uso e começam como active
+ALTER TABLE okrs ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active';
+ALTER TABLE okrs ADD COLUMN IF NOT EXISTS status_reason TEXT;
+ALTER TABLE okrs ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMP;
+
+ALTER TABLE okrs DROP CONSTRAINT IF EXISTS okrs_status_check;
+ALTER TABLE okrs ADD CONSTRAINT okrs_status_check
+    CHECK (status IN ('draft', 'active', 'on_hold', 'completed', 'abandoned'));
+
+CREATE INDEX IF NOT EXISTS idx_okrs_status ON okrs(status);
+
+-- Histórico das mudanças de status. from_status é nulo no status inicial
+CREATE TABLE IF NOT EXISTS okr_status_changes (
+    id BIGSERIAL PRIMARY KEY,
+    okr_id INTEGER NOT NULL REFERENCES okrs(id) ON DELETE CASCADE,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    reason TEXT,
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_okr_status_changes_okr_id ON okr_status_changes(okr_id, changed_at);
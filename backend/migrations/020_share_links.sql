@@ -0,0 +1,16 @@
+-- Links públicos de leitura do roadmap de um Key Result e de suas trilhas
+-- (GET /public/share/:token). Como nos tokens do calendário, apenas o hash SHA-256 do
+-- token é armazenado. Links revogados ou expirados deixam de funcionar, mas continuam
+-- listados com a contagem de visualizações
+CREATE TABLE IF NOT EXISTS share_links (
+    id SERIAL PRIMARY KEY,
+    key_result_id INTEGER NOT NULL REFERENCES key_results(id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    view_count INTEGER NOT NULL DEFAULT 0,
+    last_viewed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_share_links_key_result_id ON share_links(key_result_id);
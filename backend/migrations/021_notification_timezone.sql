@@ -0,0 +1,3 @@
+-- Fuso horário do usuário (nome IANA), usado para o horário de silêncio, a data dos
+-- lembretes do dia e o dia do check-in semanal
+ALTER TABLE notification_preferences ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'America/Sao_Paulo';
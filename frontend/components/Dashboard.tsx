@@ -2,12 +2,11 @@
 
 import { useEffect, useState } from 'react';
 import { useRouter } from 'next/navigation';
-import { OKR, Category, KeyResult } from '@/types';
-import { okrsAPI, categoriesAPI, keyResultsAPI } from '@/lib/api';
+import { OKR, Category, Dashboard as DashboardData } from '@/types';
+import { okrsAPI, categoriesAPI, dashboardAPI } from '@/lib/api';
 import OKRCard from './OKRCard';
 import StatCard from './StatCard';
 import CategoryTooltip from './CategoryTooltip';
-import { calculateOKRProgress } from '@/lib/utils';
 
 export default function Dashboard() {
   const router = useRouter();
@@ -15,7 +14,7 @@ export default function Dashboard() {
   const [categories, setCategories] = useState<Category[]>([]);
   const [selectedCategory, setSelectedCategory] = useState<number | null>(null);
   const [loading, setLoading] = useState(true);
-  const [keyResultsMap, setKeyResultsMap] = useState<Record<number, KeyResult[]>>({});
+  const [dashboard, setDashboard] = useState<DashboardData | null>(null);
 
   useEffect(() => {
     loadData();
@@ -27,30 +26,15 @@ export default function Dashboard() {
 
   const loadData = async () => {
     try {
-      const [okrsData, categoriesData] = await Promise.all([
+      // Estatísticas e progresso dos OKRs vêm agregados do backend em uma única chamada
+      const [okrsData, categoriesData, dashboardData] = await Promise.all([
         okrsAPI.getAll(),
         categoriesAPI.getAll(),
+        dashboardAPI.get(),
       ]);
       setOKRs(okrsData);
       setCategories(categoriesData);
-      
-      // Carregar Key Results para cada OKR
-      const keyResultsPromises = okrsData.map(async (okr) => {
-        try {
-          const keyResults = await keyResultsAPI.getByOKRId(okr.id);
-          return { okrId: okr.id, keyResults };
-        } catch (error) {
-          console.error(`Erro ao carregar Key Results do OKR ${okr.id}:`, error);
-          return { okrId: okr.id, keyResults: [] };
-        }
-      });
-      
-      const keyResultsData = await Promise.all(keyResultsPromises);
-      const map: Record<number, KeyResult[]> = {};
-      keyResultsData.forEach(({ okrId, keyResults }) => {
-        map[okrId] = keyResults;
-      });
-      setKeyResultsMap(map);
+      setDashboard(dashboardData);
     } catch (error) {
       console.error('Erro ao carregar dados:', error);
     } finally {
@@ -64,23 +48,6 @@ export default function Dashboard() {
         ? await okrsAPI.getAll(selectedCategory)
         : await okrsAPI.getAll();
       setOKRs(data);
-      
-      // Carregar Key Results para os novos OKRs
-      const keyResultsPromises = data.map(async (okr) => {
-        try {
-          const keyResults = await keyResultsAPI.getByOKRId(okr.id);
-          return { okrId: okr.id, keyResults };
-        } catch (error) {
-          return { okrId: okr.id, keyResults: [] };
-        }
-      });
-      
-      const keyResultsData = await Promise.all(keyResultsPromises);
-      const map: Record<number, KeyResult[]> = {};
-      keyResultsData.forEach(({ okrId, keyResults }) => {
-        map[okrId] = keyResults;
-      });
-      setKeyResultsMap(map);
     } catch (error) {
       console.error('Erro ao carregar OKRs:', error);
     }
@@ -107,26 +74,15 @@ export default function Dashboard() {
     );
   }
 
-  const totalOKRs = okrs.length;
-  const completedOKRs = okrs.filter((okr) => {
-    const keyResults = keyResultsMap[okr.id] || [];
-    return calculateOKRProgress(keyResults) === 100;
-  }).length;
-  
-  const inProgressOKRs = okrs.filter((okr) => {
-    const keyResults = keyResultsMap[okr.id] || [];
-    const progress = calculateOKRProgress(keyResults);
-    return progress > 0 && progress < 100;
-  }).length;
-  
-  const averageProgress = okrs.length > 0
-    ? Math.round(
-        okrs.reduce((sum, okr) => {
-          const keyResults = keyResultsMap[okr.id] || [];
-          return sum + calculateOKRProgress(keyResults);
-        }, 0) / okrs.length
-      )
-    : 0;
+  const totalOKRs = dashboard?.totals.okrs ?? okrs.length;
+  const completedOKRs = dashboard?.totals.completed_okrs ?? 0;
+  const inProgressOKRs = dashboard?.totals.in_progress_okrs ?? 0;
+  const averageProgress = dashboard?.totals.average_progress ?? 0;
+
+  const progressMap: Record<number, number> = {};
+  dashboard?.okrs.forEach(({ okr_id, progress }) => {
+    progressMap[okr_id] = progress;
+  });
 
   return (
     <div className="min-h-screen bg-gradient-to-br from-gray-50 via-blue-50/30 to-gray-50 p-8">
@@ -251,8 +207,7 @@ export default function Dashboard() {
           ) : (
             <div className="grid grid-cols-1 md:grid-cols-2 lg:grid-cols-3 gap-6">
               {okrs.map((okr) => {
-                const keyResults = keyResultsMap[okr.id] || [];
-                const progress = calculateOKRProgress(keyResults);
+                const progress = progressMap[okr.id] ?? 0;
                 return (
                   <OKRCard
                     key={okr.id}
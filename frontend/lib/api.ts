@@ -11,6 +11,43 @@ import {
   UpdateOKRRequest,
   CreateKeyResultRequest,
   UpdateKeyResultRequest,
+  ScheduleRequest,
+  ScheduleResult,
+  StudySettings,
+  StudyDayOff,
+  UpdateStudySettingsRequest,
+  CreateStudyDayOffRequest,
+  CalendarFeedToken,
+  RiskReport,
+  NotificationInbox,
+  NotificationPreferences,
+  UpdateNotificationPreferencesRequest,
+  NotificationTestResult,
+  WebhookSubscription,
+  CreateWebhookSubscriptionRequest,
+  UpdateWebhookSubscriptionRequest,
+  WebhookDelivery,
+  WebhookDeliveryStatus,
+  StudySession,
+  CreateStudySessionRequest,
+  StudyStats,
+  Dashboard,
+  AuditEvent,
+  AuditEntity,
+  AuditAction,
+  TrashItem,
+  ChangeOKRStatusRequest,
+  OKRStatusChange,
+  Backup,
+  ImportStrategy,
+  ImportResult,
+  KeyResultExportParams,
+  OKRImportResult,
+  DocumentFormat,
+  ShareLink,
+  CreateShareLinkRequest,
+  SharedRoadmap,
+  Page,
 } from '@/types';
 
 // @ts-ignore - process.env é disponibilizado pelo Next.js
@@ -27,10 +64,12 @@ async function fetchAPI<T>(endpoint: string, options?: RequestInit): Promise<T>
     });
 
     if (!response.ok) {
-      const error = await response.json().catch(() => ({ error: 'Erro desconhecido' }));
-      const errorMessage = error.error || `HTTP error! status: ${response.status}`;
+      // Erros da API seguem o formato application/problem+json (RFC 7807)
+      const error = await response.json().catch(() => ({ detail: 'Erro desconhecido' }));
+      const errorMessage = error.detail || error.title || error.error || `HTTP error! status: ${response.status}`;
       const apiError = new Error(errorMessage) as any;
       apiError.status = response.status;
+      apiError.fieldErrors = error.errors;
       throw apiError;
     }
 
@@ -47,6 +86,29 @@ async function fetchAPI<T>(endpoint: string, options?: RequestInit): Promise<T>
   }
 }
 
+// Listagens paginadas retornam um envelope; o front-end segue o cursor até a última página,
+// buscando cada uma com o limite máximo
+const LIST_LIMIT = 100;
+
+async function fetchPage<T>(endpoint: string, params: Record<string, string | number | undefined> = {}): Promise<T[]> {
+  const query = new URLSearchParams({ limit: String(LIST_LIMIT) });
+  Object.entries(params).forEach(([key, value]) => {
+    if (value !== undefined && value !== '') {
+      query.set(key, String(value));
+    }
+  });
+
+  const items: T[] = [];
+  for (;;) {
+    const page = await fetchAPI<Page<T>>(`${endpoint}?${query.toString()}`);
+    items.push(...page.items);
+    if (!page.next_cursor) {
+      return items;
+    }
+    query.set('cursor', page.next_cursor);
+  }
+}
+
 // Categories
 export const categoriesAPI = {
   getAll: (): Promise<Category[]> => fetchAPI<Category[]>('/categories'),
@@ -61,10 +123,8 @@ export const categoriesAPI = {
 
 // OKRs
 export const okrsAPI = {
-  getAll: (categoryId?: number): Promise<OKR[]> => {
-    const url = categoryId ? `/okrs?category_id=${categoryId}` : '/okrs';
-    return fetchAPI<OKR[]>(url);
-  },
+  getAll: (categoryId?: number): Promise<OKR[]> =>
+    fetchPage<OKR>('/okrs', { category_id: categoryId }),
   getById: (id: number): Promise<OKR> => fetchAPI<OKR>(`/okrs/${id}`),
   create: (data: CreateOKRRequest): Promise<OKR> =>
     fetchAPI<OKR>('/okrs', { method: 'POST', body: JSON.stringify(data) }),
@@ -74,12 +134,47 @@ export const okrsAPI = {
     fetchAPI<void>(`/okrs/${id}`, { method: 'DELETE' }),
   generateKeyResults: (id: number): Promise<void> =>
     fetchAPI<void>(`/okrs/${id}/generate-key-results`, { method: 'POST' }),
+  schedule: (id: number, data: ScheduleRequest = {}): Promise<ScheduleResult> =>
+    fetchAPI<ScheduleResult>(`/okrs/${id}/schedule`, { method: 'POST', body: JSON.stringify(data) }),
+  getArchived: (categoryId?: number): Promise<OKR[]> =>
+    fetchPage<OKR>('/okrs', { category_id: categoryId, archived: 'true' }),
+  archive: (id: number): Promise<OKR> =>
+    fetchAPI<OKR>(`/okrs/${id}/archive`, { method: 'POST' }),
+  unarchive: (id: number): Promise<OKR> =>
+    fetchAPI<OKR>(`/okrs/${id}/unarchive`, { method: 'POST' }),
+  changeStatus: (id: number, data: ChangeOKRStatusRequest): Promise<OKR> =>
+    fetchAPI<OKR>(`/okrs/${id}/status`, { method: 'POST', body: JSON.stringify(data) }),
+  getStatusHistory: (id: number): Promise<OKRStatusChange[]> =>
+    fetchAPI<OKRStatusChange[]>(`/okrs/${id}/status-history`),
+  // URL de download da planilha de Key Results (uma linha por Key Result)
+  exportUrl: (format: 'csv' | 'xlsx', params: KeyResultExportParams = {}): string => {
+    const query = new URLSearchParams();
+    Object.entries(params).forEach(([key, value]) => {
+      if (value !== undefined && value !== '') {
+        query.set(key, String(value));
+      }
+    });
+    const qs = query.toString();
+    return `${API_URL}/okrs/export.${format}${qs ? `?${qs}` : ''}`;
+  },
+  // Importa uma planilha CSV (objective, category, completion_date, key_result, ...)
+  importCSV: (file: Blob, params?: { dryRun?: boolean; generateKeyResults?: boolean }): Promise<OKRImportResult> => {
+    const query = new URLSearchParams();
+    if (params?.dryRun) query.set('dry_run', 'true');
+    if (params?.generateKeyResults) query.set('generate_key_results', 'true');
+    const qs = query.toString();
+    return fetchAPI<OKRImportResult>(`/import/okrs.csv${qs ? `?${qs}` : ''}`, {
+      method: 'POST',
+      body: file,
+      headers: { 'Content-Type': 'text/csv' },
+    });
+  },
 };
 
 // Key Results
 export const keyResultsAPI = {
   getAll: (): Promise<KeyResultWithOKR[]> =>
-    fetchAPI<KeyResultWithOKR[]>('/key-results'),
+    fetchPage<KeyResultWithOKR>('/key-results'),
   getByOKRId: (okrId: number): Promise<KeyResult[]> =>
     fetchAPI<KeyResult[]>(`/okrs/${okrId}/key-results`),
   create: (data: CreateKeyResultRequest): Promise<KeyResult> =>
@@ -124,10 +219,180 @@ export const roadmapsAPI = {
     fetchAPI<EducationalTrail>(`/roadmap-items/${roadmapItemId}/educational-trail`),
   deleteEducationalTrail: (roadmapItemId: number): Promise<void> =>
     fetchAPI<void>(`/roadmap-items/${roadmapItemId}/educational-trail`, { method: 'DELETE' }),
+  replanEducationalTrail: (roadmapItemId: number, startDate?: string): Promise<EducationalTrail> =>
+    fetchAPI<EducationalTrail>(`/roadmap-items/${roadmapItemId}/educational-trail/replan`, {
+      method: 'POST',
+      body: JSON.stringify(startDate ? { start_date: startDate } : {}),
+    }),
   updateTrailActivity: (activityId: number, completed: boolean): Promise<void> =>
     fetchAPI<void>(`/trail-activities/${activityId}`, {
       method: 'PUT',
       body: JSON.stringify({ completed }),
     }),
+  // URLs de download para impressão ou compartilhamento
+  roadmapExportUrl: (keyResultId: number, format: DocumentFormat = 'md'): string =>
+    `${API_URL}/key-results/${keyResultId}/roadmap/export?format=${format}`,
+  educationalTrailExportUrl: (roadmapItemId: number, format: DocumentFormat = 'md'): string =>
+    `${API_URL}/roadmap-items/${roadmapItemId}/educational-trail/export?format=${format}`,
+};
+
+export const studySettingsAPI = {
+  get: (): Promise<StudySettings> => fetchAPI<StudySettings>('/settings/study'),
+  update: (data: UpdateStudySettingsRequest): Promise<StudySettings> =>
+    fetchAPI<StudySettings>('/settings/study', {
+      method: 'PUT',
+      body: JSON.stringify(data),
+    }),
+  createDayOff: (data: CreateStudyDayOffRequest): Promise<StudyDayOff> =>
+    fetchAPI<StudyDayOff>('/settings/study/days-off', {
+      method: 'POST',
+      body: JSON.stringify(data),
+    }),
+  deleteDayOff: (id: number): Promise<void> =>
+    fetchAPI<void>(`/settings/study/days-off/${id}`, { method: 'DELETE' }),
+};
+
+export const calendarAPI = {
+  // Gera um novo token do feed iCalendar (revoga o anterior)
+  rotateToken: (): Promise<CalendarFeedToken> =>
+    fetchAPI<CalendarFeedToken>('/calendar/token', { method: 'POST' }),
+};
+
+export const reportsAPI = {
+  risk: (): Promise<RiskReport> => fetchAPI<RiskReport>('/reports/risk'),
+};
+
+export const notificationsAPI = {
+  inbox: (): Promise<NotificationInbox> => fetchAPI<NotificationInbox>('/notifications'),
+  markRead: (id: number): Promise<void> =>
+    fetchAPI<void>(`/notifications/${id}/read`, { method: 'POST' }),
+  markAllRead: (): Promise<void> =>
+    fetchAPI<void>('/notifications/read-all', { method: 'POST' }),
+  // Envia um lembrete de teste por todos os canais ativados
+  sendTest: (): Promise<NotificationTestResult[]> =>
+    fetchAPI<NotificationTestResult[]>('/notifications/test', { method: 'POST' }),
+  getPreferences: (): Promise<NotificationPreferences> =>
+    fetchAPI<NotificationPreferences>('/settings/notifications'),
+  updatePreferences: (data: UpdateNotificationPreferencesRequest): Promise<NotificationPreferences> =>
+    fetchAPI<NotificationPreferences>('/settings/notifications', {
+      method: 'PUT',
+      body: JSON.stringify(data),
+    }),
 };
 
+export const webhooksAPI = {
+  list: (): Promise<WebhookSubscription[]> => fetchAPI<WebhookSubscription[]>('/webhooks'),
+  get: (id: number): Promise<WebhookSubscription> => fetchAPI<WebhookSubscription>(`/webhooks/${id}`),
+  // O segredo só é retornado na criação e na rotação
+  create: (data: CreateWebhookSubscriptionRequest): Promise<WebhookSubscription> =>
+    fetchAPI<WebhookSubscription>('/webhooks', {
+      method: 'POST',
+      body: JSON.stringify(data),
+    }),
+  update: (id: number, data: UpdateWebhookSubscriptionRequest): Promise<WebhookSubscription> =>
+    fetchAPI<WebhookSubscription>(`/webhooks/${id}`, {
+      method: 'PUT',
+      body: JSON.stringify(data),
+    }),
+  delete: (id: number): Promise<void> =>
+    fetchAPI<void>(`/webhooks/${id}`, { method: 'DELETE' }),
+  rotateSecret: (id: number): Promise<WebhookSubscription> =>
+    fetchAPI<WebhookSubscription>(`/webhooks/${id}/rotate-secret`, { method: 'POST' }),
+  deliveries: (id: number, params?: { status?: WebhookDeliveryStatus; limit?: number }): Promise<WebhookDelivery[]> => {
+    const query = new URLSearchParams();
+    if (params?.status) query.set('status', params.status);
+    if (params?.limit) query.set('limit', String(params.limit));
+    const qs = query.toString();
+    return fetchAPI<WebhookDelivery[]>(`/webhooks/${id}/deliveries${qs ? `?${qs}` : ''}`);
+  },
+  redeliver: (id: number, deliveryId: number): Promise<WebhookDelivery> =>
+    fetchAPI<WebhookDelivery>(`/webhooks/${id}/deliveries/${deliveryId}/redeliver`, { method: 'POST' }),
+};
+
+export const studyAPI = {
+  startTimer: (activityId: number): Promise<StudySession> =>
+    fetchAPI<StudySession>(`/trail-activities/${activityId}/timer/start`, { method: 'POST' }),
+  stopTimer: (activityId: number): Promise<StudySession> =>
+    fetchAPI<StudySession>(`/trail-activities/${activityId}/timer/stop`, { method: 'POST' }),
+  listSessions: (activityId: number): Promise<StudySession[]> =>
+    fetchAPI<StudySession[]>(`/trail-activities/${activityId}/sessions`),
+  createSession: (activityId: number, data: CreateStudySessionRequest): Promise<StudySession> =>
+    fetchAPI<StudySession>(`/trail-activities/${activityId}/sessions`, {
+      method: 'POST',
+      body: JSON.stringify(data),
+    }),
+  deleteSession: (id: number): Promise<void> =>
+    fetchAPI<void>(`/study-sessions/${id}`, { method: 'DELETE' }),
+  stats: (weeks?: number): Promise<StudyStats> =>
+    fetchAPI<StudyStats>(`/stats/study${weeks ? `?weeks=${weeks}` : ''}`),
+};
+
+export const dashboardAPI = {
+  get: (): Promise<Dashboard> => fetchAPI<Dashboard>('/dashboard'),
+};
+
+export const activityAPI = {
+  list: (params?: {
+    entity?: AuditEntity;
+    id?: number;
+    action?: AuditAction;
+    limit?: number;
+    cursor?: string;
+  }): Promise<Page<AuditEvent>> => {
+    const query = new URLSearchParams();
+    if (params?.entity) query.set('entity', params.entity);
+    if (params?.id) query.set('id', String(params.id));
+    if (params?.action) query.set('action', params.action);
+    if (params?.limit) query.set('limit', String(params.limit));
+    if (params?.cursor) query.set('cursor', params.cursor);
+    const qs = query.toString();
+    return fetchAPI<Page<AuditEvent>>(`/activity${qs ? `?${qs}` : ''}`);
+  },
+};
+
+export const trashAPI = {
+  list: (): Promise<TrashItem[]> => fetchAPI<TrashItem[]>('/trash'),
+
+  restoreOKR: (id: number): Promise<OKR> =>
+    fetchAPI<OKR>(`/okrs/${id}/restore`, { method: 'POST' }),
+
+  restoreKeyResult: (id: number): Promise<KeyResult> =>
+    fetchAPI<KeyResult>(`/key-results/${id}/restore`, { method: 'POST' }),
+
+  restoreRoadmap: (id: number): Promise<Roadmap> =>
+    fetchAPI<Roadmap>(`/roadmaps/${id}/restore`, { method: 'POST' }),
+};
+
+export const backupAPI = {
+  export: (): Promise<Backup> => fetchAPI<Backup>('/export'),
+
+  import: (backup: Backup, params?: { strategy?: ImportStrategy; dryRun?: boolean }): Promise<ImportResult> => {
+    const query = new URLSearchParams();
+    if (params?.strategy) query.set('strategy', params.strategy);
+    if (params?.dryRun) query.set('dry_run', 'true');
+    const qs = query.toString();
+    return fetchAPI<ImportResult>(`/import${qs ? `?${qs}` : ''}`, { method: 'POST', body: JSON.stringify(backup) });
+  },
+};
+
+export const shareAPI = {
+  create: (keyResultId: number, data: CreateShareLinkRequest = {}): Promise<ShareLink> =>
+    fetchAPI<ShareLink>(`/key-results/${keyResultId}/roadmap/share`, { method: 'POST', body: JSON.stringify(data) }),
+
+  list: (keyResultId: number): Promise<ShareLink[]> =>
+    fetchAPI<ShareLink[]>(`/key-results/${keyResultId}/roadmap/shares`),
+
+  revoke: (id: number): Promise<ShareLink> =>
+    fetchAPI<ShareLink>(`/share-links/${id}`, { method: 'DELETE' }),
+
+  // A visão pública fica fora de /api/v1
+  view: async (token: string): Promise<SharedRoadmap> => {
+    const base = String(API_URL).replace(/\/api\/v1\/?$/, '');
+    const response = await fetch(`${base}/public/share/${encodeURIComponent(token)}`);
+    if (!response.ok) {
+      const error = await response.json().catch(() => ({ detail: 'Erro desconhecido' }));
+      throw new Error(error.detail || error.title || `HTTP error! status: ${response.status}`);
+    }
+    return response.json();
+  },
+};
@@ -11,6 +11,13 @@ export interface OKR {
   category_id: number;
   category?: Category;
   completion_date?: string;
+  // Presente quando o OKR está arquivado
+  archived_at?: string;
+  status: OKRStatus;
+  // Motivo informado na última mudança de status
+  status_reason?: string;
+  status_changed_at?: string;
+  risk?: RiskAssessment;
   created_at: string;
   updated_at: string;
 }
@@ -20,7 +27,9 @@ export interface KeyResult {
   okr_id: number;
   title: string;
   completed: boolean;
+  difficulty: number;
   expected_completion_date?: string;
+  due_date_computed: boolean;
   created_at: string;
   updated_at: string;
 }
@@ -55,6 +64,13 @@ export interface Roadmap {
   updated_at: string;
 }
 
+export interface Page<T> {
+  items: T[];
+  total: number;
+  limit: number;
+  next_cursor?: string;
+}
+
 export interface CreateCategoryRequest {
   name: string;
 }
@@ -63,6 +79,8 @@ export interface CreateOKRRequest {
   objective: string;
   category_id: number;
   completion_date?: string;
+  // Status inicial (padrão: active)
+  status?: 'draft' | 'active';
 }
 
 export interface UpdateOKRRequest {
@@ -74,12 +92,39 @@ export interface UpdateOKRRequest {
 export interface CreateKeyResultRequest {
   okr_id: number;
   title: string;
+  difficulty?: number;
   expected_completion_date?: string;
 }
 
 export interface UpdateKeyResultRequest {
   title: string;
   completed: boolean;
+  difficulty?: number;
+}
+
+export type ScheduleStrategy = 'even' | 'weighted' | 'front_loaded' | 'back_loaded';
+
+export interface ScheduleRequest {
+  apply?: boolean;
+  strategy?: ScheduleStrategy;
+  include_manual?: boolean;
+}
+
+export interface ScheduleChange {
+  key_result_id: number;
+  title: string;
+  difficulty: number;
+  current_date?: string;
+  proposed_date: string;
+  changed: boolean;
+}
+
+export interface ScheduleResult {
+  okr_id: number;
+  strategy: ScheduleStrategy;
+  completion_date: string;
+  applied: boolean;
+  changes: ScheduleChange[];
 }
 
 export interface EducationalResource {
@@ -123,6 +168,10 @@ export interface TrailActivity {
   url?: string;
   progress?: string;
   completed: boolean;
+  completed_at?: string;
+  estimated_minutes?: number; // interpretado a partir de duration
+  spent_minutes: number;
+  timer_started_at?: string; // presente quando o cronômetro está em andamento
   created_at: string;
   updated_at: string;
 }
@@ -131,6 +180,7 @@ export interface EducationalTrailStep {
   id: number;
   trail_id: number;
   day: number;
+  scheduled_date?: string;
   title: string;
   description: string;
   activities: TrailActivity[];
@@ -152,8 +202,470 @@ export interface EducationalTrail {
   topic: string;
   total_days: number;
   description: string;
+  start_date?: string;
+  overdue_steps: number;
   steps: EducationalTrailStep[];
   resources: Record<string, TrailResource>;
   created_at: string;
   updated_at: string;
 }
+
+// Study Calendar Types
+export type StudyDayOffKind = 'holiday' | 'blackout';
+
+export interface StudyDayOff {
+  id: number;
+  kind: StudyDayOffKind;
+  start_date: string;
+  end_date: string;
+  description: string;
+  created_at: string;
+}
+
+export interface StudySettings {
+  study_weekdays: number[]; // 0 = domingo ... 6 = sábado
+  hours_per_day: number;
+  days_off: StudyDayOff[];
+  updated_at: string;
+}
+
+export interface UpdateStudySettingsRequest {
+  study_weekdays: number[];
+  hours_per_day: number;
+}
+
+export interface CreateStudyDayOffRequest {
+  kind: StudyDayOffKind;
+  start_date: string;
+  end_date?: string;
+  description?: string;
+}
+
+// Calendar Feed Types
+export interface CalendarFeedToken {
+  token: string;
+  feed_url: string; // URL para assinar em Google Calendar/Outlook
+  created_at: string;
+}
+
+// Risk Report Types
+export type RiskLevel = 'on_track' | 'at_risk' | 'off_track';
+
+export interface RiskAssessment {
+  level: RiskLevel;
+  progress: number; // 0 a 1
+  time_elapsed?: number; // 0 a 1
+  due_date?: string;
+  days_remaining?: number;
+  overdue: boolean;
+  reason: string;
+}
+
+export interface TrailRisk extends RiskAssessment {
+  trail_id: number;
+  roadmap_item_id: number;
+  topic: string;
+}
+
+export interface KeyResultRisk extends RiskAssessment {
+  key_result_id: number;
+  title: string;
+  trails: TrailRisk[];
+}
+
+export interface OKRRisk extends RiskAssessment {
+  okr_id: number;
+  objective: string;
+  key_results: KeyResultRisk[];
+}
+
+export interface RiskReport {
+  generated_at: string;
+  summary: {
+    okrs: Record<RiskLevel, number>;
+    key_results: Record<RiskLevel, number>;
+    trails: Record<RiskLevel, number>;
+  };
+  okrs: OKRRisk[];
+}
+
+// Notification Types
+export type NotificationKind = 'trail_today' | 'key_result_due_soon' | 'overdue' | 'weekly_checkin';
+
+export interface Notification {
+  id: number;
+  kind: NotificationKind;
+  title: string;
+  body: string;
+  created_at: string;
+  read_at?: string;
+}
+
+export interface NotificationInbox {
+  items: Notification[];
+  unread_count: number;
+}
+
+export interface NotificationPreferences {
+  inbox_enabled: boolean;
+  email_enabled: boolean;
+  email_address: string;
+  webhook_enabled: boolean;
+  webhook_url: string;
+  kinds: NotificationKind[];
+  quiet_hours_start?: string; // HH:MM
+  quiet_hours_end?: string; // HH:MM
+  weekly_checkin_weekday: number; // 0 = domingo ... 6 = sábado
+  timezone: string; // fuso IANA, ex.: America/Sao_Paulo
+  updated_at: string;
+}
+
+export type UpdateNotificationPreferencesRequest = Omit<NotificationPreferences, 'updated_at'>;
+
+export interface NotificationTestResult {
+  channel: 'inbox' | 'email' | 'webhook';
+  sent: boolean;
+  error?: string;
+}
+
+// Webhook Types
+export type WebhookEventType =
+  | 'okr.created'
+  | 'okr.status_changed'
+  | 'key_result.completed'
+  | 'roadmap.generated'
+  | 'educational_roadmap.generated'
+  | 'educational_trail.generated'
+  | 'trail_activity.checked';
+
+export interface WebhookSubscription {
+  id: number;
+  url: string;
+  events: WebhookEventType[];
+  secret?: string; // presente apenas na criação e na rotação
+  active: boolean;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface CreateWebhookSubscriptionRequest {
+  url: string;
+  events: WebhookEventType[];
+  secret?: string;
+}
+
+export interface UpdateWebhookSubscriptionRequest {
+  url: string;
+  events: WebhookEventType[];
+  active: boolean;
+}
+
+export type WebhookDeliveryStatus = 'pending' | 'succeeded' | 'failed';
+
+export interface WebhookDelivery {
+  id: number;
+  subscription_id: number;
+  event_id: string;
+  event_type: WebhookEventType;
+  status: WebhookDeliveryStatus;
+  attempts: number;
+  next_attempt_at?: string;
+  response_status?: number;
+  response_body?: string;
+  last_error?: string;
+  duration_ms?: number;
+  created_at: string;
+  last_attempt_at?: string;
+  delivered_at?: string;
+}
+
+// Study Time Types
+export interface StudySession {
+  id: number;
+  activity_id: number;
+  source: 'timer' | 'manual';
+  started_at: string;
+  ended_at?: string;
+  minutes: number;
+  note?: string;
+  created_at: string;
+}
+
+export interface CreateStudySessionRequest {
+  minutes: number;
+  date?: string; // YYYY-MM-DD
+  note?: string;
+}
+
+export interface StudyEstimate {
+  activities: number;
+  estimated_minutes: number;
+  actual_minutes: number;
+  ratio?: number; // real / estimado
+}
+
+export interface StudyStats {
+  generated_at: string;
+  streak: {
+    current: number;
+    longest: number;
+    studied_today: boolean;
+    last_study_date?: string;
+  };
+  weekly: {
+    week_start: string;
+    minutes: number;
+    categories: { category_id: number; name: string; minutes: number }[];
+  }[];
+  estimates: StudyEstimate & {
+    by_activity_type: (StudyEstimate & { type: string })[];
+  };
+}
+
+export interface DashboardTotals {
+  okrs: number;
+  completed_okrs: number;
+  in_progress_okrs: number;
+  not_started_okrs: number;
+  key_results: number;
+  completed_key_results: number;
+  overdue_key_results: number;
+  completion_rate: number; // 0 a 1
+  average_progress: number; // 0 a 100
+}
+
+export interface CategoryProgress {
+  category_id: number;
+  name: string;
+  okrs: number;
+  key_results: number;
+  completed_key_results: number;
+  completion_rate: number;
+}
+
+export interface OKRProgress {
+  okr_id: number;
+  key_results: number;
+  completed_key_results: number;
+  progress: number; // 0 a 100
+}
+
+export interface UpcomingDeadline {
+  kind: 'okr' | 'key_result';
+  id: number;
+  okr_id: number;
+  title: string;
+  due_date: string; // YYYY-MM-DD
+  days_remaining: number;
+}
+
+export interface RecentActivity {
+  kind: 'okr_created' | 'key_result_completed' | 'trail_activity_completed' | 'study_session';
+  okr_id: number;
+  title: string;
+  minutes?: number;
+  at: string;
+}
+
+export interface RoadmapProgress {
+  roadmaps: number;
+  items: number;
+  completed_items: number;
+  completion_rate: number;
+}
+
+export interface TrailProgress {
+  trails: number;
+  activities: number;
+  completed_activities: number;
+  completion_rate: number;
+  overdue_steps: number;
+  studied_minutes: number;
+}
+
+export interface Dashboard {
+  generated_at: string;
+  totals: DashboardTotals;
+  categories: CategoryProgress[];
+  okrs: OKRProgress[];
+  upcoming_deadlines: UpcomingDeadline[];
+  recent_activity: RecentActivity[];
+  roadmaps: RoadmapProgress;
+  trails: TrailProgress;
+}
+
+export type AuditEntity =
+  | 'category'
+  | 'okr'
+  | 'key_result'
+  | 'roadmap_item'
+  | 'educational_resource'
+  | 'trail_activity';
+
+export type AuditAction = 'create' | 'update' | 'delete' | 'restore';
+
+export interface AuditEvent {
+  id: number;
+  occurred_at: string;
+  actor: string;
+  request_id?: string;
+  entity: AuditEntity;
+  entity_id: number;
+  action: AuditAction;
+  // Apenas os campos alterados, com os nomes das colunas do banco
+  changes: Record<string, { before: unknown; after: unknown }>;
+}
+
+export type TrashItemType = 'okr' | 'key_result' | 'roadmap';
+
+export interface TrashItem {
+  type: TrashItemType;
+  id: number;
+  title: string;
+  // Objetivo do OKR (Key Results) ou título do Key Result (roadmaps)
+  context?: string;
+  okr_id: number;
+  key_result_id?: number;
+  deleted_at: string;
+  // Ausente quando a lixeira não expira
+  purge_at?: string;
+}
+
+export type OKRStatus = 'draft' | 'active' | 'on_hold' | 'completed' | 'abandoned';
+
+export interface ChangeOKRStatusRequest {
+  status: OKRStatus;
+  // Obrigatório ao abandonar
+  reason?: string;
+}
+
+export interface OKRStatusChange {
+  id: number;
+  okr_id: number;
+  // Ausente no status inicial
+  from?: OKRStatus;
+  to: OKRStatus;
+  reason?: string;
+  changed_at: string;
+}
+
+// Backup completo (GET /export). Os IDs são os da instância de origem
+export interface Backup {
+  format: 'conquista-ai-backup';
+  version: number;
+  exported_at: string;
+  categories: { id: number; name: string }[];
+  okrs: unknown[];
+}
+
+export type ImportStrategy = 'skip' | 'overwrite' | 'duplicate';
+
+export interface ImportResult {
+  dry_run: boolean;
+  strategy: ImportStrategy;
+  // Registros criados por tipo (category, okr, key_result, roadmap, ...)
+  created: Record<string, number>;
+  skipped: number;
+  overwritten: number;
+  // IDs do arquivo → IDs da instância, por tipo (category, okr, key_result)
+  id_map: Record<string, Record<string, number>>;
+}
+
+// Filtros da exportação de Key Results em planilha (GET /okrs/export.csv|xlsx)
+export interface KeyResultExportParams {
+  okr_id?: number;
+  category_id?: number;
+  cycle?: string;
+  completed?: boolean;
+  archived?: boolean | 'all';
+  status?: OKRStatus;
+  q?: string;
+  sort?: string;
+  // Apenas CSV; ";" abre direto no Excel em português
+  delimiter?: ',' | ';';
+}
+
+// Resultado de POST /import/okrs.csv. Linhas com erro (e as demais do mesmo OKR) não são gravadas
+export interface OKRImportRowError {
+  // Linha no arquivo, contando o cabeçalho como linha 1
+  row: number;
+  field?: string;
+  message: string;
+}
+
+export interface OKRImportResult {
+  dry_run: boolean;
+  rows: number;
+  imported_rows: number;
+  okrs_created: number;
+  key_results_created: number;
+  okr_ids: number[];
+  errors: OKRImportRowError[];
+  // Presente com generate_key_results=true: um item por OKR importado sem Key Results
+  key_result_generation?: { okr_id: number; key_results: number; error?: string }[];
+}
+
+// Formatos de exportação de roadmaps e trilhas para impressão
+export type DocumentFormat = 'md' | 'pdf';
+
+// Link público de leitura do roadmap de um Key Result. token e url só vêm na criação
+export interface ShareLink {
+  id: number;
+  key_result_id: number;
+  expires_at?: string;
+  revoked_at?: string;
+  view_count: number;
+  last_viewed_at?: string;
+  created_at: string;
+  token?: string;
+  url?: string;
+}
+
+export interface CreateShareLinkRequest {
+  // 1 a 365; sem o campo o link vale até ser revogado
+  expires_in_days?: number;
+}
+
+// Visão pública (GET /public/share/:token), sem IDs nem dados do OKR
+export interface SharedRoadmap {
+  topic: string;
+  key_result: string;
+  expires_at?: string;
+  categories: {
+    name: string;
+    items: {
+      title: string;
+      completed: boolean;
+      trail?: {
+        topic: string;
+        description?: string;
+        total_days: number;
+        start_date?: string;
+        steps: {
+          day: number;
+          title: string;
+          description?: string;
+          scheduled_date?: string;
+          completed: boolean;
+          activities: {
+            type: string;
+            title: string;
+            description?: string;
+            duration?: string;
+            url?: string;
+            chapters?: string[];
+            completed: boolean;
+          }[];
+        }[];
+        resources: {
+          title: string;
+          description?: string;
+          author?: string;
+          duration?: string;
+          url?: string;
+          chapters?: string[];
+        }[];
+      };
+    }[];
+  }[];
+}